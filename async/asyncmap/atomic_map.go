@@ -49,6 +49,13 @@ func NewAtomicMap[K comparable, V any]() AtomicMap[K, V] {
 	return newAtomicMap[K, V](0)
 }
 
+// NewAtomicMapWithHash returns a new atomic map with a custom hash function.
+func NewAtomicMapWithHash[K comparable, V any](hash HashFunc[K]) AtomicMap[K, V] {
+	m := newAtomicMap[K, V](0)
+	m.hash = hash
+	return m
+}
+
 // internal
 
 const (
@@ -63,6 +70,7 @@ var _ Map[int, int] = (*atomicMap[int, int])(nil)
 
 type atomicMap[K comparable, V any] struct {
 	pool pools.Pool[*atomicMapEntry[K, V]]
+	hash HashFunc[K]
 
 	wmu   sync.RWMutex                         // resize mutex
 	state atomic.Pointer[atomicMapState[K, V]] // current state
@@ -75,7 +83,10 @@ func newAtomicMap[K comparable, V any](size int) *atomicMap[K, V] {
 	num = max(num, atomicMapMinSize)
 
 	s := newAtomicMapState(num, pool)
-	m := &atomicMap[K, V]{pool: pool}
+	m := &atomicMap[K, V]{
+		pool: pool,
+		hash: hashing.Hash[K],
+	}
 	m.state.Store(s)
 	return m
 }
@@ -100,14 +111,14 @@ func (m *atomicMap[K, V]) Clear() {
 
 // Contains returns true if a key exists.
 func (m *atomicMap[K, V]) Contains(key K) bool {
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	s := m.state.Load()
 	return s.contains(h, key)
 }
 
 // Get returns a value by key, or false.
 func (m *atomicMap[K, V]) Get(key K) (V, bool) {
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	s := m.state.Load()
 	return s.get(h, key)
 }
@@ -128,7 +139,7 @@ func (m *atomicMap[K, V]) Delete(key K) (V, bool) {
 	m.wmu.RLock()
 	defer m.wmu.RUnlock()
 
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	s := m.state.Load()
 	return s.delete(h, key)
 }
@@ -172,6 +183,40 @@ func (m *atomicMap[K, V]) Range(fn func(K, V) bool) {
 	s.range_(fn)
 }
 
+// CompareAndSwap sets a new value if the current value equals old, returns true if swapped.
+func (m *atomicMap[K, V]) CompareAndSwap(key K, old V, new V) bool {
+	var swapped bool
+	m.compute(key, computeCAS(old, new, &swapped))
+	return swapped
+}
+
+// CompareAndDelete deletes a key if its value equals old, returns true if deleted.
+func (m *atomicMap[K, V]) CompareAndDelete(key K, old V) bool {
+	var deleted bool
+	m.compute(key, computeCAD(old, &deleted))
+	return deleted
+}
+
+// Compute atomically computes a new key value from the current value and its presence,
+// sets the new value if the function returns true, or deletes the key otherwise.
+func (m *atomicMap[K, V]) Compute(key K, fn func(v V, ok bool) (V, bool)) (V, bool) {
+	return m.compute(key, computeKeep(fn))
+}
+
+// ComputeIfAbsent returns a key value and true, or atomically sets a value
+// computed by the function and false.
+func (m *atomicMap[K, V]) ComputeIfAbsent(key K, fn func() V) (V, bool) {
+	var loaded bool
+	v, _ := m.compute(key, computeAbsent(fn, &loaded))
+	return v, loaded
+}
+
+// Update atomically updates an existing key value, returns the new value and true,
+// or false if the key is absent.
+func (m *atomicMap[K, V]) Update(key K, fn func(v V) V) (V, bool) {
+	return m.compute(key, computeUpdate(fn))
+}
+
 // LockMap exclusively locks the map.
 func (m *atomicMap[K, V]) LockMap() LockedMap[K, V] {
 	m.wmu.Lock()
@@ -185,7 +230,7 @@ func (m *atomicMap[K, V]) getOrSet(key K, value V, resize *bool) (V, bool) {
 	m.wmu.RLock()
 	defer m.wmu.RUnlock()
 
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	s := m.state.Load()
 	v, ok := s.getOrSet(h, key, value)
 
@@ -198,7 +243,7 @@ func (m *atomicMap[K, V]) set(key K, value V, resize *bool) {
 	m.wmu.RLock()
 	defer m.wmu.RUnlock()
 
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	s := m.state.Load()
 	s.set(h, key, value)
 
@@ -210,7 +255,7 @@ func (m *atomicMap[K, V]) setAbsent(key K, value V, resize *bool) bool {
 	m.wmu.RLock()
 	defer m.wmu.RUnlock()
 
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	s := m.state.Load()
 	ok := s.setAbsent(h, key, value)
 
@@ -223,7 +268,7 @@ func (m *atomicMap[K, V]) swap(key K, value V, resize *bool) (V, bool) {
 	m.wmu.RLock()
 	defer m.wmu.RUnlock()
 
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	s := m.state.Load()
 	v, ok := s.swap(h, key, value)
 
@@ -232,6 +277,29 @@ func (m *atomicMap[K, V]) swap(key K, value V, resize *bool) (V, bool) {
 	return v, ok
 }
 
+func (m *atomicMap[K, V]) compute(key K, fn computeFunc[V]) (V, bool) {
+	resize := false
+	v, ok := m._compute(key, fn, &resize)
+
+	if resize {
+		m.resize()
+	}
+	return v, ok
+}
+
+func (m *atomicMap[K, V]) _compute(key K, fn computeFunc[V], resize *bool) (V, bool) {
+	m.wmu.RLock()
+	defer m.wmu.RUnlock()
+
+	h := m.hash(key)
+	s := m.state.Load()
+	v, ok := s.compute(h, key, fn)
+
+	n := s.len()
+	*resize = n >= s.threshold
+	return v, ok
+}
+
 // private
 
 func (m *atomicMap[K, V]) clearLocked() {
@@ -259,7 +327,7 @@ func (m *atomicMap[K, V]) resize() {
 
 	// Copy all items
 	s.rangeLocked(func(k K, v V) bool {
-		h := hashing.Hash(k)
+		h := m.hash(k)
 		next.set(h, k, v)
 		return true
 	})
@@ -151,6 +151,57 @@ func (b *atomicMapBucket[K, V]) delete(key K, pool pools.Pool[*atomicMapEntry[K,
 	return v, ok
 }
 
+// compute calls a function with the current key value, and sets or deletes the value,
+// returns the resulting value and presence, and the number of added items.
+func (b *atomicMapBucket[K, V]) compute(key K, fn computeFunc[V], pool pools.Pool[*atomicMapEntry[K, V]]) (
+	v V, ok bool, delta int) {
+
+	b.wmu.Lock()
+	defer b.wmu.Unlock()
+
+	// Load current entry
+	entry := b.entry.Load()
+
+	// Get current value
+	var cur V
+	var exists bool
+	if entry != nil {
+		cur, exists = entry.get(key)
+	}
+
+	// Compute value
+	v, action := fn(cur, exists)
+	switch action {
+	case computeNone:
+		return cur, exists, 0
+
+	case computeSet:
+		next := newAtomicMapEntry(pool)
+		next.init(entry)
+		next.set(key, v)
+		b.swapEntry(next, entry, pool)
+
+		if !exists {
+			delta = 1
+		}
+		return v, true, delta
+
+	case computeDelete:
+		var zero V
+		if !exists {
+			return zero, false, 0
+		}
+
+		next := newAtomicMapEntry(pool)
+		next.init(entry)
+		next.delete(key)
+		b.swapEntry(next, entry, pool)
+		return zero, false, -1
+	}
+
+	panic("unreachable")
+}
+
 func (b *atomicMapBucket[K, V]) range_(fn func(K, V) bool, pool pools.Pool[*atomicMapEntry[K, V]]) (
 	continue_ bool) {
 
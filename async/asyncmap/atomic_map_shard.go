@@ -101,6 +101,16 @@ func (s *atomicMapShard[K, V]) swap(h uint32, key K, value V) (V, bool) {
 	return v, ok
 }
 
+func (s *atomicMapShard[K, V]) compute(h uint32, key K, fn computeFunc[V]) (V, bool) {
+	resize := false
+	v, ok := s._compute(h, key, fn, &resize)
+
+	if resize {
+		s._resize()
+	}
+	return v, ok
+}
+
 func (s *atomicMapShard[K, V]) range_(fn func(K, V) bool) bool {
 	state := s.state.Load()
 	return state.range_(fn)
@@ -155,6 +165,18 @@ func (s *atomicMapShard[K, V]) _swap(h uint32, key K, value V, resize *bool) (V,
 	return v, ok
 }
 
+func (s *atomicMapShard[K, V]) _compute(h uint32, key K, fn computeFunc[V], resize *bool) (V, bool) {
+	s.wmu.RLock()
+	defer s.wmu.RUnlock()
+
+	state := s.state.Load()
+	v, ok := state.compute(h, key, fn)
+
+	n := state.len()
+	*resize = n >= state.threshold
+	return v, ok
+}
+
 // resize
 
 func (s *atomicMapShard[K, V]) _resize() {
@@ -105,6 +105,15 @@ func (s *atomicMapState[K, V]) swap(h uint32, key K, value V) (v V, _ bool) {
 	return v, ok
 }
 
+func (s *atomicMapState[K, V]) compute(h uint32, key K, fn computeFunc[V]) (V, bool) {
+	b := s.bucket(h)
+	v, ok, delta := b.compute(key, fn, s.pool)
+	if delta != 0 {
+		s.count.Add(int64(delta))
+	}
+	return v, ok
+}
+
 func (s *atomicMapState[K, V]) range_(fn func(K, V) bool) bool {
 	for i := range s.buckets {
 		ok := s.buckets[i].range_(fn, s.pool)
@@ -36,12 +36,20 @@ func NewAtomicShardedMap[K comparable, V any]() AtomicShardedMap[K, V] {
 	return newAtomicShardedMap[K, V](0)
 }
 
+// NewAtomicShardedMapWithHash returns a new atomic sharded map with a custom hash function.
+func NewAtomicShardedMapWithHash[K comparable, V any](hash HashFunc[K]) AtomicShardedMap[K, V] {
+	m := newAtomicShardedMap[K, V](0)
+	m.hash = hash
+	return m
+}
+
 // internal
 
 var _ Map[int, int] = (*atomicShardedMap[int, int])(nil)
 
 type atomicShardedMap[K comparable, V any] struct {
 	pool   pools.Pool[*atomicMapEntry[K, V]]
+	hash   HashFunc[K]
 	shards []atomicMapShard[K, V] // always power of two
 
 	bitWidth int // shard hash bit width
@@ -58,6 +66,7 @@ func newAtomicShardedMap[K comparable, V any](size int) *atomicShardedMap[K, V]
 	// Make map
 	m := &atomicShardedMap[K, V]{
 		pool:     pool,
+		hash:     hashing.Hash[K],
 		shards:   make([]atomicMapShard[K, V], shardNum),
 		bitWidth: bitWidth,
 	}
@@ -139,6 +148,45 @@ func (m *atomicShardedMap[K, V]) Range(fn func(K, V) bool) {
 	}
 }
 
+// CompareAndSwap sets a new value if the current value equals old, returns true if swapped.
+func (m *atomicShardedMap[K, V]) CompareAndSwap(key K, old V, new V) bool {
+	var swapped bool
+	s, h := m.shard(key)
+	s.compute(h, key, computeCAS(old, new, &swapped))
+	return swapped
+}
+
+// CompareAndDelete deletes a key if its value equals old, returns true if deleted.
+func (m *atomicShardedMap[K, V]) CompareAndDelete(key K, old V) bool {
+	var deleted bool
+	s, h := m.shard(key)
+	s.compute(h, key, computeCAD(old, &deleted))
+	return deleted
+}
+
+// Compute atomically computes a new key value from the current value and its presence,
+// sets the new value if the function returns true, or deletes the key otherwise.
+func (m *atomicShardedMap[K, V]) Compute(key K, fn func(v V, ok bool) (V, bool)) (V, bool) {
+	s, h := m.shard(key)
+	return s.compute(h, key, computeKeep(fn))
+}
+
+// ComputeIfAbsent returns a key value and true, or atomically sets a value
+// computed by the function and false.
+func (m *atomicShardedMap[K, V]) ComputeIfAbsent(key K, fn func() V) (V, bool) {
+	var loaded bool
+	s, h := m.shard(key)
+	v, _ := s.compute(h, key, computeAbsent(fn, &loaded))
+	return v, loaded
+}
+
+// Update atomically updates an existing key value, returns the new value and true,
+// or false if the key is absent.
+func (m *atomicShardedMap[K, V]) Update(key K, fn func(v V) V) (V, bool) {
+	s, h := m.shard(key)
+	return s.compute(h, key, computeUpdate(fn))
+}
+
 // LockMap exclusively locks the map.
 func (m *atomicShardedMap[K, V]) LockMap() LockedMap[K, V] {
 	i := 0
@@ -164,7 +212,7 @@ func (m *atomicShardedMap[K, V]) LockMap() LockedMap[K, V] {
 
 // hashes returns two hierarchical key hashes, one for map and one for shard.
 func (m *atomicShardedMap[K, V]) hashes(key K) (uint32, uint32) {
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	h1 := h
 
 	// Right shift to get shard hash
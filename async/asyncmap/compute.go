@@ -0,0 +1,78 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package asyncmap
+
+// computeAction specifies how to modify a key value after computing it.
+type computeAction int
+
+const (
+	computeNone   computeAction = iota // keep the current value
+	computeSet                         // set a new value
+	computeDelete                      // delete the value
+)
+
+// computeFunc receives the current key value and presence, and returns
+// a new value and an action, the function is called atomically per key.
+type computeFunc[V any] func(v V, ok bool) (V, computeAction)
+
+// computeCAS returns a function which sets a new value if the current equals old.
+func computeCAS[V any](old V, new V, swapped *bool) computeFunc[V] {
+	return func(v V, ok bool) (V, computeAction) {
+		*swapped = ok && valuesEqual(v, old)
+		if !*swapped {
+			return v, computeNone
+		}
+		return new, computeSet
+	}
+}
+
+// computeCAD returns a function which deletes a value if it equals old.
+func computeCAD[V any](old V, deleted *bool) computeFunc[V] {
+	return func(v V, ok bool) (V, computeAction) {
+		*deleted = ok && valuesEqual(v, old)
+		if !*deleted {
+			return v, computeNone
+		}
+		return v, computeDelete
+	}
+}
+
+// computeKeep returns a function which sets a new value or deletes the key
+// when the user function returns false.
+func computeKeep[V any](fn func(V, bool) (V, bool)) computeFunc[V] {
+	return func(v V, ok bool) (V, computeAction) {
+		v1, keep := fn(v, ok)
+		if !keep {
+			return v1, computeDelete
+		}
+		return v1, computeSet
+	}
+}
+
+// computeAbsent returns a function which sets a new value only when absent.
+func computeAbsent[V any](fn func() V, loaded *bool) computeFunc[V] {
+	return func(v V, ok bool) (V, computeAction) {
+		*loaded = ok
+		if ok {
+			return v, computeNone
+		}
+		return fn(), computeSet
+	}
+}
+
+// computeUpdate returns a function which updates a value only when present.
+func computeUpdate[V any](fn func(V) V) computeFunc[V] {
+	return func(v V, ok bool) (V, computeAction) {
+		if !ok {
+			return v, computeNone
+		}
+		return fn(v), computeSet
+	}
+}
+
+// valuesEqual compares two values, panics if the values are not comparable.
+func valuesEqual[V any](a V, b V) bool {
+	return any(a) == any(b)
+}
@@ -0,0 +1,320 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package asyncmap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testComputeMaps() map[string]func() Map[int, int] {
+	return map[string]func() Map[int, int]{
+		"AtomicMap":        func() Map[int, int] { return newAtomicMap[int, int](0) },
+		"AtomicShardedMap": func() Map[int, int] { return newAtomicShardedMap[int, int](0) },
+		"ShardedMap":       func() Map[int, int] { return newShardedMap[int, int]() },
+		"SyncMap":          func() Map[int, int] { return newSyncMap[int, int]() },
+	}
+}
+
+func testComputeEach(t *testing.T, fn func(t *testing.T, m Map[int, int])) {
+	for name, newMap := range testComputeMaps() {
+		t.Run(name, func(t *testing.T) {
+			fn(t, newMap())
+		})
+	}
+}
+
+// CompareAndSwap
+
+func TestMap_CompareAndSwap__should_swap_value_if_equal(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		m.Set(1, 10)
+
+		ok := m.CompareAndSwap(1, 10, 20)
+		require.True(t, ok)
+
+		v, _ := m.Get(1)
+		assert.Equal(t, 20, v)
+	})
+}
+
+func TestMap_CompareAndSwap__should_not_swap_value_if_not_equal(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		m.Set(1, 10)
+
+		ok := m.CompareAndSwap(1, 11, 20)
+		require.False(t, ok)
+
+		v, _ := m.Get(1)
+		assert.Equal(t, 10, v)
+	})
+}
+
+func TestMap_CompareAndSwap__should_not_set_absent_key(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		ok := m.CompareAndSwap(1, 0, 20)
+		require.False(t, ok)
+		assert.False(t, m.Contains(1))
+	})
+}
+
+// CompareAndDelete
+
+func TestMap_CompareAndDelete__should_delete_value_if_equal(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		m.Set(1, 10)
+
+		ok := m.CompareAndDelete(1, 11)
+		require.False(t, ok)
+		assert.True(t, m.Contains(1))
+
+		ok = m.CompareAndDelete(1, 10)
+		require.True(t, ok)
+		assert.False(t, m.Contains(1))
+		assert.Equal(t, 0, m.Len())
+	})
+}
+
+// Compute
+
+func TestMap_Compute__should_set_absent_value(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		v, ok := m.Compute(1, func(v int, ok bool) (int, bool) {
+			assert.False(t, ok)
+			return 10, true
+		})
+
+		assert.True(t, ok)
+		assert.Equal(t, 10, v)
+		assert.Equal(t, 1, m.Len())
+	})
+}
+
+func TestMap_Compute__should_replace_existing_value(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		m.Set(1, 10)
+
+		v, ok := m.Compute(1, func(v int, ok bool) (int, bool) {
+			assert.True(t, ok)
+			return v + 1, true
+		})
+
+		assert.True(t, ok)
+		assert.Equal(t, 11, v)
+		assert.Equal(t, 1, m.Len())
+	})
+}
+
+func TestMap_Compute__should_delete_value_when_function_returns_false(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		m.Set(1, 10)
+		m.Set(2, 20)
+
+		v, ok := m.Compute(1, func(v int, ok bool) (int, bool) {
+			return 0, false
+		})
+
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+		assert.False(t, m.Contains(1))
+		assert.Equal(t, 1, m.Len())
+	})
+}
+
+// ComputeIfAbsent
+
+func TestMap_ComputeIfAbsent__should_compute_absent_value(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		v, loaded := m.ComputeIfAbsent(1, func() int { return 10 })
+		assert.False(t, loaded)
+		assert.Equal(t, 10, v)
+
+		v, loaded = m.ComputeIfAbsent(1, func() int {
+			t.Fatal("must not be called")
+			return 0
+		})
+		assert.True(t, loaded)
+		assert.Equal(t, 10, v)
+	})
+}
+
+// Update
+
+func TestMap_Update__should_update_existing_value(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		_, ok := m.Update(1, func(v int) int { return v + 1 })
+		assert.False(t, ok)
+		assert.False(t, m.Contains(1))
+
+		m.Set(1, 10)
+		v, ok := m.Update(1, func(v int) int { return v + 1 })
+		assert.True(t, ok)
+		assert.Equal(t, 11, v)
+	})
+}
+
+// Linearizability
+
+func TestMap_Compute__should_be_atomic_under_concurrency(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		workers := 8
+		increments := 1000
+		keys := 4
+
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := 0; i < increments; i++ {
+					m.Compute(i%keys, func(v int, ok bool) (int, bool) {
+						return v + 1, true
+					})
+				}
+			}()
+		}
+		wg.Wait()
+
+		for k := 0; k < keys; k++ {
+			v, _ := m.Get(k)
+			assert.Equal(t, workers*increments/keys, v)
+		}
+	})
+}
+
+func TestMap_CompareAndSwap__should_be_atomic_under_concurrency(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		workers := 8
+		increments := 1000
+		m.Set(0, 0)
+
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := 0; i < increments; i++ {
+					for {
+						v, _ := m.Get(0)
+						if m.CompareAndSwap(0, v, v+1) {
+							break
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		v, _ := m.Get(0)
+		assert.Equal(t, workers*increments, v)
+	})
+}
+
+func TestMap_ComputeIfAbsent__should_set_value_once_under_concurrency(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		workers := 8
+		keys := 100
+
+		results := make([][]int, workers)
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				results[w] = make([]int, keys)
+				for k := 0; k < keys; k++ {
+					v, _ := m.ComputeIfAbsent(k, func() int { return w })
+					results[w][k] = v
+				}
+			}()
+		}
+		wg.Wait()
+
+		// All workers must observe the same value per key
+		for k := 0; k < keys; k++ {
+			v, _ := m.Get(k)
+			for w := 0; w < workers; w++ {
+				assert.Equal(t, v, results[w][k])
+			}
+		}
+		assert.Equal(t, keys, m.Len())
+	})
+}
+
+func TestMap_Update__should_not_lose_updates_with_concurrent_deletes(t *testing.T) {
+	testComputeEach(t, func(t *testing.T, m Map[int, int]) {
+		workers := 4
+		increments := 1000
+
+		var mu sync.Mutex
+		deleted := 0
+
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers; w++ {
+			wg.Add(2)
+
+			// Increment
+			go func() {
+				defer wg.Done()
+				for i := 0; i < increments; i++ {
+					m.Compute(0, func(v int, ok bool) (int, bool) {
+						return v + 1, true
+					})
+				}
+			}()
+
+			// Delete and count removed increments
+			go func() {
+				defer wg.Done()
+				for i := 0; i < increments/10; i++ {
+					v, ok := m.Get(0)
+					if ok && m.CompareAndDelete(0, v) {
+						mu.Lock()
+						deleted += v
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		v, _ := m.Get(0)
+		assert.Equal(t, workers*increments, v+deleted)
+	})
+}
+
+// Non-comparable
+
+func TestMap_Compute__should_support_non_comparable_values(t *testing.T) {
+	maps := map[string]Map[int, []byte]{
+		"AtomicMap":        newAtomicMap[int, []byte](0),
+		"AtomicShardedMap": newAtomicShardedMap[int, []byte](0),
+		"ShardedMap":       newShardedMap[int, []byte](),
+		"SyncMap":          newSyncMap[int, []byte](),
+	}
+
+	for name, m := range maps {
+		t.Run(name, func(t *testing.T) {
+			v, loaded := m.ComputeIfAbsent(1, func() []byte { return []byte("a") })
+			assert.False(t, loaded)
+			assert.Equal(t, []byte("a"), v)
+
+			v, ok := m.Update(1, func(v []byte) []byte { return append(v, 'b') })
+			require.True(t, ok)
+			assert.Equal(t, []byte("ab"), v)
+
+			v, ok = m.Compute(1, func(v []byte, ok bool) ([]byte, bool) { return nil, false })
+			assert.False(t, ok)
+			assert.Nil(t, v)
+			assert.False(t, m.Contains(1))
+		})
+	}
+}
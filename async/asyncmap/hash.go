@@ -0,0 +1,33 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package asyncmap
+
+import "github.com/basecomplextech/baselibrary/internal/hashing"
+
+// Hasher is implemented by keys which compute their own hashes,
+// such keys are hashed directly by the default hash function.
+//
+// Example:
+//
+//	type ObjectKey struct {
+//		Tenant uint32
+//		Object uint64
+//	}
+//
+//	func (k ObjectKey) Hash32() uint32 {
+//		return k.Tenant ^ uint32(k.Object) ^ uint32(k.Object>>32)
+//	}
+type Hasher = hashing.Hasher
+
+// HashFunc returns a 32-bit key hash.
+type HashFunc[K any] func(key K) uint32
+
+// Hash is the default hash function.
+//
+// The function supports numbers, strings, byte slices, bin types and [Hasher] keys,
+// and panics on other key types. Strings and byte slices are hashed with XXH3.
+func Hash[K any](key K) uint32 {
+	return hashing.Hash(key)
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package asyncmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testHashKey struct {
+	tenant uint32
+	object uint64
+}
+
+func (k testHashKey) Hash32() uint32 {
+	return k.tenant ^ uint32(k.object) ^ uint32(k.object>>32)
+}
+
+type testStructKey struct {
+	tenant uint32
+	object uint64
+}
+
+func testStructHash(k testStructKey) uint32 {
+	return k.tenant*31 + uint32(k.object)
+}
+
+// Hash
+
+func TestHash__should_hash_hasher_keys(t *testing.T) {
+	key := testHashKey{tenant: 1, object: 2}
+
+	h := Hash(key)
+	assert.Equal(t, key.Hash32(), h)
+}
+
+func TestHash__should_panic_on_unsupported_keys(t *testing.T) {
+	assert.Panics(t, func() {
+		Hash(testStructKey{})
+	})
+}
+
+func TestHash__should_distribute_similar_strings(t *testing.T) {
+	buckets := 16
+	counts := make([]int, buckets)
+
+	n := 16_000
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		h := Hash(key)
+		counts[int(h)%buckets]++
+	}
+
+	for _, c := range counts {
+		assert.InDelta(t, n/buckets, c, float64(n/buckets)/5)
+	}
+}
+
+// Maps
+
+func TestMap__should_support_hasher_keys(t *testing.T) {
+	maps := []Map[testHashKey, int]{
+		NewAtomicMap[testHashKey, int](),
+		NewAtomicShardedMap[testHashKey, int](),
+		NewShardedMap[testHashKey, int](),
+	}
+
+	for _, m := range maps {
+		for i := 0; i < 100; i++ {
+			m.Set(testHashKey{tenant: uint32(i % 3), object: uint64(i)}, i)
+		}
+
+		for i := 0; i < 100; i++ {
+			v, ok := m.Get(testHashKey{tenant: uint32(i % 3), object: uint64(i)})
+			require.True(t, ok)
+			assert.Equal(t, i, v)
+		}
+		assert.Equal(t, 100, m.Len())
+	}
+}
+
+func TestMap__should_use_custom_hash_function(t *testing.T) {
+	maps := []Map[testStructKey, int]{
+		NewAtomicMapWithHash[testStructKey, int](testStructHash),
+		NewAtomicShardedMapWithHash[testStructKey, int](testStructHash),
+		NewShardedMapWithHash[testStructKey, int](testStructHash),
+	}
+
+	for _, m := range maps {
+		for i := 0; i < 100; i++ {
+			m.Set(testStructKey{tenant: uint32(i % 3), object: uint64(i)}, i)
+		}
+
+		for i := 0; i < 100; i++ {
+			v, ok := m.Get(testStructKey{tenant: uint32(i % 3), object: uint64(i)})
+			require.True(t, ok)
+			assert.Equal(t, i, v)
+		}
+		assert.Equal(t, 100, m.Len())
+	}
+}
+
+func TestLockMap__should_use_custom_hash_function(t *testing.T) {
+	m := NewLockMapWithHash[testStructKey](testStructHash)
+	key := testStructKey{tenant: 1, object: 2}
+
+	lock, st := m.Lock(async.NoContext(), key)
+	require.True(t, st.OK())
+	assert.True(t, m.Contains(key))
+
+	lock.Free()
+	assert.False(t, m.Contains(key))
+}
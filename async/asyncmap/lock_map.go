@@ -5,7 +5,9 @@
 package asyncmap
 
 import (
+	"cmp"
 	"runtime"
+	"slices"
 	"unsafe"
 
 	"github.com/basecomplextech/baselibrary/async"
@@ -47,6 +49,23 @@ type LockMap[K comparable] interface {
 	//	defer lock.Unlock()
 	Get(key K) KeyLock
 
+	// GetRW returns a shared/exclusive key lock, the lock must be freed after use.
+	//
+	// Usage:
+	//
+	//	m := NewLockMap[int]()
+	//
+	//	lock := m.GetRW(123)
+	//	defer lock.Free()
+	//
+	//	select {
+	//	case <-lock.RLock():
+	//	case <-ctx.Wait():
+	//		return ctx.Status()
+	//	}
+	//	defer lock.RUnlock()
+	GetRW(key K) RWKeyLock
+
 	// Contains returns true if the key is present.
 	//
 	// Usually it means that the key is locked, but it is not guaranteed.
@@ -66,6 +85,30 @@ type LockMap[K comparable] interface {
 	//	defer lock.Free()
 	Lock(ctx async.Context, key K) (LockedKey, status.Status)
 
+	// RLock returns a key locked in shared mode, the key must be freed after use.
+	RLock(ctx async.Context, key K) (LockedKey, status.Status)
+
+	// LockKeys locks multiple keys in a deterministic order, and returns a locked
+	// key which unlocks all keys when freed. Duplicate keys are locked once.
+	//
+	// The order is the same for concurrent callers, so transactions which lock
+	// overlapping keys do not deadlock.
+	//
+	// Usage:
+	//
+	//	m := NewLockMap[int]()
+	//
+	//	lock, st := m.LockKeys(ctx, 1, 2, 3)
+	//	if !st.OK() {
+	//		return st
+	//	}
+	//	defer lock.Free()
+	LockKeys(ctx async.Context, keys ...K) (LockedKey, status.Status)
+
+	// RLockKeys locks multiple keys in shared mode in a deterministic order,
+	// see LockKeys.
+	RLockKeys(ctx async.Context, keys ...K) (LockedKey, status.Status)
+
 	// LockMap locks the map itself, internally it locks all buckets.
 	//
 	// Usage:
@@ -87,11 +130,19 @@ func NewLockMap[K comparable]() LockMap[K] {
 	return newLockMap[K]()
 }
 
+// NewLockMapWithHash returns a new lock map with a custom hash function.
+func NewLockMapWithHash[K comparable](hash HashFunc[K]) LockMap[K] {
+	m := newLockMap[K]()
+	m.hash = hash
+	return m
+}
+
 // internal
 
 var _ LockMap[any] = (*lockMap[any])(nil)
 
 type lockMap[K comparable] struct {
+	hash    HashFunc[K]
 	pool    pools.Pool[*lockMapItem[K]]
 	buckets []lockMapBucket[K]
 }
@@ -109,6 +160,7 @@ func newLockMap[K comparable]() *lockMap[K] {
 
 	// Make map
 	m := &lockMap[K]{
+		hash:    hashing.Hash[K],
 		pool:    pool,
 		buckets: make([]lockMapBucket[K], bucketNum),
 	}
@@ -130,6 +182,16 @@ func (m *lockMap[K]) Get(key K) KeyLock {
 	return newLockMapKeyLock(item)
 }
 
+// GetRW returns a shared/exclusive key lock, the lock must be freed after use.
+func (m *lockMap[K]) GetRW(key K) RWKeyLock {
+	// Get lock item
+	b := m.bucket(key)
+	item := b.get(key)
+
+	// Return key lock
+	return newLockMapKeyLock(item)
+}
+
 // Contains returns true if the key is present.
 //
 // Usually it means that the key is locked, but it is not guaranteed.
@@ -141,35 +203,39 @@ func (m *lockMap[K]) Contains(key K) bool {
 
 // Lock returns a locked key, the key must be freed after use.
 func (m *lockMap[K]) Lock(ctx async.Context, key K) (LockedKey, status.Status) {
-	// Get lock item
 	b := m.bucket(key)
 	item := b.get(key)
 
-	// Release if not locked
-	done := false
-	defer func() {
-		if !done {
-			item.release()
-		}
-	}()
+	st := item.lockCtx(ctx, false)
+	if !st.OK() {
+		item.release()
+		return nil, st
+	}
+	return newLockMapLockedKey(item, false), status.OK
+}
 
-	// Try lock
-	select {
-	case <-item.lock:
-	default:
-		// Lock or wait
-		// Context channel is lazily allocated, so try to postpone calling wait.
-		select {
-		case <-item.lock:
-		case <-ctx.Wait():
-			return nil, ctx.Status()
-		}
+// RLock returns a key locked in shared mode, the key must be freed after use.
+func (m *lockMap[K]) RLock(ctx async.Context, key K) (LockedKey, status.Status) {
+	b := m.bucket(key)
+	item := b.get(key)
+
+	st := item.lockCtx(ctx, true)
+	if !st.OK() {
+		item.release()
+		return nil, st
 	}
+	return newLockMapLockedKey(item, true), status.OK
+}
 
-	// Return locked key
-	k := newLockMapLockedKey(item)
-	done = true
-	return k, status.OK
+// LockKeys locks multiple keys in a deterministic order, and returns a locked
+// key which unlocks all keys when freed. Duplicate keys are locked once.
+func (m *lockMap[K]) LockKeys(ctx async.Context, keys ...K) (LockedKey, status.Status) {
+	return m.lockKeys(ctx, keys, false)
+}
+
+// RLockKeys locks multiple keys in shared mode in a deterministic order.
+func (m *lockMap[K]) RLockKeys(ctx async.Context, keys ...K) (LockedKey, status.Status) {
+	return m.lockKeys(ctx, keys, true)
 }
 
 // LockMap locks the map itself, internally it locks all buckets.
@@ -195,6 +261,54 @@ func (m *lockMap[K]) LockMap() LockedLockMap[K] {
 
 // internal
 
+// lockKeys retains items, sorts them by addresses, and locks them in this order.
+// Items are retained during locking, so concurrent callers get the same items
+// and the same order.
+func (m *lockMap[K]) lockKeys(ctx async.Context, keys []K, read bool) (LockedKey, status.Status) {
+	// Retain items
+	items := make([]*lockMapItem[K], 0, len(keys))
+	for _, key := range keys {
+		b := m.bucket(key)
+		item := b.get(key)
+		items = append(items, item)
+	}
+
+	// Sort items, release duplicates
+	slices.SortFunc(items, func(a, b *lockMapItem[K]) int {
+		return cmp.Compare(uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(b)))
+	})
+	n := 0
+	for i, item := range items {
+		if i > 0 && item == items[n-1] {
+			item.release()
+			continue
+		}
+		items[n] = item
+		n++
+	}
+	clear(items[n:])
+	items = items[:n]
+
+	// Lock items
+	for i, item := range items {
+		st := item.lockCtx(ctx, read)
+		if st.OK() {
+			continue
+		}
+
+		// Unlock acquired items, release all
+		for j := i - 1; j >= 0; j-- {
+			items[j].unlock(read)
+		}
+		for _, item := range items {
+			item.release()
+		}
+		return nil, st
+	}
+
+	return newLockMapLockedKeys(items, read), status.OK
+}
+
 // unlockMap unlocks the map itself, internally it unlocks all buckets.
 func (m *lockMap[K]) unlockMap() {
 	for i := range m.buckets {
@@ -204,7 +318,7 @@ func (m *lockMap[K]) unlockMap() {
 }
 
 func (m *lockMap[K]) bucket(key K) *lockMapBucket[K] {
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	i := int(h) % len(m.buckets)
 	return &m.buckets[i]
 }
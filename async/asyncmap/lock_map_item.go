@@ -4,15 +4,35 @@
 
 package asyncmap
 
-import "github.com/basecomplextech/baselibrary/pools"
-
+import (
+	"sync"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/collect/chans"
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/status"
+)
+
+// lockMapItem is a shared/exclusive key lock which prefers writers.
+//
+// Writers register themselves before waiting, and receive a single write token
+// from the lock channel, the token is granted when there are no readers. Readers
+// are admitted immediately when there are no writers, otherwise they wait for
+// a gate channel which is closed when the last writer leaves.
 type lockMapItem[K comparable] struct {
 	b *lockMapBucket[K]
 
-	refs int32
-	lock chan struct{}
+	refs int32         // guarded by bucket mutex
+	lock chan struct{} // write token
 
 	key K
+
+	mu      sync.Mutex
+	readers int32         // admitted readers
+	waiting int32         // readers waiting for gate
+	writers int32         // pending writers, including the one holding the token
+	granted bool          // write token is granted
+	gate    chan struct{} // reader gate, nil when readers are admitted
 }
 
 func newLockMapItem[K comparable](b *lockMapBucket[K], key K) *lockMapItem[K] {
@@ -30,18 +50,152 @@ func newLockMapItem[K comparable](b *lockMapBucket[K], key K) *lockMapItem[K] {
 func makeLockMapItem[K comparable]() *lockMapItem[K] {
 	m := &lockMapItem[K]{}
 	m.lock = make(chan struct{}, 1)
-	m.lock <- struct{}{}
 	return m
 }
 
-func (m *lockMapItem[K]) unlock() {
+// write
+
+// lockWrite registers a pending writer, the writer must receive from the lock channel,
+// and then call unlockWrite, or cancelWrite if it does not receive.
+func (m *lockMapItem[K]) lockWrite() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.writers++
+	m.grant()
+}
+
+// unlockWrite unlocks the write lock, grants it to the next writer or admits readers.
+func (m *lockMapItem[K]) unlockWrite() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.granted || len(m.lock) > 0 {
+		panic("unlock of unlocked key lock")
+	}
+
+	m.granted = false
+	m.writers--
+
+	if m.writers > 0 {
+		m.grant()
+		return
+	}
+	m.admit()
+}
+
+// cancelWrite unregisters a pending writer which has not acquired the lock.
+func (m *lockMapItem[K]) cancelWrite() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.writers--
+	if m.writers > 0 {
+		return
+	}
+
+	// Take back unreceived token
+	if m.granted {
+		select {
+		case <-m.lock:
+		default:
+			panic("cancel of acquired key lock")
+		}
+		m.granted = false
+	}
+
+	m.admit()
+}
+
+// read
+
+// lockRead returns a channel which is closed when the reader is admitted, and a gate
+// when the reader waits, the reader must call unlockRead with the gate.
+func (m *lockMapItem[K]) lockRead() (<-chan struct{}, chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writers == 0 {
+		m.readers++
+		return chans.Closed(), nil
+	}
+
+	if m.gate == nil {
+		m.gate = make(chan struct{})
+	}
+	m.waiting++
+	return m.gate, m.gate
+}
+
+// unlockRead unlocks the read lock, or cancels waiting, grants the lock to writers
+// when there are no more readers.
+func (m *lockMapItem[K]) unlockRead(gate chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Cancel waiting
+	if gate != nil && gate == m.gate {
+		m.waiting--
+		return
+	}
+
+	if m.readers <= 0 {
+		panic("unlock of unlocked key read lock")
+	}
+
+	m.readers--
+	m.grant()
+}
+
+// lockCtx acquires a read or write lock, or returns the context status.
+func (m *lockMapItem[K]) lockCtx(ctx async.Context, read bool) status.Status {
+	// Read lock
+	if read {
+		ch, gate := m.lockRead()
+		select {
+		case <-ch:
+			return status.OK
+		default:
+		}
+
+		select {
+		case <-ch:
+			return status.OK
+		case <-ctx.Wait():
+			m.unlockRead(gate)
+			return ctx.Status()
+		}
+	}
+
+	// Write lock
+	// Context channel is lazily allocated, so try to postpone calling wait.
+	m.lockWrite()
 	select {
-	case m.lock <- struct{}{}:
+	case <-m.lock:
+		return status.OK
 	default:
-		panic("unlock of unlocked key lock")
 	}
+
+	select {
+	case <-m.lock:
+		return status.OK
+	case <-ctx.Wait():
+		m.cancelWrite()
+		return ctx.Status()
+	}
+}
+
+// unlock unlocks an acquired read or write lock.
+func (m *lockMapItem[K]) unlock(read bool) {
+	if read {
+		m.unlockRead(nil)
+		return
+	}
+	m.unlockWrite()
 }
 
+// release
+
 func (m *lockMapItem[K]) release() {
 	deleted := m.b.release(m)
 	if !deleted {
@@ -55,10 +209,32 @@ func (m *lockMapItem[K]) release() {
 
 // private
 
+// grant grants the write token to pending writers when there are no readers.
+func (m *lockMapItem[K]) grant() {
+	if m.writers == 0 || m.readers > 0 || m.granted {
+		return
+	}
+
+	m.granted = true
+	m.lock <- struct{}{}
+}
+
+// admit admits waiting readers.
+func (m *lockMapItem[K]) admit() {
+	if m.gate == nil {
+		return
+	}
+
+	close(m.gate)
+	m.readers += m.waiting
+	m.waiting = 0
+	m.gate = nil
+}
+
 func (m *lockMapItem[K]) reset() {
 	lock := m.lock
 	select {
-	case m.lock <- struct{}{}:
+	case <-m.lock:
 	default:
 	}
 
@@ -4,6 +4,8 @@
 
 package asyncmap
 
+import "github.com/basecomplextech/baselibrary/collect/chans"
+
 // KeyLock is a single lock for a key, the lock must be freed after use.
 type KeyLock interface {
 	// Lock returns a channel receiving from which locks the key.
@@ -13,15 +15,48 @@ type KeyLock interface {
 	Unlock()
 
 	// Free frees the acquired key.
+	//
+	// Free cancels waiting for the lock if the lock has been requested but not received,
+	// an acquired lock must be unlocked before free.
 	Free()
 }
 
+// RWKeyLock is a shared/exclusive lock for a key, the lock must be freed after use.
+//
+// The lock prefers writers, new readers wait when there are pending writers.
+// A lock can be held only in one mode at a time.
+//
+// Usage:
+//
+//	lock := m.GetRW(123)
+//	defer lock.Free()
+//
+//	select {
+//	case <-lock.RLock():
+//	case <-ctx.Wait():
+//		return ctx.Status()
+//	}
+//	defer lock.RUnlock()
+type RWKeyLock interface {
+	KeyLock
+
+	// RLock returns a channel receiving from which locks the key in shared mode.
+	RLock() <-chan struct{}
+
+	// RUnlock unlocks the shared key lock.
+	RUnlock()
+}
+
 // internal
 
-var _ KeyLock = &lockMapKeyLock[any]{}
+var _ RWKeyLock = &lockMapKeyLock[any]{}
 
 type lockMapKeyLock[K comparable] struct {
 	item *lockMapItem[K]
+
+	writing bool          // write lock requested
+	reading bool          // read lock requested
+	gate    chan struct{} // read gate if waiting
 }
 
 func newLockMapKeyLock[K comparable](item *lockMapItem[K]) *lockMapKeyLock[K] {
@@ -30,12 +65,55 @@ func newLockMapKeyLock[K comparable](item *lockMapItem[K]) *lockMapKeyLock[K] {
 
 // Lock returns a channel receiving from which locks the key.
 func (l *lockMapKeyLock[K]) Lock() <-chan struct{} {
+	if l.reading {
+		panic("lock of read locked key lock")
+	}
+
+	if !l.writing {
+		l.writing = true
+		l.item.lockWrite()
+	}
 	return l.item.lock
 }
 
 // Unlock unlocks the key lock.
 func (l *lockMapKeyLock[K]) Unlock() {
-	l.item.unlock()
+	if !l.writing {
+		panic("unlock of unlocked key lock")
+	}
+
+	l.item.unlockWrite()
+	l.writing = false
+}
+
+// RLock returns a channel receiving from which locks the key in shared mode.
+func (l *lockMapKeyLock[K]) RLock() <-chan struct{} {
+	if l.writing {
+		panic("read lock of write locked key lock")
+	}
+
+	if !l.reading {
+		l.reading = true
+		ch, gate := l.item.lockRead()
+		l.gate = gate
+		return ch
+	}
+
+	if l.gate == nil {
+		return chans.Closed()
+	}
+	return l.gate
+}
+
+// RUnlock unlocks the shared key lock.
+func (l *lockMapKeyLock[K]) RUnlock() {
+	if !l.reading {
+		panic("unlock of unlocked key read lock")
+	}
+
+	l.item.unlockRead(l.gate)
+	l.reading = false
+	l.gate = nil
 }
 
 // Free frees the acquired key.
@@ -47,5 +125,13 @@ func (l *lockMapKeyLock[K]) Free() {
 	m := l.item
 	l.item = nil
 
+	// Cancel pending requests
+	switch {
+	case l.writing:
+		m.cancelWrite()
+	case l.reading:
+		m.unlockRead(l.gate)
+	}
+
 	m.release()
 }
@@ -16,10 +16,11 @@ var _ LockedKey = &lockMapLockedKey[any]{}
 
 type lockMapLockedKey[K comparable] struct {
 	item *lockMapItem[K]
+	read bool // shared lock
 }
 
-func newLockMapLockedKey[K comparable](item *lockMapItem[K]) *lockMapLockedKey[K] {
-	return &lockMapLockedKey[K]{item: item}
+func newLockMapLockedKey[K comparable](item *lockMapItem[K], read bool) *lockMapLockedKey[K] {
+	return &lockMapLockedKey[K]{item: item, read: read}
 }
 
 func (l *lockMapLockedKey[K]) Free() {
@@ -30,6 +31,35 @@ func (l *lockMapLockedKey[K]) Free() {
 	m := l.item
 	l.item = nil
 
-	m.unlock()
+	m.unlock(l.read)
 	m.release()
 }
+
+// keys
+
+var _ LockedKey = &lockMapLockedKeys[any]{}
+
+type lockMapLockedKeys[K comparable] struct {
+	items []*lockMapItem[K] // in lock order
+	read  bool              // shared locks
+}
+
+func newLockMapLockedKeys[K comparable](items []*lockMapItem[K], read bool) *lockMapLockedKeys[K] {
+	return &lockMapLockedKeys[K]{items: items, read: read}
+}
+
+func (l *lockMapLockedKeys[K]) Free() {
+	if l.items == nil {
+		panic("free of freed locked keys")
+	}
+
+	items := l.items
+	l.items = nil
+
+	// Unlock in reverse order
+	for i := len(items) - 1; i >= 0; i-- {
+		m := items[i]
+		m.unlock(l.read)
+		m.release()
+	}
+}
@@ -116,3 +116,229 @@ func TestKeyLock_Free__should_release_delete_key_lock(t *testing.T) {
 	_, ok := b.getNoRetain(key)
 	assert.False(t, ok)
 }
+
+// RLock
+
+func TestLockMap_RLock__should_allow_concurrent_readers(t *testing.T) {
+	m := newLockMap[int]()
+	ctx := async.NoContext()
+
+	lock0, st := m.RLock(ctx, 123)
+	require.True(t, st.OK())
+	defer lock0.Free()
+
+	lock1, st := m.RLock(ctx, 123)
+	require.True(t, st.OK())
+	defer lock1.Free()
+}
+
+func TestLockMap_RLock__should_wait_for_writer(t *testing.T) {
+	m := newLockMap[int]()
+	ctx := async.NoContext()
+
+	lock, st := m.Lock(ctx, 123)
+	require.True(t, st.OK())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		rlock, st := m.RLock(ctx, 123)
+		require.True(t, st.OK())
+		rlock.Free()
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("reader acquired write locked key")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	lock.Free()
+	<-done
+}
+
+func TestLockMap_Lock__should_wait_for_readers(t *testing.T) {
+	m := newLockMap[int]()
+	ctx := async.NoContext()
+
+	rlock, st := m.RLock(ctx, 123)
+	require.True(t, st.OK())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		lock, st := m.Lock(ctx, 123)
+		require.True(t, st.OK())
+		lock.Free()
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("writer acquired read locked key")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	rlock.Free()
+	<-done
+}
+
+func TestLockMap_RLock__should_prefer_pending_writers(t *testing.T) {
+	m := newLockMap[int]()
+
+	rlock := m.GetRW(123)
+	defer rlock.Free()
+	<-rlock.RLock()
+
+	// Pending writer
+	wlock := m.GetRW(123)
+	defer wlock.Free()
+	wch := wlock.Lock()
+
+	// New reader waits
+	rlock1 := m.GetRW(123)
+	defer rlock1.Free()
+	rch := rlock1.RLock()
+
+	select {
+	case <-rch:
+		t.Fatal("reader acquired key with pending writer")
+	default:
+	}
+
+	// Writer acquires after reader
+	rlock.RUnlock()
+	<-wch
+
+	select {
+	case <-rch:
+		t.Fatal("reader acquired write locked key")
+	default:
+	}
+
+	// Reader acquires after writer
+	wlock.Unlock()
+	<-rch
+	rlock1.RUnlock()
+}
+
+func TestLockMap_RLock__should_admit_readers_when_pending_writer_cancelled(t *testing.T) {
+	m := newLockMap[int]()
+
+	rlock := m.GetRW(123)
+	defer rlock.Free()
+	<-rlock.RLock()
+	defer rlock.RUnlock()
+
+	// Cancel pending writer
+	ctx := async.NewContext()
+	defer ctx.Free()
+	ctx.Cancel()
+
+	_, st := m.Lock(ctx, 123)
+	assert.False(t, st.OK())
+
+	// New reader acquires
+	lock, st := m.RLock(async.NoContext(), 123)
+	require.True(t, st.OK())
+	lock.Free()
+}
+
+func TestRWKeyLock_Free__should_cancel_waiting_reader(t *testing.T) {
+	m := newLockMap[int]()
+
+	wlock := m.GetRW(123)
+	<-wlock.Lock()
+
+	rlock := m.GetRW(123)
+	rch := rlock.RLock()
+	rlock.Free()
+
+	wlock.Unlock()
+	wlock.Free()
+
+	select {
+	case <-rch:
+	default:
+		t.Fatal("gate not opened")
+	}
+
+	b := m.bucket(123)
+	_, ok := b.getNoRetain(123)
+	assert.False(t, ok)
+}
+
+// LockKeys
+
+func TestLockMap_LockKeys__should_lock_keys_without_deadlocks(t *testing.T) {
+	m := newLockMap[int]()
+	ctx := async.NoContext()
+
+	n := 1000
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < n; i++ {
+			lock, st := m.LockKeys(ctx, 1, 2, 3)
+			require.True(t, st.OK())
+			lock.Free()
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		lock, st := m.LockKeys(ctx, 3, 2, 1)
+		require.True(t, st.OK())
+		lock.Free()
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlock")
+	}
+}
+
+func TestLockMap_LockKeys__should_lock_duplicate_keys_once(t *testing.T) {
+	m := newLockMap[int]()
+	ctx := async.NoContext()
+
+	lock, st := m.LockKeys(ctx, 1, 2, 1)
+	require.True(t, st.OK())
+	lock.Free()
+
+	assert.False(t, m.Contains(1))
+	assert.False(t, m.Contains(2))
+}
+
+func TestLockMap_LockKeys__should_unlock_acquired_keys_on_cancel(t *testing.T) {
+	m := newLockMap[int]()
+
+	lock, st := m.Lock(async.NoContext(), 2)
+	require.True(t, st.OK())
+
+	ctx := async.TimeoutContext(10 * time.Millisecond)
+	defer ctx.Free()
+
+	_, st = m.LockKeys(ctx, 1, 2, 3)
+	assert.False(t, st.OK())
+	lock.Free()
+
+	assert.False(t, m.Contains(1))
+	assert.False(t, m.Contains(2))
+	assert.False(t, m.Contains(3))
+}
+
+func TestLockMap_RLockKeys__should_allow_concurrent_readers(t *testing.T) {
+	m := newLockMap[int]()
+	ctx := async.NoContext()
+
+	lock0, st := m.RLockKeys(ctx, 1, 2)
+	require.True(t, st.OK())
+	defer lock0.Free()
+
+	lock1, st := m.RLockKeys(ctx, 2, 3)
+	require.True(t, st.OK())
+	defer lock1.Free()
+}
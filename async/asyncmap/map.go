@@ -54,6 +54,41 @@ type Map[K comparable, V any] interface {
 	// Range iterates over all key-value pairs.
 	// The iteration stops if the function returns false.
 	Range(fn func(K, V) bool)
+
+	// Atomic
+
+	// CompareAndSwap sets a new value if the current value equals old, returns true if swapped.
+	// The method panics if the values are not comparable.
+	CompareAndSwap(key K, old V, new V) bool
+
+	// CompareAndDelete deletes a key if its value equals old, returns true if deleted.
+	// The method panics if the values are not comparable.
+	CompareAndDelete(key K, old V) bool
+
+	// Compute atomically computes a new key value from the current value and its presence,
+	// sets the new value if the function returns true, or deletes the key otherwise.
+	// Returns the new value and true, or false if deleted.
+	//
+	// The function must not access the map. It may be called multiple times by [SyncMap].
+	//
+	// Example:
+	//
+	//	m.Compute(key, func(v int, ok bool) (int, bool) {
+	//		return v + 1, true
+	//	})
+	Compute(key K, fn func(v V, ok bool) (V, bool)) (V, bool)
+
+	// ComputeIfAbsent returns a key value and true, or atomically sets a value
+	// computed by the function and false.
+	//
+	// The function must not access the map. It may be called multiple times by [SyncMap].
+	ComputeIfAbsent(key K, fn func() V) (V, bool)
+
+	// Update atomically updates an existing key value, returns the new value and true,
+	// or false if the key is absent.
+	//
+	// The function must not access the map. It may be called multiple times by [SyncMap].
+	Update(key K, fn func(v V) V) (V, bool)
 }
 
 // LockedMap provides an exclusive access to an async map.
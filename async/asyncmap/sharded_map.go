@@ -35,11 +35,19 @@ func NewShardedMap[K comparable, V any]() Map[K, V] {
 	return newShardedMap[K, V]()
 }
 
+// NewShardedMapWithHash returns a new sharded map with a custom hash function.
+func NewShardedMapWithHash[K comparable, V any](hash HashFunc[K]) Map[K, V] {
+	m := newShardedMap[K, V]()
+	m.hash = hash
+	return m
+}
+
 // internal
 
 var _ Map[int, int] = &shardedMap[int, int]{}
 
 type shardedMap[K comparable, V any] struct {
+	hash   HashFunc[K]
 	shards []shardedMapShard[K, V]
 }
 
@@ -47,6 +55,7 @@ func newShardedMap[K comparable, V any]() *shardedMap[K, V] {
 	cpus := runtime.NumCPU()
 
 	return &shardedMap[K, V]{
+		hash:   hashing.Hash[K],
 		shards: make([]shardedMapShard[K, V], cpus),
 	}
 }
@@ -129,10 +138,49 @@ func (m *shardedMap[K, V]) Range(fn func(K, V) bool) {
 	}
 }
 
+// CompareAndSwap sets a new value if the current value equals old, returns true if swapped.
+func (m *shardedMap[K, V]) CompareAndSwap(key K, old V, new V) bool {
+	var swapped bool
+	s := m.shard(key)
+	s.compute(key, computeCAS(old, new, &swapped))
+	return swapped
+}
+
+// CompareAndDelete deletes a key if its value equals old, returns true if deleted.
+func (m *shardedMap[K, V]) CompareAndDelete(key K, old V) bool {
+	var deleted bool
+	s := m.shard(key)
+	s.compute(key, computeCAD(old, &deleted))
+	return deleted
+}
+
+// Compute atomically computes a new key value from the current value and its presence,
+// sets the new value if the function returns true, or deletes the key otherwise.
+func (m *shardedMap[K, V]) Compute(key K, fn func(v V, ok bool) (V, bool)) (V, bool) {
+	s := m.shard(key)
+	return s.compute(key, computeKeep(fn))
+}
+
+// ComputeIfAbsent returns a key value and true, or atomically sets a value
+// computed by the function and false.
+func (m *shardedMap[K, V]) ComputeIfAbsent(key K, fn func() V) (V, bool) {
+	var loaded bool
+	s := m.shard(key)
+	v, _ := s.compute(key, computeAbsent(fn, &loaded))
+	return v, loaded
+}
+
+// Update atomically updates an existing key value, returns the new value and true,
+// or false if the key is absent.
+func (m *shardedMap[K, V]) Update(key K, fn func(v V) V) (V, bool) {
+	s := m.shard(key)
+	return s.compute(key, computeUpdate(fn))
+}
+
 // private
 
 func (m *shardedMap[K, V]) shard(key K) *shardedMapShard[K, V] {
-	h := hashing.Hash(key)
+	h := m.hash(key)
 	i := int(h) % len(m.shards)
 	return &m.shards[i]
 }
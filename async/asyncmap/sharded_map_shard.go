@@ -146,20 +146,31 @@ func (s *shardedMapShard[K, V]) swap(key K, value V) (v V, _ bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if m, ok := s.entry.Unwrap(); ok {
-		if m.key == key {
-			v1 := m.value
-			m.value = value
-			return v1, true
-		}
-	}
-	if more, ok := s.more.Unwrap(); ok {
-		if v1, ok := more[key]; ok {
-			more[key] = value
-			return v1, true
-		}
+	v, ok := s._get(key)
+	s._set(key, value)
+	return v, ok
+}
+
+func (s *shardedMapShard[K, V]) compute(key K, fn computeFunc[V]) (v V, _ bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, ok := s._get(key)
+	v, action := fn(cur, ok)
+
+	switch action {
+	case computeNone:
+		return cur, ok
+	case computeSet:
+		s._set(key, v)
+		return v, true
+	case computeDelete:
+		var zero V
+		s._delete(key)
+		return zero, false
 	}
-	return v, false
+
+	panic("unreachable")
 }
 
 func (s *shardedMapShard[K, V]) range_(fn func(K, V) bool) bool {
@@ -196,14 +207,57 @@ func (s *shardedMapShard[K, V]) _contains(key K) bool {
 	return false
 }
 
+func (s *shardedMapShard[K, V]) _get(key K) (v V, _ bool) {
+	if m, ok := s.entry.Unwrap(); ok {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+	if more, ok := s.more.Unwrap(); ok {
+		v, ok := more[key]
+		return v, ok
+	}
+	return v, false
+}
+
+func (s *shardedMapShard[K, V]) _delete(key K) {
+	if m, ok := s.entry.Unwrap(); ok {
+		if m.key == key {
+			s.entry.Clear()
+			return
+		}
+	}
+	if more, ok := s.more.Unwrap(); ok {
+		delete(more, key)
+	}
+}
+
 func (s *shardedMapShard[K, V]) _set(key K, value V) {
+	// Replace entry
+	if m, ok := s.entry.Unwrap(); ok {
+		if m.key == key {
+			s.entry.Set(shardedMapEntry[K, V]{key: key, value: value})
+			return
+		}
+	}
+
+	// Replace in more
+	more, ok := s.more.Unwrap()
+	if ok {
+		if _, ok := more[key]; ok {
+			more[key] = value
+			return
+		}
+	}
+
+	// Add entry
 	if !s.entry.Valid {
 		e := shardedMapEntry[K, V]{key: key, value: value}
 		s.entry.Set(e)
 		return
 	}
 
-	more, ok := s.more.Unwrap()
+	// Add to more
 	if !ok {
 		more = make(map[K]V)
 		s.more.Set(more)
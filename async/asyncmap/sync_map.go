@@ -37,7 +37,13 @@ func NewSyncMap[K comparable, V any]() SyncMap[K, V] {
 var _ SyncMap[int, int] = (*syncMap[int, int])(nil)
 
 type syncMap[K comparable, V any] struct {
-	raw sync.Map
+	raw sync.Map // K -> *syncEntry[V]
+}
+
+// syncEntry boxes a value, so that compute can swap entries by pointer,
+// and does not require the values to be comparable.
+type syncEntry[V any] struct {
+	value V
 }
 
 func newSyncMap[K comparable, V any]() *syncMap[K, V] {
@@ -67,17 +73,17 @@ func (m *syncMap[K, V]) Contains(key K) bool {
 
 // Get returns a value by key, or false.
 func (m *syncMap[K, V]) Get(key K) (v V, _ bool) {
-	val, ok := m.raw.Load(key)
+	e, ok := m.load(key)
 	if !ok {
 		return v, false
 	}
-	return val.(V), true
+	return e.value, true
 }
 
 // GetOrSet returns a value by key and true, or sets a value and false.
 func (m *syncMap[K, V]) GetOrSet(key K, value V) (_ V, set bool) {
-	val, ok := m.raw.LoadOrStore(key, value)
-	return val.(V), ok
+	val, ok := m.raw.LoadOrStore(key, &syncEntry[V]{value})
+	return val.(*syncEntry[V]).value, ok
 }
 
 // Delete deletes a key value, and returns the previous value.
@@ -86,7 +92,7 @@ func (m *syncMap[K, V]) Delete(key K) (v V, _ bool) {
 	if !ok {
 		return v, false
 	}
-	return val.(V), true
+	return val.(*syncEntry[V]).value, true
 }
 
 // LockMap is not supported.
@@ -96,28 +102,132 @@ func (m *syncMap[K, V]) LockMap() LockedMap[K, V] {
 
 // Set sets a value for a key.
 func (m *syncMap[K, V]) Set(key K, value V) {
-	m.raw.Store(key, value)
+	m.raw.Store(key, &syncEntry[V]{value})
 }
 
 // SetAbsent sets a key value if absent, returns true if set.
 func (m *syncMap[K, V]) SetAbsent(key K, value V) bool {
-	_, loaded := m.raw.LoadOrStore(key, value)
+	_, loaded := m.raw.LoadOrStore(key, &syncEntry[V]{value})
 	return !loaded
 }
 
 // Swap swaps a key value and returns the previous value.
 func (m *syncMap[K, V]) Swap(key K, value V) (v V, _ bool) {
-	val, ok := m.raw.Swap(key, value)
+	val, ok := m.raw.Swap(key, &syncEntry[V]{value})
 	if !ok {
 		return v, false
 	}
-	return val.(V), true
+	return val.(*syncEntry[V]).value, true
 }
 
 // Range iterates over all key-value pairs.
 // The iteration stops if the function returns false.
 func (m *syncMap[K, V]) Range(fn func(K, V) bool) {
-	m.raw.Range(func(key, value any) bool {
-		return fn(key.(K), value.(V))
+	m.raw.Range(func(key, val any) bool {
+		return fn(key.(K), val.(*syncEntry[V]).value)
 	})
 }
+
+// CompareAndSwap sets a new value if the current value equals old, returns true if swapped.
+// The method panics if the values are not comparable.
+func (m *syncMap[K, V]) CompareAndSwap(key K, old V, new V) bool {
+	for {
+		e, ok := m.load(key)
+		if !ok || !valuesEqual(e.value, old) {
+			return false
+		}
+		if m.raw.CompareAndSwap(key, e, &syncEntry[V]{new}) {
+			return true
+		}
+	}
+}
+
+// CompareAndDelete deletes a key if its value equals old, returns true if deleted.
+// The method panics if the values are not comparable.
+func (m *syncMap[K, V]) CompareAndDelete(key K, old V) bool {
+	for {
+		e, ok := m.load(key)
+		if !ok || !valuesEqual(e.value, old) {
+			return false
+		}
+		if m.raw.CompareAndDelete(key, e) {
+			return true
+		}
+	}
+}
+
+// Compute atomically computes a new key value from the current value and its presence,
+// sets the new value if the function returns true, or deletes the key otherwise.
+//
+// The function may be called multiple times on concurrent modifications.
+func (m *syncMap[K, V]) Compute(key K, fn func(v V, ok bool) (V, bool)) (V, bool) {
+	return m.compute(key, computeKeep(fn))
+}
+
+// ComputeIfAbsent returns a key value and true, or atomically sets a value
+// computed by the function and false.
+//
+// The function may be called multiple times on concurrent modifications.
+func (m *syncMap[K, V]) ComputeIfAbsent(key K, fn func() V) (V, bool) {
+	var loaded bool
+	v, _ := m.compute(key, computeAbsent(fn, &loaded))
+	return v, loaded
+}
+
+// Update atomically updates an existing key value, returns the new value and true,
+// or false if the key is absent.
+//
+// The function may be called multiple times on concurrent modifications.
+func (m *syncMap[K, V]) Update(key K, fn func(v V) V) (V, bool) {
+	return m.compute(key, computeUpdate(fn))
+}
+
+// private
+
+func (m *syncMap[K, V]) load(key K) (*syncEntry[V], bool) {
+	val, ok := m.raw.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return val.(*syncEntry[V]), true
+}
+
+// compute computes a value in a compare-and-swap loop, swaps entries by pointer.
+func (m *syncMap[K, V]) compute(key K, fn computeFunc[V]) (v V, _ bool) {
+	for {
+		// Load current value
+		var cur V
+		e, ok := m.load(key)
+		if ok {
+			cur = e.value
+		}
+
+		// Compute value
+		v, action := fn(cur, ok)
+		switch action {
+		case computeNone:
+			return cur, ok
+
+		case computeSet:
+			next := &syncEntry[V]{v}
+			if !ok {
+				if _, loaded := m.raw.LoadOrStore(key, next); !loaded {
+					return v, true
+				}
+				continue
+			}
+			if m.raw.CompareAndSwap(key, e, next) {
+				return v, true
+			}
+
+		case computeDelete:
+			var zero V
+			if !ok {
+				return zero, false
+			}
+			if m.raw.CompareAndDelete(key, e) {
+				return zero, false
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package asyncrc
+
+import (
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/async/asyncmap"
+)
+
+// SingleFlight deduplicates concurrent calls by key.
+//
+// The first caller starts a shared call, other callers join it and receive the same result.
+// Each caller gets its own future, and can stop waiting by cancelling its context,
+// this does not cancel the shared call. The shared call is cancelled only when all its
+// callers have cancelled their waits.
+//
+// The returned futures must be released after use.
+//
+// Usage:
+//
+//	flight := asyncrc.NewSingleFlight[string, *Object]()
+//
+//	future := flight.Do(ctx, key, func(ctx async.Context) (*Object, status.Status) {
+//		return loadObject(ctx, key)
+//	})
+//	defer future.Release()
+//
+//	select {
+//	case <-future.Wait():
+//	case <-ctx.Wait():
+//		return nil, ctx.Status()
+//	}
+//	return future.Result()
+type SingleFlight[K comparable, V any] interface {
+	// Do runs a function once per key, other callers share the result through their futures.
+	//
+	// The future is completed with the context status if the context is cancelled
+	// before the shared call completes.
+	Do(ctx async.Context, key K, fn async.Func[V]) Future[V]
+}
+
+// NewSingleFlight returns a new single flight.
+func NewSingleFlight[K comparable, V any]() SingleFlight[K, V] {
+	return newSingleFlight[K, V](asyncmap.Hash[K])
+}
+
+// NewSingleFlightWithHash returns a new single flight with a custom key hash function.
+func NewSingleFlightWithHash[K comparable, V any](hash asyncmap.HashFunc[K]) SingleFlight[K, V] {
+	return newSingleFlight[K, V](hash)
+}
+
+// internal
+
+var _ SingleFlight[int, int] = (*singleFlight[int, int])(nil)
+
+type singleFlight[K comparable, V any] struct {
+	calls asyncmap.Map[K, *flightCall[K, V]]
+}
+
+func newSingleFlight[K comparable, V any](hash asyncmap.HashFunc[K]) *singleFlight[K, V] {
+	return &singleFlight[K, V]{
+		calls: asyncmap.NewAtomicMapWithHash[K, *flightCall[K, V]](hash),
+	}
+}
+
+// Do runs a function once per key, other callers share the result through their futures.
+//
+// The future is completed with the context status if the context is cancelled
+// before the shared call completes.
+func (f *singleFlight[K, V]) Do(ctx async.Context, key K, fn async.Func[V]) Future[V] {
+	for {
+		// Join existing call
+		call, ok := f.calls.Get(key)
+		if ok {
+			if future, ok := call.join(ctx); ok {
+				return future
+			}
+			continue
+		}
+
+		// Add new call
+		call = newFlightCall(f, key, fn)
+		call1, loaded := f.calls.GetOrSet(key, call)
+		if loaded {
+			if future, ok := call1.join(ctx); ok {
+				return future
+			}
+			continue
+		}
+
+		// Join and start new call
+		future, _ := call.join(ctx)
+		call.start()
+		return future
+	}
+}
+
+// private
+
+// remove deletes a call from the map, must be called only once per call.
+func (f *singleFlight[K, V]) remove(key K) {
+	f.calls.Delete(key)
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package asyncrc
+
+import (
+	"sync"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/status"
+)
+
+type flightCall[K comparable, V any] struct {
+	flight  *singleFlight[K, V]
+	key     K
+	routine async.Routine[V]
+
+	mu        sync.Mutex
+	done      bool // call completed
+	abandoned bool // all waiters have left
+	waiters   map[*flightWaiter[K, V]]struct{}
+}
+
+func newFlightCall[K comparable, V any](f *singleFlight[K, V], key K, fn async.Func[V],
+) *flightCall[K, V] {
+	c := &flightCall[K, V]{
+		flight:  f,
+		key:     key,
+		waiters: make(map[*flightWaiter[K, V]]struct{}),
+	}
+
+	c.routine = async.NewRoutine(fn)
+	c.routine.OnStop(c.onStop)
+	return c
+}
+
+// join adds a waiter to the call, returns false if the call is already done or abandoned.
+func (c *flightCall[K, V]) join(ctx async.Context) (Future[V], bool) {
+	w, ok := c.add(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	// Add callback outside of the lock,
+	// it can be called immediately if the context is cancelled.
+	ctx.AddCallback(w)
+
+	// Remove callback if the waiter has already left
+	c.mu.Lock()
+	left := w.left
+	c.mu.Unlock()
+
+	if left {
+		ctx.RemoveCallback(w)
+	}
+	return w.future(), true
+}
+
+// start starts the shared call routine.
+func (c *flightCall[K, V]) start() {
+	c.routine.Start()
+}
+
+// private
+
+func (c *flightCall[K, V]) add(ctx async.Context) (*flightWaiter[K, V], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.done || c.abandoned {
+		return nil, false
+	}
+
+	w := newFlightWaiter(c, ctx)
+	c.waiters[w] = struct{}{}
+	return w, true
+}
+
+// leave removes a waiter from the call, and cancels the call if no waiters left.
+func (c *flightCall[K, V]) leave(w *flightWaiter[K, V], st status.Status) {
+	abandoned := c.remove(w, st)
+	if !abandoned {
+		return
+	}
+
+	// Stop routine outside of the lock,
+	// because the routine calls onStop while holding its own lock.
+	c.routine.Stop()
+}
+
+// onStop is called by the routine when it stops.
+func (c *flightCall[K, V]) onStop(r async.Routine[V]) {
+	result, st := r.Result()
+
+	// Complete call, get waiters
+	waiters, ok := c.complete()
+	if !ok {
+		return
+	}
+
+	// Notify waiters outside of the lock, because
+	// removing callbacks can deadlock with contexts cancellation.
+	for _, w := range waiters {
+		w.ctx.RemoveCallback(w)
+		w.complete(result, st)
+	}
+}
+
+func (c *flightCall[K, V]) complete() ([]*flightWaiter[K, V], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.abandoned {
+		return nil, false
+	}
+
+	c.done = true
+	c.flight.remove(c.key)
+
+	waiters := make([]*flightWaiter[K, V], 0, len(c.waiters))
+	for w := range c.waiters {
+		w.left = true
+		waiters = append(waiters, w)
+	}
+	clear(c.waiters)
+	return waiters, true
+}
+
+func (c *flightCall[K, V]) remove(w *flightWaiter[K, V], st status.Status) (abandoned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Return if already left
+	if w.left {
+		return false
+	}
+
+	// Remove waiter, reject its promise
+	var zero V
+	w.left = true
+	delete(c.waiters, w)
+	w.complete(zero, st)
+
+	// Return if more waiters
+	if len(c.waiters) > 0 {
+		return false
+	}
+
+	// Abandon call
+	c.abandoned = true
+	c.flight.remove(c.key)
+	return true
+}
+
+// waiter
+
+var _ async.ContextCallback = (*flightWaiter[int, int])(nil)
+
+type flightWaiter[K comparable, V any] struct {
+	call    *flightCall[K, V]
+	ctx     async.Context
+	promise Promise[V]
+
+	left bool // guarded by call mutex
+}
+
+func newFlightWaiter[K comparable, V any](call *flightCall[K, V], ctx async.Context,
+) *flightWaiter[K, V] {
+	// Retain promise, one reference is returned to the caller,
+	// another is released when the waiter completes it.
+	p := newPromise[V]()
+	p.Retain()
+
+	return &flightWaiter[K, V]{
+		call:    call,
+		ctx:     ctx,
+		promise: p,
+	}
+}
+
+// OnCancelled is called when the waiter context is cancelled.
+func (w *flightWaiter[K, V]) OnCancelled(st status.Status) {
+	w.call.leave(w, st)
+}
+
+// private
+
+func (w *flightWaiter[K, V]) future() Future[V] {
+	return w.promise
+}
+
+func (w *flightWaiter[K, V]) complete(result V, st status.Status) {
+	w.promise.Complete(result, st)
+	w.promise.Release()
+}
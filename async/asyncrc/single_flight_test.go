@@ -0,0 +1,206 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package asyncrc
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Do
+
+func TestSingleFlight_Do__should_run_function_once_per_key(t *testing.T) {
+	f := NewSingleFlight[string, int]()
+	ctx := async.NoContext()
+
+	calls := atomic.Int32{}
+	release := make(chan struct{})
+	fn := func(ctx async.Context) (int, status.Status) {
+		calls.Add(1)
+		<-release
+		return 123, status.OK
+	}
+
+	futures := make([]Future[int], 0, 10)
+	for i := 0; i < 10; i++ {
+		future := f.Do(ctx, "key", fn)
+		futures = append(futures, future)
+	}
+	close(release)
+
+	for _, future := range futures {
+		select {
+		case <-future.Wait():
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		v, st := future.Result()
+		require.True(t, st.OK())
+		assert.Equal(t, 123, v)
+		future.Release()
+	}
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestSingleFlight_Do__should_run_new_call_after_previous_completed(t *testing.T) {
+	f := NewSingleFlight[string, int]()
+	ctx := async.NoContext()
+
+	calls := atomic.Int32{}
+	fn := func(ctx async.Context) (int, status.Status) {
+		n := calls.Add(1)
+		return int(n), status.OK
+	}
+
+	future := f.Do(ctx, "key", fn)
+	<-future.Wait()
+	v, _ := future.Result()
+	future.Release()
+	assert.Equal(t, 1, v)
+
+	future = f.Do(ctx, "key", fn)
+	<-future.Wait()
+	v, _ = future.Result()
+	future.Release()
+	assert.Equal(t, 2, v)
+}
+
+func TestSingleFlight_Do__should_not_cancel_shared_call_when_one_waiter_cancelled(t *testing.T) {
+	f := NewSingleFlight[string, int]()
+
+	release := make(chan struct{})
+	fn := func(ctx async.Context) (int, status.Status) {
+		select {
+		case <-release:
+			return 123, status.OK
+		case <-ctx.Wait():
+			return 0, ctx.Status()
+		}
+	}
+
+	ctx0 := async.NewContext()
+	defer ctx0.Free()
+	ctx1 := async.NewContext()
+	defer ctx1.Free()
+
+	future0 := f.Do(ctx0, "key", fn)
+	defer future0.Release()
+	future1 := f.Do(ctx1, "key", fn)
+	defer future1.Release()
+
+	ctx0.Cancel()
+	select {
+	case <-future0.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	assert.Equal(t, status.Cancelled, future0.Status())
+
+	close(release)
+	select {
+	case <-future1.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	v, st := future1.Result()
+	require.True(t, st.OK())
+	assert.Equal(t, 123, v)
+}
+
+func TestSingleFlight_Do__should_cancel_shared_call_when_all_waiters_cancelled(t *testing.T) {
+	f := NewSingleFlight[string, int]()
+
+	cancelled := make(chan struct{})
+	fn := func(ctx async.Context) (int, status.Status) {
+		<-ctx.Wait()
+		close(cancelled)
+		return 0, ctx.Status()
+	}
+
+	ctx0 := async.NewContext()
+	defer ctx0.Free()
+	ctx1 := async.NewContext()
+	defer ctx1.Free()
+
+	future0 := f.Do(ctx0, "key", fn)
+	defer future0.Release()
+	future1 := f.Do(ctx1, "key", fn)
+	defer future1.Release()
+
+	ctx0.Cancel()
+	ctx1.Cancel()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("shared call not cancelled")
+	}
+
+	assert.Equal(t, status.Cancelled, future0.Status())
+	assert.Equal(t, status.Cancelled, future1.Status())
+}
+
+func TestSingleFlight_Do__should_start_new_call_when_previous_abandoned(t *testing.T) {
+	f := NewSingleFlight[string, int]()
+
+	started := make(chan struct{})
+	calls := atomic.Int32{}
+	fn := func(ctx async.Context) (int, status.Status) {
+		n := calls.Add(1)
+		if n == 1 {
+			close(started)
+			<-ctx.Wait()
+			return 0, ctx.Status()
+		}
+		return int(n), status.OK
+	}
+
+	ctx := async.NewContext()
+	future := f.Do(ctx, "key", fn)
+	<-started
+	ctx.Free()
+	<-future.Wait()
+	future.Release()
+
+	future = f.Do(async.NoContext(), "key", fn)
+	defer future.Release()
+
+	select {
+	case <-future.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	v, st := future.Result()
+	require.True(t, st.OK())
+	assert.Equal(t, 2, v)
+}
+
+func TestSingleFlight_Do__should_recover_from_panic(t *testing.T) {
+	f := NewSingleFlight[string, int]()
+
+	future := f.Do(async.NoContext(), "key", func(ctx async.Context) (int, status.Status) {
+		panic("test")
+	})
+	defer future.Release()
+
+	select {
+	case <-future.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	st := future.Status()
+	assert.Equal(t, status.CodeError, st.Code)
+}
@@ -0,0 +1,331 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"sync"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
+)
+
+// Batcher coalesces concurrent items into batches, and processes them with a single operation,
+// for example, one fsync or one RPC.
+//
+// Items are collected until the max batch size is reached, or the max delay elapses,
+// then the batch function is called with all collected items. When the max number of
+// concurrent batches is running, items are collected until a batch completes.
+//
+// Example:
+//
+//	batcher := async.NewBatcher(writeBatch, async.BatcherOptions{
+//		MaxSize:  128,
+//		MaxDelay: time.Millisecond,
+//	})
+//	defer async.StopWait(batcher)
+//
+//	func write(ctx async.Context, record Record) status.Status {
+//		future := batcher.Submit(ctx, record)
+//		select {
+//		case <-ctx.Wait():
+//			return ctx.Status()
+//		case <-future.Wait():
+//		}
+//
+//		_, st := future.Result()
+//		return st
+//	}
+type Batcher[In, Out any] interface {
+	// Submit adds an item to the next batch, and returns a future with its result.
+	//
+	// The item is skipped and rejected with the context status if the context
+	// is cancelled before the batch starts. Submit rejects items when the batcher is stopped.
+	Submit(ctx Context, item In) Future[Out]
+
+	// Flush starts a batch with the pending items without waiting for the max delay.
+	Flush()
+
+	// Stop stops accepting new items, processes the pending ones and returns a channel
+	// which is closed when all batches are completed.
+	Stop() <-chan struct{}
+
+	// Wait returns a channel which is closed when the batcher is stopped
+	// and all batches are completed.
+	Wait() <-chan struct{}
+}
+
+// BatchFunc processes a batch of items, and returns a result for each item.
+//
+// A non-OK batch status rejects all items. Otherwise, the results must have the same length
+// as the items, and each item is completed with its result value and status.
+type BatchFunc[In, Out any] func(ctx Context, items []In) ([]Result[Out], status.Status)
+
+// BatcherOptions specifies the batcher options.
+type BatcherOptions struct {
+	// MaxSize is the max number of items in a batch, zero means unlimited.
+	MaxSize int
+
+	// MaxDelay is the max time to collect items before starting a batch,
+	// zero means a batch starts as soon as the concurrency limit allows.
+	MaxDelay time.Duration
+
+	// MaxConcurrent is the max number of concurrently running batches, the default is 1.
+	MaxConcurrent int
+
+	// Clock is the clock used for the max delay, nil means the real clock.
+	Clock clockwork.Clock
+}
+
+// NewBatcher returns a new batcher.
+func NewBatcher[In, Out any](fn BatchFunc[In, Out], opts BatcherOptions) Batcher[In, Out] {
+	return newBatcher(fn, opts)
+}
+
+// internal
+
+var _ Batcher[int, int] = (*batcher[int, int])(nil)
+
+type batcher[In, Out any] struct {
+	fn    BatchFunc[In, Out]
+	opts  BatcherOptions
+	clock clockwork.Clock
+	done  chan struct{} // closed when stopped and drained
+
+	mu      sync.Mutex
+	pending []batchItem[In, Out]
+	running int
+	ready   bool // max delay elapsed or flush requested
+	stopped bool
+	timer   clockwork.Timer
+}
+
+type batchItem[In, Out any] struct {
+	ctx     Context
+	item    In
+	promise Promise[Out]
+}
+
+func newBatcher[In, Out any](fn BatchFunc[In, Out], opts BatcherOptions) *batcher[In, Out] {
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = 1
+	}
+
+	return &batcher[In, Out]{
+		fn:    fn,
+		opts:  opts,
+		clock: clockwork.Or(opts.Clock),
+		done:  make(chan struct{}),
+	}
+}
+
+// Submit adds an item to the next batch, and returns a future with its result.
+//
+// The item is skipped and rejected with the context status if the context
+// is cancelled before the batch starts. Submit rejects items when the batcher is stopped.
+func (b *batcher[In, Out]) Submit(ctx Context, item In) Future[Out] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopped {
+		return Rejected[Out](status.Unavailable("batcher stopped"))
+	}
+
+	// Add item
+	p := newPromise[Out]()
+	b.pending = append(b.pending, batchItem[In, Out]{
+		ctx:     ctx,
+		item:    item,
+		promise: p,
+	})
+
+	// Maybe start timer
+	if len(b.pending) == 1 && b.opts.MaxDelay > 0 {
+		b.startTimer()
+	}
+
+	b.dispatch()
+	return p
+}
+
+// Flush starts a batch with the pending items without waiting for the max delay.
+func (b *batcher[In, Out]) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending) == 0 {
+		return
+	}
+
+	b.ready = true
+	b.dispatch()
+}
+
+// Stop stops accepting new items, processes the pending ones and returns a channel
+// which is closed when all batches are completed.
+func (b *batcher[In, Out]) Stop() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopped {
+		return b.done
+	}
+
+	b.stopped = true
+	b.dispatch()
+	b.maybeDone()
+	return b.done
+}
+
+// Wait returns a channel which is closed when the batcher is stopped
+// and all batches are completed.
+func (b *batcher[In, Out]) Wait() <-chan struct{} {
+	return b.done
+}
+
+// internal
+
+// dispatch starts batches while there are ready items and the concurrency limit allows.
+func (b *batcher[In, Out]) dispatch() {
+	for len(b.pending) > 0 && b.running < b.opts.MaxConcurrent {
+		full := b.opts.MaxSize > 0 && len(b.pending) >= b.opts.MaxSize
+		if !full && !b.ready && !b.stopped && b.opts.MaxDelay > 0 {
+			return
+		}
+
+		// Take batch
+		n := len(b.pending)
+		if b.opts.MaxSize > 0 {
+			n = min(n, b.opts.MaxSize)
+		}
+
+		batch := make([]batchItem[In, Out], n)
+		copy(batch, b.pending)
+
+		rest := copy(b.pending, b.pending[n:])
+		clear(b.pending[rest:])
+		b.pending = b.pending[:rest]
+
+		// Reset timer when no more items
+		if len(b.pending) == 0 {
+			b.ready = false
+			b.stopTimer()
+		}
+
+		// Run batch
+		b.running++
+		go b.run(batch)
+	}
+}
+
+// run runs a batch and completes its items.
+func (b *batcher[In, Out]) run(batch []batchItem[In, Out]) {
+	defer b.complete()
+
+	// Skip cancelled items
+	live := batch[:0]
+	for _, item := range batch {
+		if item.ctx.Done() {
+			item.promise.Reject(item.ctx.Status())
+			continue
+		}
+		live = append(live, item)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	// Call function
+	items := make([]In, len(live))
+	for i, item := range live {
+		items[i] = item.item
+	}
+	results, st := b.call(items)
+
+	// Reject all on error
+	if !st.OK() {
+		for _, item := range live {
+			item.promise.Reject(st)
+		}
+		return
+	}
+
+	// Check results
+	if len(results) != len(live) {
+		st := status.Errorf("batch returned %d results for %d items", len(results), len(live))
+		for _, item := range live {
+			item.promise.Reject(st)
+		}
+		return
+	}
+
+	// Complete items
+	for i, item := range live {
+		result := results[i]
+		item.promise.Complete(result.Value, result.Status)
+	}
+}
+
+// call calls the batch function and recovers from panics.
+func (b *batcher[In, Out]) call(items []In) (results []Result[Out], st status.Status) {
+	defer func() {
+		if e := recover(); e != nil {
+			st = status.Recover(e)
+		}
+	}()
+
+	return b.fn(NoContext(), items)
+}
+
+// complete decrements the running batches and dispatches the pending items.
+func (b *batcher[In, Out]) complete() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.running--
+	b.dispatch()
+	b.maybeDone()
+}
+
+// maybeDone closes the done channel when stopped and drained.
+func (b *batcher[In, Out]) maybeDone() {
+	if !b.stopped || b.running > 0 || len(b.pending) > 0 {
+		return
+	}
+
+	select {
+	case <-b.done:
+	default:
+		close(b.done)
+	}
+}
+
+// timer
+
+func (b *batcher[In, Out]) startTimer() {
+	if b.timer == nil {
+		b.timer = b.clock.AfterFunc(b.opts.MaxDelay, b.onTimer)
+		return
+	}
+	b.timer.Reset(b.opts.MaxDelay)
+}
+
+func (b *batcher[In, Out]) stopTimer() {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+}
+
+func (b *batcher[In, Out]) onTimer() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending) == 0 {
+		return
+	}
+
+	b.ready = true
+	b.dispatch()
+}
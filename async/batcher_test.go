@@ -0,0 +1,278 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBatchDouble(ctx Context, items []int) ([]Result[int], status.Status) {
+	results := make([]Result[int], len(items))
+	for i, item := range items {
+		results[i] = Result[int]{Value: item * 2, Status: status.OK}
+	}
+	return results, status.OK
+}
+
+func testAwait[T any](t *testing.T, f Future[T]) (T, status.Status) {
+	select {
+	case <-f.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	return f.Result()
+}
+
+// Submit
+
+func TestBatcher_Submit__should_batch_items_up_to_max_size(t *testing.T) {
+	var sizes []int
+	var mu sync.Mutex
+
+	fn := func(ctx Context, items []int) ([]Result[int], status.Status) {
+		mu.Lock()
+		sizes = append(sizes, len(items))
+		mu.Unlock()
+		return testBatchDouble(ctx, items)
+	}
+
+	b := NewBatcher(fn, BatcherOptions{
+		MaxSize:  4,
+		MaxDelay: time.Hour,
+	})
+	defer StopWait(b)
+
+	futures := make([]Future[int], 8)
+	for i := range futures {
+		futures[i] = b.Submit(NoContext(), i)
+	}
+
+	for i, f := range futures {
+		v, st := testAwait(t, f)
+		require.True(t, st.OK())
+		assert.Equal(t, i*2, v)
+	}
+	assert.Equal(t, []int{4, 4}, sizes)
+}
+
+func TestBatcher_Submit__should_start_batch_after_max_delay(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+	b := NewBatcher(testBatchDouble, BatcherOptions{
+		MaxSize:  100,
+		MaxDelay: time.Millisecond * 5,
+		Clock:    clock,
+	})
+	defer StopWait(b)
+
+	f := b.Submit(NoContext(), 1)
+	clock.Advance(time.Millisecond*5 - 1)
+	assert.False(t, f.Done())
+
+	clock.Advance(1)
+
+	v, st := testAwait(t, f)
+	require.True(t, st.OK())
+	assert.Equal(t, 2, v)
+}
+
+func TestBatcher_Submit__should_collect_items_while_batch_running(t *testing.T) {
+	var sizes []int
+	var mu sync.Mutex
+	release := make(chan struct{})
+
+	fn := func(ctx Context, items []int) ([]Result[int], status.Status) {
+		<-release
+
+		mu.Lock()
+		sizes = append(sizes, len(items))
+		mu.Unlock()
+		return testBatchDouble(ctx, items)
+	}
+
+	b := NewBatcher(fn, BatcherOptions{})
+	defer StopWait(b)
+
+	f0 := b.Submit(NoContext(), 0)
+	f1 := b.Submit(NoContext(), 1)
+	f2 := b.Submit(NoContext(), 2)
+	close(release)
+
+	testAwait(t, f0)
+	testAwait(t, f1)
+	testAwait(t, f2)
+	assert.Equal(t, []int{1, 2}, sizes)
+}
+
+func TestBatcher_Submit__should_limit_concurrent_batches(t *testing.T) {
+	var running atomic.Int32
+	var maxRunning atomic.Int32
+
+	fn := func(ctx Context, items []int) ([]Result[int], status.Status) {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		if n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		time.Sleep(time.Millisecond)
+		return testBatchDouble(ctx, items)
+	}
+
+	b := NewBatcher(fn, BatcherOptions{
+		MaxSize:       1,
+		MaxConcurrent: 2,
+	})
+	defer StopWait(b)
+
+	futures := make([]Future[int], 10)
+	for i := range futures {
+		futures[i] = b.Submit(NoContext(), i)
+	}
+	for _, f := range futures {
+		testAwait(t, f)
+	}
+
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestBatcher_Submit__should_complete_items_with_their_statuses(t *testing.T) {
+	fn := func(ctx Context, items []int) ([]Result[int], status.Status) {
+		results := make([]Result[int], len(items))
+		for i, item := range items {
+			if item%2 == 0 {
+				results[i] = Result[int]{Value: item, Status: status.OK}
+			} else {
+				results[i] = Result[int]{Status: status.NotFound("odd")}
+			}
+		}
+		return results, status.OK
+	}
+
+	b := NewBatcher(fn, BatcherOptions{MaxSize: 2, MaxDelay: time.Hour})
+	defer StopWait(b)
+
+	f0 := b.Submit(NoContext(), 0)
+	f1 := b.Submit(NoContext(), 1)
+
+	_, st := testAwait(t, f0)
+	assert.True(t, st.OK())
+
+	_, st = testAwait(t, f1)
+	assert.Equal(t, status.CodeNotFound, st.Code)
+}
+
+func TestBatcher_Submit__should_reject_all_items_on_batch_error(t *testing.T) {
+	st := status.Test("test")
+	fn := func(ctx Context, items []int) ([]Result[int], status.Status) {
+		return nil, st
+	}
+
+	b := NewBatcher(fn, BatcherOptions{MaxSize: 2, MaxDelay: time.Hour})
+	defer StopWait(b)
+
+	f0 := b.Submit(NoContext(), 0)
+	f1 := b.Submit(NoContext(), 1)
+
+	_, st0 := testAwait(t, f0)
+	_, st1 := testAwait(t, f1)
+	assert.Equal(t, st, st0)
+	assert.Equal(t, st, st1)
+}
+
+func TestBatcher_Submit__should_reject_all_items_on_panic(t *testing.T) {
+	fn := func(ctx Context, items []int) ([]Result[int], status.Status) {
+		panic("test")
+	}
+
+	b := NewBatcher(fn, BatcherOptions{})
+	defer StopWait(b)
+
+	f := b.Submit(NoContext(), 0)
+
+	_, st := testAwait(t, f)
+	assert.Equal(t, status.CodeError, st.Code)
+}
+
+func TestBatcher_Submit__should_skip_cancelled_items(t *testing.T) {
+	var items []int
+	fn := func(ctx Context, batch []int) ([]Result[int], status.Status) {
+		items = append(items, batch...)
+		return testBatchDouble(ctx, batch)
+	}
+
+	b := NewBatcher(fn, BatcherOptions{MaxSize: 2, MaxDelay: time.Hour})
+	defer StopWait(b)
+
+	ctx := NewContext()
+	ctx.Cancel()
+	defer ctx.Free()
+
+	f0 := b.Submit(ctx, 0)
+	f1 := b.Submit(NoContext(), 1)
+
+	_, st := testAwait(t, f0)
+	assert.Equal(t, status.Cancelled, st)
+
+	_, st = testAwait(t, f1)
+	assert.True(t, st.OK())
+	assert.Equal(t, []int{1}, items)
+}
+
+// Flush
+
+func TestBatcher_Flush__should_start_batch_immediately(t *testing.T) {
+	b := NewBatcher(testBatchDouble, BatcherOptions{
+		MaxSize:  100,
+		MaxDelay: time.Hour,
+	})
+	defer StopWait(b)
+
+	f := b.Submit(NoContext(), 1)
+	b.Flush()
+
+	v, st := testAwait(t, f)
+	require.True(t, st.OK())
+	assert.Equal(t, 2, v)
+}
+
+// Stop
+
+func TestBatcher_Stop__should_drain_pending_items(t *testing.T) {
+	b := NewBatcher(testBatchDouble, BatcherOptions{
+		MaxSize:  100,
+		MaxDelay: time.Hour,
+	})
+
+	f := b.Submit(NoContext(), 1)
+
+	select {
+	case <-b.Stop():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	require.True(t, f.Done())
+	v, st := f.Result()
+	require.True(t, st.OK())
+	assert.Equal(t, 2, v)
+}
+
+func TestBatcher_Stop__should_reject_new_items(t *testing.T) {
+	b := NewBatcher(testBatchDouble, BatcherOptions{})
+	StopWait(b)
+
+	f := b.Submit(NoContext(), 1)
+
+	_, st := testAwait(t, f)
+	assert.Equal(t, status.CodeUnavailable, st.Code)
+}
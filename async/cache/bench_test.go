@@ -0,0 +1,166 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/async/asyncmap"
+)
+
+const benchCacheNum = 1024
+
+// Read
+
+func BenchmarkCache_Read(b *testing.B) {
+	c := New(Options[int, int]{MaxEntries: benchCacheNum * 2})
+	for i := 0; i < benchCacheNum; i++ {
+		c.Set(i, i)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		key := rand.IntN(benchCacheNum)
+
+		val, ok := c.Get(key)
+		if !ok {
+			b.Fatal("item not found")
+		}
+		if val != key {
+			b.Fatal("invalid value", key, val)
+		}
+	}
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkCache_Read_Parallel(b *testing.B) {
+	c := New(Options[int, int]{MaxEntries: benchCacheNum * 2})
+	for i := 0; i < benchCacheNum; i++ {
+		c.Set(i, i)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			key := rand.IntN(benchCacheNum)
+
+			val, ok := c.Get(key)
+			if !ok {
+				b.Fatal("item not found")
+			}
+			if val != key {
+				b.Fatal("invalid value", key, val)
+			}
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkCache_Read_Parallel_LRU(b *testing.B) {
+	c := New(Options[int, int]{MaxEntries: benchCacheNum * 2, Policy: PolicyLRU})
+	for i := 0; i < benchCacheNum; i++ {
+		c.Set(i, i)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			key := rand.IntN(benchCacheNum)
+
+			val, ok := c.Get(key)
+			if !ok {
+				b.Fatal("item not found")
+			}
+			if val != key {
+				b.Fatal("invalid value", key, val)
+			}
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkCache_Read_Parallel_AtomicShardedMap(b *testing.B) {
+	m := asyncmap.NewAtomicShardedMap[int, int]()
+	for i := 0; i < benchCacheNum; i++ {
+		m.Set(i, i)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			key := rand.IntN(benchCacheNum)
+
+			val, ok := m.Get(key)
+			if !ok {
+				b.Fatal("item not found")
+			}
+			if val != key {
+				b.Fatal("invalid value", key, val)
+			}
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+// Write
+
+func BenchmarkCache_Write(b *testing.B) {
+	c := New(Options[int, int]{MaxEntries: benchCacheNum})
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		key := rand.IntN(benchCacheNum * 2)
+		c.Set(key, key)
+	}
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkCache_Write_Parallel(b *testing.B) {
+	c := New(Options[int, int]{MaxEntries: benchCacheNum})
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			key := rand.IntN(benchCacheNum * 2)
+			c.Set(key, key)
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkCache_Write_Parallel_AtomicShardedMap(b *testing.B) {
+	m := asyncmap.NewAtomicShardedMap[int, int]()
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			key := rand.IntN(benchCacheNum * 2)
+			m.Set(key, key)
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
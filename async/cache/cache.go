@@ -0,0 +1,396 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/async/asyncmap"
+	"github.com/basecomplextech/baselibrary/async/asyncrc"
+	"github.com/basecomplextech/baselibrary/ref"
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
+)
+
+// Cache is a sharded concurrent cache with a size bound, per-entry TTL and eviction.
+//
+// The cache is split into multiple shards each guarded by a mutex, the capacity
+// is divided equally between the shards, see [Options.MaxCost]. Entries are evicted by an LRU or W-TinyLFU policy.
+// Expired entries are removed lazily on access, or by DeleteExpired.
+//
+// Missing values can be loaded by a loader in GetOrLoad, concurrent loads of
+// the same key are deduplicated.
+//
+// Use [NewRefCache] to store references, they are released when evicted.
+type Cache[K comparable, V any] interface {
+	// Len returns the number of entries, including expired but not yet removed ones.
+	Len() int
+
+	// Clear deletes all entries.
+	Clear()
+
+	// Contains returns true if a non-expired entry exists.
+	Contains(key K) bool
+
+	// Get returns a value, or false if not found or expired.
+	// Reference caches retain the returned reference.
+	Get(key K) (V, bool)
+
+	// GetOrLoad returns a value, or loads it using the loader.
+	// Concurrent loads of the same key are deduplicated.
+	// Reference caches retain the returned reference.
+	GetOrLoad(ctx async.Context, key K) (V, status.Status)
+
+	// Set sets a value with the default TTL.
+	// Reference caches retain the reference.
+	Set(key K, value V)
+
+	// SetTTL sets a value with a TTL, zero means no expiration.
+	// Reference caches retain the reference.
+	SetTTL(key K, value V, ttl time.Duration)
+
+	// Delete deletes an entry, returns true if deleted.
+	Delete(key K) bool
+
+	// DeleteExpired deletes all expired entries.
+	DeleteExpired()
+
+	// Stats returns the cache statistics.
+	Stats() Stats
+}
+
+// New returns a new cache.
+func New[K comparable, V any](opts Options[K, V]) Cache[K, V] {
+	return newCache(opts)
+}
+
+// NewRefCache returns a new cache which stores references.
+//
+// The cache retains references on Set and on returning them from Get and GetOrLoad,
+// and releases them when evicted. The references returned by the loader are not retained,
+// the cache takes their ownership.
+func NewRefCache[K comparable, V any](opts Options[K, ref.R[V]]) Cache[K, ref.R[V]] {
+	c := newCache(opts)
+	c.retainFn = func(r ref.R[V]) { r.Retain() }
+	c.releaseFn = func(r ref.R[V]) { r.Release() }
+	return c
+}
+
+// internal
+
+const (
+	// minShardCapacity is the minimum shard capacity before reducing the number of shards.
+	minShardCapacity = 64
+
+	// maxLoadAttempts is the max number of loads when a loaded reference
+	// is evicted before it has been read.
+	maxLoadAttempts = 3
+)
+
+var _ Cache[int, int] = (*cache[int, int])(nil)
+
+type cache[K comparable, V any] struct {
+	opts   Options[K, V]
+	shards []*shard[K, V]
+	flight asyncrc.SingleFlight[K, V]
+	stats  stats
+	hash   asyncmap.HashFunc[K]
+
+	clock        clockwork.Clock
+	refreshAhead int64 // nanos
+
+	retainFn  func(V) // optional
+	releaseFn func(V) // optional
+}
+
+func newCache[K comparable, V any](opts Options[K, V]) *cache[K, V] {
+	capacity := opts.MaxCost
+	if capacity <= 0 {
+		capacity = int64(opts.MaxEntries)
+	}
+
+	// Calculate number of shards, round to power of two,
+	// reduce shards when capacity is small.
+	num := roundToPowerOfTwo(runtime.NumCPU())
+	if capacity > 0 {
+		for num > 1 && capacity/int64(num) < minShardCapacity {
+			num /= 2
+		}
+	}
+
+	// Calculate shard capacity, round up
+	shardCap := int64(0)
+	if capacity > 0 {
+		shardCap = (capacity + int64(num) - 1) / int64(num)
+	}
+
+	// Default hash
+	hash := opts.Hash
+	if hash == nil {
+		hash = asyncmap.Hash[K]
+	}
+
+	// Make cache
+	c := &cache[K, V]{
+		opts:   opts,
+		shards: make([]*shard[K, V], num),
+		flight: asyncrc.NewSingleFlightWithHash[K, V](hash),
+		hash:   hash,
+
+		clock:        clockwork.Or(opts.Clock),
+		refreshAhead: int64(opts.RefreshAhead),
+	}
+	for i := range c.shards {
+		c.shards[i] = newShard(c, shardCap, opts.Policy)
+	}
+	return c
+}
+
+// Len returns the number of entries, including expired but not yet removed ones.
+func (c *cache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.len()
+	}
+	return n
+}
+
+// Clear deletes all entries.
+func (c *cache[K, V]) Clear() {
+	for _, s := range c.shards {
+		evicted := s.clear()
+		c.notify(evicted)
+	}
+}
+
+// Contains returns true if a non-expired entry exists.
+func (c *cache[K, V]) Contains(key K) bool {
+	s, _ := c.shard(key)
+	now := c.now()
+	return s.contains(key, now)
+}
+
+// Get returns a value, or false if not found or expired.
+// Reference caches retain the returned reference.
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	return c.get(key, true)
+}
+
+// GetOrLoad returns a value, or loads it using the loader.
+// Concurrent loads of the same key are deduplicated.
+// Reference caches retain the returned reference.
+func (c *cache[K, V]) GetOrLoad(ctx async.Context, key K) (v V, st status.Status) {
+	if c.opts.Loader == nil {
+		return v, status.Unsupported("cache loader not set")
+	}
+
+	for attempt := 0; ; attempt++ {
+		// Return cached value, count only the first lookup
+		v, ok := c.get(key, attempt == 0)
+		if ok {
+			return v, status.OK
+		}
+
+		// Load value
+		if attempt >= maxLoadAttempts {
+			return v, status.Unavailable("loaded cache value has been evicted")
+		}
+		v, st = c.load(ctx, key)
+		if !st.OK() {
+			return v, st
+		}
+
+		// Return loaded value
+		// Reference caches read the value again to retain it.
+		if c.releaseFn == nil {
+			return v, status.OK
+		}
+	}
+}
+
+// Set sets a value with the default TTL.
+// Reference caches retain the reference.
+func (c *cache[K, V]) Set(key K, value V) {
+	c.retain(value)
+	c.set(key, value, c.opts.TTL)
+}
+
+// SetTTL sets a value with a TTL, zero means no expiration.
+// Reference caches retain the reference.
+func (c *cache[K, V]) SetTTL(key K, value V, ttl time.Duration) {
+	c.retain(value)
+	c.set(key, value, ttl)
+}
+
+// Delete deletes an entry, returns true if deleted.
+func (c *cache[K, V]) Delete(key K) bool {
+	s, _ := c.shard(key)
+
+	ok, evicted := s.remove(key)
+	c.notify(evicted)
+	return ok
+}
+
+// DeleteExpired deletes all expired entries.
+func (c *cache[K, V]) DeleteExpired() {
+	now := c.now()
+
+	for _, s := range c.shards {
+		evicted := s.deleteExpired(now)
+		c.notify(evicted)
+	}
+}
+
+// Stats returns the cache statistics.
+func (c *cache[K, V]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// internal
+
+// get returns a value, and records a hit or a miss when stats is true.
+func (c *cache[K, V]) get(key K, stats bool) (V, bool) {
+	s, h := c.shard(key)
+	now := c.now()
+
+	v, ok, refresh, evicted := s.get(key, h, now)
+	c.notify(evicted)
+
+	if stats {
+		if ok {
+			c.stats.hits.Add(1)
+		} else {
+			c.stats.misses.Add(1)
+		}
+	}
+	if !ok {
+		return v, false
+	}
+
+	if refresh {
+		c.refresh(key)
+	}
+	return v, true
+}
+
+// set sets a value, the value must be already retained.
+func (c *cache[K, V]) set(key K, value V, ttl time.Duration) {
+	s, h := c.shard(key)
+
+	// Make entry
+	e := &entry[K, V]{
+		key:   key,
+		value: value,
+		hash:  h,
+		cost:  1,
+	}
+	if cost := c.opts.Cost; cost != nil {
+		e.cost = cost(key, value)
+	}
+	if ttl > 0 {
+		e.expires = c.now() + int64(ttl)
+	}
+
+	// Set entry
+	evicted := s.set(e)
+	c.notify(evicted)
+}
+
+// load loads a value using the single flight, and awaits the result.
+func (c *cache[K, V]) load(ctx async.Context, key K) (V, status.Status) {
+	fn := c.loadFunc(key)
+
+	future := c.flight.Do(ctx, key, fn)
+	defer future.Release()
+
+	<-future.Wait()
+	return future.Result()
+}
+
+// loadFunc returns a function which loads a value and adds it to the cache.
+func (c *cache[K, V]) loadFunc(key K) async.Func[V] {
+	return func(ctx async.Context) (V, status.Status) {
+		c.stats.loads.Add(1)
+
+		v, st := c.opts.Loader(ctx, key)
+		if !st.OK() {
+			c.stats.loadErrors.Add(1)
+
+			// Allow next refresh-ahead
+			s, _ := c.shard(key)
+			s.refreshFailed(key)
+			return v, st
+		}
+
+		c.set(key, v, c.opts.TTL)
+		return v, status.OK
+	}
+}
+
+// refresh reloads a value in the background.
+func (c *cache[K, V]) refresh(key K) {
+	if c.opts.Loader == nil {
+		return
+	}
+
+	fn := c.loadFunc(key)
+	future := c.flight.Do(async.NoContext(), key, fn)
+	future.Release()
+}
+
+// notify notifies the eviction callback and releases the evicted values.
+func (c *cache[K, V]) notify(evicted []evicted[K, V]) {
+	for _, ev := range evicted {
+		switch ev.reason {
+		case EvictCapacity, EvictExpired:
+			c.stats.evictions.Add(1)
+		}
+
+		if fn := c.opts.OnEvict; fn != nil {
+			fn(ev.key, ev.value, ev.reason)
+		}
+		c.release(ev.value)
+	}
+}
+
+// private
+
+func (c *cache[K, V]) shard(key K) (*shard[K, V], uint32) {
+	h := c.hash(key)
+	i := h & uint32(len(c.shards)-1)
+	return c.shards[i], h
+}
+
+func (c *cache[K, V]) now() int64 {
+	return c.clock.Now().UnixNano()
+}
+
+func (c *cache[K, V]) retain(v V) {
+	if c.retainFn != nil {
+		c.retainFn(v)
+	}
+}
+
+func (c *cache[K, V]) release(v V) {
+	if c.releaseFn != nil {
+		c.releaseFn(v)
+	}
+}
+
+// util
+
+// roundToPowerOfTwo rounds a number to the nearest power of two.
+func roundToPowerOfTwo(n int) int {
+	n--
+	n |= n >> 1
+	n |= n >> 2
+	n |= n >> 4
+	n |= n >> 8
+	n |= n >> 16
+	n++
+	return n
+}
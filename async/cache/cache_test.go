@@ -0,0 +1,462 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/ref"
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCache[K comparable, V any](opts Options[K, V]) (*cache[K, V], clockwork.Fake) {
+	clock := clockwork.NewFake(time.Time{})
+	opts.Clock = clock
+
+	c := newCache(opts)
+	return c, clock
+}
+
+// Get
+
+func TestCache_Get__should_return_value(t *testing.T) {
+	c, _ := testCache(Options[int, int]{})
+	c.Set(1, 10)
+
+	v, ok := c.Get(1)
+	require.True(t, ok)
+	assert.Equal(t, 10, v)
+}
+
+func TestCache_Get__should_return_false_when_not_found(t *testing.T) {
+	c, _ := testCache(Options[int, int]{})
+
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+}
+
+func TestCache_Get__should_delete_expired_entry(t *testing.T) {
+	c, clock := testCache(Options[int, int]{TTL: time.Second})
+	c.Set(1, 10)
+
+	clock.Advance(time.Second)
+
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+// GetOrLoad
+
+func TestCache_GetOrLoad__should_load_missing_value(t *testing.T) {
+	c, _ := testCache(Options[int, int]{
+		Loader: func(ctx async.Context, key int) (int, status.Status) {
+			return key * 10, status.OK
+		},
+	})
+
+	v, st := c.GetOrLoad(async.NoContext(), 1)
+	require.True(t, st.OK())
+	assert.Equal(t, 10, v)
+
+	v, ok := c.Get(1)
+	require.True(t, ok)
+	assert.Equal(t, 10, v)
+}
+
+func TestCache_GetOrLoad__should_deduplicate_concurrent_loads(t *testing.T) {
+	var loads atomic.Int32
+	release := make(chan struct{})
+
+	c, _ := testCache(Options[int, int]{
+		Loader: func(ctx async.Context, key int) (int, status.Status) {
+			loads.Add(1)
+			<-release
+			return key * 10, status.OK
+		},
+	})
+
+	n := 10
+	wg := sync.WaitGroup{}
+	results := make([]int, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, st := c.GetOrLoad(async.NoContext(), 1)
+			if st.OK() {
+				results[i] = v
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+	for _, v := range results {
+		assert.Equal(t, 10, v)
+	}
+}
+
+func TestCache_GetOrLoad__should_return_loader_error(t *testing.T) {
+	c, _ := testCache(Options[int, int]{
+		Loader: func(ctx async.Context, key int) (int, status.Status) {
+			return 0, status.NotFound("not found")
+		},
+	})
+
+	_, st := c.GetOrLoad(async.NoContext(), 1)
+	assert.Equal(t, status.CodeNotFound, st.Code)
+	assert.Equal(t, 0, c.Len())
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Loads)
+	assert.Equal(t, int64(1), stats.LoadErrors)
+}
+
+func TestCache_GetOrLoad__should_return_unsupported_when_no_loader(t *testing.T) {
+	c, _ := testCache(Options[int, int]{})
+
+	_, st := c.GetOrLoad(async.NoContext(), 1)
+	assert.Equal(t, status.CodeUnsupported, st.Code)
+}
+
+func TestCache_GetOrLoad__should_refresh_ahead(t *testing.T) {
+	var loads atomic.Int32
+
+	c, clock := testCache(Options[int, int]{
+		TTL:          10 * time.Second,
+		RefreshAhead: 5 * time.Second,
+		Loader: func(ctx async.Context, key int) (int, status.Status) {
+			n := loads.Add(1)
+			return int(n), status.OK
+		},
+	})
+
+	v, st := c.GetOrLoad(async.NoContext(), 1)
+	require.True(t, st.OK())
+	assert.Equal(t, 1, v)
+
+	// Return old value, refresh in background
+	clock.Advance(6 * time.Second)
+	v, ok := c.Get(1)
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// Await refreshed value
+	for i := 0; i < 100; i++ {
+		v, _ = c.Get(1)
+		if v == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 2, v)
+	assert.Equal(t, int32(2), loads.Load())
+}
+
+func TestCache_GetOrLoad__should_refresh_ahead_again_after_load_error(t *testing.T) {
+	var loads atomic.Int32
+
+	c, clock := testCache(Options[int, int]{
+		TTL:          10 * time.Second,
+		RefreshAhead: 5 * time.Second,
+		Loader: func(ctx async.Context, key int) (int, status.Status) {
+			n := loads.Add(1)
+			if n == 2 {
+				return 0, status.Unavailable("test")
+			}
+			return int(n), status.OK
+		},
+	})
+
+	v, st := c.GetOrLoad(async.NoContext(), 1)
+	require.True(t, st.OK())
+	assert.Equal(t, 1, v)
+
+	// Fail first refresh
+	clock.Advance(6 * time.Second)
+	c.Get(1)
+
+	for i := 0; i < 100 && c.Stats().LoadErrors == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	require.Equal(t, int64(1), c.Stats().LoadErrors)
+
+	// Refresh again
+	for i := 0; i < 100; i++ {
+		v, _ = c.Get(1)
+		if v == 3 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 3, v)
+}
+
+// Set
+
+func TestCache_Set__should_replace_value(t *testing.T) {
+	var reasons []EvictReason
+
+	c, _ := testCache(Options[int, int]{
+		OnEvict: func(key int, value int, reason EvictReason) {
+			reasons = append(reasons, reason)
+		},
+	})
+	c.Set(1, 10)
+	c.Set(1, 20)
+
+	v, ok := c.Get(1)
+	require.True(t, ok)
+	assert.Equal(t, 20, v)
+	assert.Equal(t, []EvictReason{EvictReplaced}, reasons)
+}
+
+func TestCache_SetTTL__should_override_default_ttl(t *testing.T) {
+	c, clock := testCache(Options[int, int]{TTL: time.Second})
+	c.SetTTL(1, 10, time.Minute)
+
+	clock.Advance(time.Second)
+
+	ok := c.Contains(1)
+	assert.True(t, ok)
+}
+
+// Delete
+
+func TestCache_Delete__should_delete_entry(t *testing.T) {
+	var reasons []EvictReason
+
+	c, _ := testCache(Options[int, int]{
+		OnEvict: func(key int, value int, reason EvictReason) {
+			reasons = append(reasons, reason)
+		},
+	})
+	c.Set(1, 10)
+
+	ok := c.Delete(1)
+	assert.True(t, ok)
+	assert.False(t, c.Contains(1))
+	assert.Equal(t, []EvictReason{EvictDeleted}, reasons)
+
+	ok = c.Delete(1)
+	assert.False(t, ok)
+}
+
+func TestCache_DeleteExpired__should_delete_expired_entries(t *testing.T) {
+	c, clock := testCache(Options[int, int]{TTL: time.Second})
+	for i := 0; i < 10; i++ {
+		c.Set(i, i)
+	}
+	c.SetTTL(100, 100, time.Minute)
+
+	clock.Advance(time.Second)
+	c.DeleteExpired()
+
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, int64(10), c.Stats().Evictions)
+}
+
+// Eviction
+
+func TestCache__should_evict_least_recently_used_entries(t *testing.T) {
+	var evicted []int
+
+	c, _ := testCache(Options[int, int]{
+		MaxEntries: 10,
+		Policy:     PolicyLRU,
+		OnEvict: func(key int, value int, reason EvictReason) {
+			evicted = append(evicted, key)
+		},
+	})
+	for i := 0; i < 10; i++ {
+		c.Set(i, i)
+	}
+
+	c.Get(0)
+	c.Set(10, 10)
+
+	assert.Equal(t, 10, c.Len())
+	assert.Equal(t, []int{1}, evicted)
+	assert.True(t, c.Contains(0))
+}
+
+func TestCache__should_keep_frequent_entries_with_tinylfu(t *testing.T) {
+	c, _ := testCache(Options[int, int]{MaxEntries: 100})
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+
+	// Access hot keys
+	for j := 0; j < 5; j++ {
+		for i := 0; i < 50; i++ {
+			c.Get(i)
+		}
+	}
+
+	// Scan new keys
+	for i := 1000; i < 1100; i++ {
+		c.Set(i, i)
+	}
+
+	for i := 0; i < 50; i++ {
+		assert.True(t, c.Contains(i), i)
+	}
+	assert.LessOrEqual(t, c.Len(), 100)
+}
+
+func TestCache__should_evict_by_cost(t *testing.T) {
+	c, _ := testCache(Options[int, int]{
+		MaxCost: 100,
+		Policy:  PolicyLRU,
+		Cost: func(key int, value int) int64 {
+			return int64(value)
+		},
+	})
+
+	c.Set(1, 60)
+	c.Set(2, 30)
+	c.Set(3, 20)
+
+	assert.False(t, c.Contains(1))
+	assert.True(t, c.Contains(2))
+	assert.True(t, c.Contains(3))
+
+	// Reject entry larger than capacity
+	c.Set(4, 200)
+	assert.False(t, c.Contains(4))
+}
+
+func TestCache_Set__should_delete_previous_value_when_rejecting_entry(t *testing.T) {
+	c, _ := testCache(Options[int, int]{
+		MaxCost: 100,
+		Policy:  PolicyLRU,
+		Cost: func(key int, value int) int64 {
+			return int64(value)
+		},
+	})
+
+	c.Set(1, 10)
+	c.Set(1, 200)
+
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+}
+
+func TestCache_Set__should_reject_entry_larger_than_shard_share(t *testing.T) {
+	c, _ := testCache(Options[int, int]{
+		MaxCost: 1 << 20,
+		Policy:  PolicyLRU,
+		Cost: func(key int, value int) int64 {
+			return int64(value)
+		},
+	})
+
+	share := c.shards[0].capacity
+	assert.Equal(t, int64(1<<20), share*int64(len(c.shards)))
+
+	c.Set(1, int(share))
+	c.Set(2, int(share)+1)
+
+	assert.True(t, c.Contains(1))
+	assert.False(t, c.Contains(2))
+}
+
+// Ref
+
+func TestRefCache__should_release_evicted_references(t *testing.T) {
+	var freed atomic.Int32
+	newRef := func(v int) ref.R[int] {
+		return ref.NewFree(v, func() { freed.Add(1) })
+	}
+
+	c := NewRefCache(Options[int, ref.R[int]]{
+		MaxEntries: 10,
+		Policy:     PolicyLRU,
+	})
+
+	for i := 0; i < 11; i++ {
+		r := newRef(i)
+		c.Set(i, r)
+		r.Release()
+	}
+	assert.Equal(t, int32(1), freed.Load())
+
+	// Retain returned reference
+	r, ok := c.Get(1)
+	require.True(t, ok)
+	c.Delete(1)
+	assert.Equal(t, int32(1), freed.Load())
+	assert.Equal(t, 1, r.Unwrap())
+
+	r.Release()
+	assert.Equal(t, int32(2), freed.Load())
+
+	c.Clear()
+	assert.Equal(t, int32(11), freed.Load())
+}
+
+func TestRefCache_GetOrLoad__should_retain_loaded_reference(t *testing.T) {
+	var freed atomic.Int32
+
+	c := NewRefCache(Options[int, ref.R[int]]{
+		Loader: func(ctx async.Context, key int) (ref.R[int], status.Status) {
+			r := ref.NewFree(key, func() { freed.Add(1) })
+			return r, status.OK
+		},
+	})
+
+	r, st := c.GetOrLoad(async.NoContext(), 1)
+	require.True(t, st.OK())
+	assert.Equal(t, int64(2), r.Refcount())
+
+	r.Release()
+	c.Clear()
+	assert.Equal(t, int32(1), freed.Load())
+}
+
+// Stats
+
+func TestCache_Stats__should_return_hits_and_misses(t *testing.T) {
+	c, _ := testCache(Options[int, int]{})
+	c.Set(1, 1)
+
+	c.Get(1)
+	c.Get(1)
+	c.Get(2)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.InDelta(t, 2.0/3, stats.HitRatio(), 0.001)
+}
+
+func TestRefCache_Stats__should_count_one_miss_per_load(t *testing.T) {
+	c := NewRefCache(Options[int, ref.R[int]]{
+		Loader: func(ctx async.Context, key int) (ref.R[int], status.Status) {
+			return ref.NewNoop(key), status.OK
+		},
+	})
+
+	r, st := c.GetOrLoad(async.NoContext(), 1)
+	require.True(t, st.OK())
+	r.Release()
+
+	stats := c.Stats()
+	assert.Equal(t, int64(0), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+}
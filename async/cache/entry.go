@@ -0,0 +1,84 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	hash    uint32
+	cost    int64
+	expires int64 // unix nanos, zero means never
+
+	refreshing bool // refresh-ahead has been started
+
+	// policy list
+	list *entryList[K, V]
+	prev *entry[K, V]
+	next *entry[K, V]
+}
+
+func (e *entry[K, V]) expired(now int64) bool {
+	return e.expires != 0 && now >= e.expires
+}
+
+// list
+
+// entryList is an intrusive doubly linked list of entries, the front is the most recent entry.
+type entryList[K comparable, V any] struct {
+	head *entry[K, V]
+	tail *entry[K, V]
+	cost int64
+}
+
+func (l *entryList[K, V]) back() *entry[K, V] {
+	return l.tail
+}
+
+func (l *entryList[K, V]) pushFront(e *entry[K, V]) {
+	e.list = l
+	e.prev = nil
+	e.next = l.head
+
+	if l.head != nil {
+		l.head.prev = e
+	} else {
+		l.tail = e
+	}
+
+	l.head = e
+	l.cost += e.cost
+}
+
+func (l *entryList[K, V]) remove(e *entry[K, V]) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		l.head = e.next
+	}
+
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		l.tail = e.prev
+	}
+
+	l.cost -= e.cost
+	e.list = nil
+	e.prev = nil
+	e.next = nil
+}
+
+func (l *entryList[K, V]) moveToFront(e *entry[K, V]) {
+	if l.head == e {
+		return
+	}
+
+	l.remove(e)
+	l.pushFront(e)
+}
+
+func (l *entryList[K, V]) clear() {
+	*l = entryList[K, V]{}
+}
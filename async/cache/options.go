@@ -0,0 +1,103 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"time"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/async/asyncmap"
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
+)
+
+// Options specifies the cache options.
+type Options[K comparable, V any] struct {
+	// MaxEntries is the max number of entries, zero means unlimited.
+	// The option is ignored when MaxCost is set, the limit is divided between the shards
+	// as MaxCost.
+	MaxEntries int
+
+	// MaxCost is the max total cost of entries, zero means unlimited.
+	//
+	// The limit is divided equally between the shards, each shard enforces its own share.
+	// An entry which costs more than a shard share is rejected even when it costs less
+	// than MaxCost, and entries which hash to one shard cannot fill the whole limit.
+	// The number of shards is the number of CPUs rounded up to a power of two, reduced
+	// until each shard share is at least 64, a small limit results in a single shard.
+	MaxCost int64
+
+	// Cost returns an entry cost, nil means each entry costs 1.
+	Cost func(key K, value V) int64
+
+	// TTL is the default entry time-to-live, zero means no expiration.
+	TTL time.Duration
+
+	// Policy is the eviction policy, the default is W-TinyLFU.
+	Policy Policy
+
+	// Loader loads missing values in GetOrLoad, optional.
+	// Concurrent loads of the same key are deduplicated.
+	Loader func(ctx async.Context, key K) (V, status.Status)
+
+	// RefreshAhead starts reloading an entry in the background when its remaining
+	// time-to-live is less than this duration, zero disables refresh-ahead.
+	// Refresh-ahead requires a loader.
+	RefreshAhead time.Duration
+
+	// OnEvict is called when an entry is removed from the cache, optional.
+	// The callback is called outside of the cache locks.
+	OnEvict func(key K, value V, reason EvictReason)
+
+	// Clock is the clock used for expiration, nil means the real clock.
+	Clock clockwork.Clock
+
+	// Hash returns a key hash, nil means the default hash function,
+	// see [asyncmap.Hash].
+	Hash asyncmap.HashFunc[K]
+}
+
+// Policy is a cache eviction policy.
+type Policy int
+
+const (
+	// PolicyTinyLFU is a W-TinyLFU eviction policy, the default.
+	PolicyTinyLFU Policy = iota
+
+	// PolicyLRU is a least recently used eviction policy.
+	PolicyLRU
+)
+
+// EvictReason specifies why an entry has been removed from the cache.
+type EvictReason int
+
+const (
+	// EvictCapacity indicates that an entry has been evicted by the eviction policy.
+	EvictCapacity EvictReason = iota
+
+	// EvictExpired indicates that an entry has expired.
+	EvictExpired
+
+	// EvictReplaced indicates that an entry has been replaced by a new value.
+	EvictReplaced
+
+	// EvictDeleted indicates that an entry has been deleted or cleared.
+	EvictDeleted
+)
+
+// String returns a reason name.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictReplaced:
+		return "replaced"
+	case EvictDeleted:
+		return "deleted"
+	}
+	return "unknown"
+}
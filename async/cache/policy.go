@@ -0,0 +1,78 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+// policy is a shard eviction policy, must be externally synchronized.
+type policy[K comparable, V any] interface {
+	// add adds a new entry, and calls evict for each evicted entry.
+	// The new entry itself can be evicted if it is not admitted.
+	add(e *entry[K, V], evict func(*entry[K, V]))
+
+	// access records an entry hit.
+	access(e *entry[K, V])
+
+	// record records a key access on a miss.
+	record(hash uint32)
+
+	// remove removes an entry.
+	remove(e *entry[K, V])
+
+	// clear removes all entries.
+	clear()
+}
+
+func newPolicy[K comparable, V any](p Policy, capacity int64) policy[K, V] {
+	if capacity <= 0 {
+		return newLRUPolicy[K, V](0)
+	}
+
+	switch p {
+	case PolicyLRU:
+		return newLRUPolicy[K, V](capacity)
+	default:
+		return newTinyLFUPolicy[K, V](capacity)
+	}
+}
+
+// lru
+
+var _ policy[int, int] = (*lruPolicy[int, int])(nil)
+
+// lruPolicy evicts the least recently used entries, zero capacity means unlimited.
+type lruPolicy[K comparable, V any] struct {
+	capacity int64
+	list     entryList[K, V]
+}
+
+func newLRUPolicy[K comparable, V any](capacity int64) *lruPolicy[K, V] {
+	return &lruPolicy[K, V]{capacity: capacity}
+}
+
+func (p *lruPolicy[K, V]) add(e *entry[K, V], evict func(*entry[K, V])) {
+	p.list.pushFront(e)
+	if p.capacity == 0 {
+		return
+	}
+
+	for p.list.cost > p.capacity {
+		victim := p.list.back()
+		p.list.remove(victim)
+		evict(victim)
+	}
+}
+
+func (p *lruPolicy[K, V]) access(e *entry[K, V]) {
+	p.list.moveToFront(e)
+}
+
+func (p *lruPolicy[K, V]) record(hash uint32) {}
+
+func (p *lruPolicy[K, V]) remove(e *entry[K, V]) {
+	p.list.remove(e)
+}
+
+func (p *lruPolicy[K, V]) clear() {
+	p.list.clear()
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+var _ policy[int, int] = (*tinyLFUPolicy[int, int])(nil)
+
+// tinyLFUPolicy is a W-TinyLFU eviction policy.
+//
+// New entries are added to a small LRU window. Entries evicted from the window
+// are candidates for the main segmented LRU, which consists of probation and protected
+// segments. A candidate is admitted only if its estimated frequency is greater than
+// the frequency of the main victim.
+//
+// See "TinyLFU: A Highly Efficient Cache Admission Policy"
+// https://arxiv.org/abs/1512.00727
+type tinyLFUPolicy[K comparable, V any] struct {
+	sketch *sketch
+
+	windowCap    int64
+	mainCap      int64
+	protectedCap int64
+
+	window    entryList[K, V]
+	probation entryList[K, V]
+	protected entryList[K, V]
+}
+
+func newTinyLFUPolicy[K comparable, V any](capacity int64) *tinyLFUPolicy[K, V] {
+	windowCap := max(capacity/100, 1)
+	mainCap := max(capacity-windowCap, 0)
+	protectedCap := mainCap * 8 / 10
+
+	return &tinyLFUPolicy[K, V]{
+		sketch: newSketch(capacity),
+
+		windowCap:    windowCap,
+		mainCap:      mainCap,
+		protectedCap: protectedCap,
+	}
+}
+
+func (p *tinyLFUPolicy[K, V]) add(e *entry[K, V], evict func(*entry[K, V])) {
+	p.sketch.increment(e.hash)
+	p.window.pushFront(e)
+
+	// Move window overflow to main
+	for p.window.cost > p.windowCap {
+		candidate := p.window.back()
+		p.window.remove(candidate)
+		p.admit(candidate, evict)
+	}
+}
+
+func (p *tinyLFUPolicy[K, V]) access(e *entry[K, V]) {
+	p.sketch.increment(e.hash)
+
+	switch e.list {
+	case &p.window:
+		p.window.moveToFront(e)
+
+	case &p.probation:
+		// Promote to protected
+		p.probation.remove(e)
+		p.protected.pushFront(e)
+
+		// Demote protected overflow to probation
+		for p.protected.cost > p.protectedCap {
+			demoted := p.protected.back()
+			p.protected.remove(demoted)
+			p.probation.pushFront(demoted)
+		}
+
+	case &p.protected:
+		p.protected.moveToFront(e)
+	}
+}
+
+func (p *tinyLFUPolicy[K, V]) record(hash uint32) {
+	p.sketch.increment(hash)
+}
+
+func (p *tinyLFUPolicy[K, V]) remove(e *entry[K, V]) {
+	if e.list != nil {
+		e.list.remove(e)
+	}
+}
+
+func (p *tinyLFUPolicy[K, V]) clear() {
+	p.sketch.reset()
+	p.window.clear()
+	p.probation.clear()
+	p.protected.clear()
+}
+
+// private
+
+// admit adds a window candidate to main, or evicts it when its frequency is not
+// greater than the frequency of the main victims.
+func (p *tinyLFUPolicy[K, V]) admit(candidate *entry[K, V], evict func(*entry[K, V])) {
+	if candidate.cost > p.mainCap {
+		evict(candidate)
+		return
+	}
+
+	freq := p.sketch.estimate(candidate.hash)
+	for p.mainCost()+candidate.cost > p.mainCap {
+		victim := p.probation.back()
+		if victim == nil {
+			victim = p.protected.back()
+		}
+
+		// Reject candidate
+		if p.sketch.estimate(victim.hash) >= freq {
+			evict(candidate)
+			return
+		}
+
+		// Evict victim
+		victim.list.remove(victim)
+		evict(victim)
+	}
+
+	p.probation.pushFront(candidate)
+}
+
+func (p *tinyLFUPolicy[K, V]) mainCost() int64 {
+	return p.probation.cost + p.protected.cost
+}
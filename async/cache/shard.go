@@ -0,0 +1,195 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import "sync"
+
+type shard[K comparable, V any] struct {
+	c *cache[K, V]
+
+	mu       sync.Mutex
+	items    map[K]*entry[K, V]
+	policy   policy[K, V]
+	capacity int64
+
+	evicted []evicted[K, V]      // collected evicted entries, notified outside of the lock
+	evictFn func(e *entry[K, V]) // cached evictCapacity method value
+}
+
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+func newShard[K comparable, V any](c *cache[K, V], capacity int64, p Policy) *shard[K, V] {
+	s := &shard[K, V]{
+		c:        c,
+		items:    make(map[K]*entry[K, V]),
+		policy:   newPolicy[K, V](p, capacity),
+		capacity: capacity,
+	}
+	s.evictFn = s.evictCapacity
+	return s
+}
+
+func (s *shard[K, V]) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.items)
+}
+
+func (s *shard[K, V]) clear() []evicted[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.items {
+		s.evict(e, EvictDeleted)
+	}
+
+	clear(s.items)
+	s.policy.clear()
+	return s.flush()
+}
+
+func (s *shard[K, V]) contains(key K, now int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.items[key]
+	if !ok {
+		return false
+	}
+	return !e.expired(now)
+}
+
+// get returns a value and retains it, and returns true if the entry should be refreshed.
+func (s *shard[K, V]) get(key K, hash uint32, now int64) (v V, ok bool, refresh bool,
+	evicted []evicted[K, V]) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Get entry
+	e, ok := s.items[key]
+	if !ok {
+		s.policy.record(hash)
+		return v, false, false, nil
+	}
+
+	// Delete expired
+	if e.expired(now) {
+		s.policy.record(hash)
+		s.delete(e, EvictExpired)
+		return v, false, false, s.flush()
+	}
+
+	// Maybe refresh ahead
+	if ahead := s.c.refreshAhead; ahead > 0 && e.expires != 0 && !e.refreshing {
+		if e.expires-now <= ahead {
+			e.refreshing = true
+			refresh = true
+		}
+	}
+
+	// Record access, retain value
+	s.policy.access(e)
+	s.c.retain(e.value)
+	return e.value, true, refresh, nil
+}
+
+// set sets an entry, the value must be already retained.
+func (s *shard[K, V]) set(e *entry[K, V]) []evicted[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Replace previous entry
+	if prev, ok := s.items[e.key]; ok {
+		s.delete(prev, EvictReplaced)
+	}
+
+	// Reject entry larger than shard, the previous entry is already deleted
+	if s.capacity > 0 && e.cost > s.capacity {
+		s.evict(e, EvictCapacity)
+		return s.flush()
+	}
+
+	// Add entry
+	s.items[e.key] = e
+	s.policy.add(e, s.evictFn)
+	return s.flush()
+}
+
+// refreshFailed clears the refresh-ahead flag, so that the entry can be refreshed again.
+func (s *shard[K, V]) refreshFailed(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.items[key]
+	if ok {
+		e.refreshing = false
+	}
+}
+
+func (s *shard[K, V]) remove(key K) (bool, []evicted[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.items[key]
+	if !ok {
+		return false, nil
+	}
+
+	s.delete(e, EvictDeleted)
+	return true, s.flush()
+}
+
+func (s *shard[K, V]) deleteExpired(now int64) []evicted[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.items {
+		if e.expired(now) {
+			s.delete(e, EvictExpired)
+		}
+	}
+	return s.flush()
+}
+
+// private
+
+// delete deletes an entry from the items and the policy.
+func (s *shard[K, V]) delete(e *entry[K, V], reason EvictReason) {
+	delete(s.items, e.key)
+	s.policy.remove(e)
+	s.evict(e, reason)
+}
+
+// evictCapacity is called by the policy which has already removed the entry.
+func (s *shard[K, V]) evictCapacity(e *entry[K, V]) {
+	delete(s.items, e.key)
+	s.evict(e, EvictCapacity)
+}
+
+func (s *shard[K, V]) evict(e *entry[K, V], reason EvictReason) {
+	ev := evicted[K, V]{
+		key:    e.key,
+		value:  e.value,
+		reason: reason,
+	}
+	s.evicted = append(s.evicted, ev)
+}
+
+// flush returns the evicted entries and resets the buffer.
+func (s *shard[K, V]) flush() []evicted[K, V] {
+	if len(s.evicted) == 0 {
+		return nil
+	}
+
+	result := s.evicted
+	s.evicted = nil
+	return result
+}
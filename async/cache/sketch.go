@@ -0,0 +1,101 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+const (
+	sketchDepth    = 4
+	sketchMaxCount = 15
+	sketchMinWidth = 16
+	sketchMaxWidth = 1 << 16
+)
+
+var sketchSeeds = [sketchDepth]uint32{
+	0x9e3779b1,
+	0x85ebca77,
+	0xc2b2ae3d,
+	0x27d4eb2f,
+}
+
+// sketch is a count-min sketch which estimates key access frequencies.
+//
+// The counters are saturated at 15, and are halved after a sample of accesses
+// to age the frequencies.
+type sketch struct {
+	rows   [sketchDepth][]uint8
+	mask   uint32
+	size   int // current number of increments
+	sample int // number of increments after which counters are halved
+}
+
+func newSketch(capacity int64) *sketch {
+	width := sketchMinWidth
+	for int64(width) < capacity && width < sketchMaxWidth {
+		width <<= 1
+	}
+
+	s := &sketch{
+		mask:   uint32(width - 1),
+		sample: width * 10,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// estimate returns the estimated key frequency.
+func (s *sketch) estimate(hash uint32) uint8 {
+	count := uint8(sketchMaxCount)
+	for i := range s.rows {
+		j := s.index(hash, i)
+		count = min(count, s.rows[i][j])
+	}
+	return count
+}
+
+// increment increments the key frequency, and ages all counters when the sample is full.
+func (s *sketch) increment(hash uint32) {
+	added := false
+	for i := range s.rows {
+		j := s.index(hash, i)
+		if s.rows[i][j] < sketchMaxCount {
+			s.rows[i][j]++
+			added = true
+		}
+	}
+	if !added {
+		return
+	}
+
+	s.size++
+	if s.size >= s.sample {
+		s.age()
+	}
+}
+
+// private
+
+func (s *sketch) index(hash uint32, row int) uint32 {
+	h := hash * sketchSeeds[row]
+	h ^= h >> 16
+	return h & s.mask
+}
+
+func (s *sketch) age() {
+	for i := range s.rows {
+		row := s.rows[i]
+		for j := range row {
+			row[j] >>= 1
+		}
+	}
+	s.size /= 2
+}
+
+func (s *sketch) reset() {
+	for i := range s.rows {
+		clear(s.rows[i])
+	}
+	s.size = 0
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import "sync/atomic"
+
+// Stats holds cache statistics.
+type Stats struct {
+	Hits       int64
+	Misses     int64
+	Loads      int64
+	LoadErrors int64
+	Evictions  int64 // capacity and expiration evictions
+}
+
+// HitRatio returns the ratio of hits to all requests, or 0 if no requests.
+func (s Stats) HitRatio() float64 {
+	n := s.Hits + s.Misses
+	if n == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(n)
+}
+
+// internal
+
+type stats struct {
+	hits       atomic.Int64
+	misses     atomic.Int64
+	loads      atomic.Int64
+	loadErrors atomic.Int64
+	evictions  atomic.Int64
+}
+
+func (s *stats) snapshot() Stats {
+	return Stats{
+		Hits:       s.hits.Load(),
+		Misses:     s.misses.Load(),
+		Loads:      s.loads.Load(),
+		LoadErrors: s.loadErrors.Load(),
+		Evictions:  s.evictions.Load(),
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"sync"
+
+	"github.com/basecomplextech/baselibrary/async/internal/cond"
+)
+
+// Cond is a condition variable which can be waited on with a context.
+//
+// Cond is similar to [sync.Cond], but its wait can be cancelled, and it can be used
+// in select statements via WaitChan.
+//
+// Example:
+//
+//	mu := &sync.Mutex{}
+//	cond := async.NewCond(mu)
+//
+//	mu.Lock()
+//	defer mu.Unlock()
+//
+//	for !ready {
+//		if st := cond.Wait(ctx); !st.OK() {
+//			return st
+//		}
+//	}
+type Cond = cond.Cond
+
+// NewCond returns a new condition variable with a locker.
+func NewCond(l sync.Locker) Cond {
+	return cond.New(l)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/basecomplextech/baselibrary/async/internal/context"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type (
@@ -46,11 +47,21 @@ func TimeoutContext(timeout time.Duration) Context {
 	return context.Timeout(timeout)
 }
 
+// TimeoutContextWith returns a context with a timeout which uses a clock.
+func TimeoutContextWith(clock clockwork.Clock, timeout time.Duration) Context {
+	return context.TimeoutWith(clock, timeout)
+}
+
 // DeadlineContext returns a context with a deadline.
 func DeadlineContext(deadline time.Time) Context {
 	return context.Deadline(deadline)
 }
 
+// DeadlineContextWith returns a context with a deadline which uses a clock.
+func DeadlineContextWith(clock clockwork.Clock, deadline time.Time) Context {
+	return context.DeadlineWith(clock, deadline)
+}
+
 // Next
 
 // NextContext returns a child context.
@@ -63,14 +74,58 @@ func NextTimeoutContext(parent Context, timeout time.Duration) Context {
 	return context.NextTimeout(parent, timeout)
 }
 
+// NextTimeoutContextWith returns a child context with a timeout which uses a clock.
+func NextTimeoutContextWith(clock clockwork.Clock, parent Context, timeout time.Duration) Context {
+	return context.NextTimeoutWith(clock, parent, timeout)
+}
+
 // NextDeadlineContext returns a child context with a deadline.
 func NextDeadlineContext(parent Context, deadline time.Time) Context {
 	return context.NextDeadline(parent, deadline)
 }
 
+// NextDeadlineContextWith returns a child context with a deadline which uses a clock.
+func NextDeadlineContextWith(clock clockwork.Clock, parent Context, deadline time.Time) Context {
+	return context.NextDeadlineWith(clock, parent, deadline)
+}
+
+// Debug
+
+// SetContextDebug enables or disables the context debug mode.
+//
+// In the debug mode contexts record the stack of the first cancel call,
+// it can be retrieved via [ContextCancelStack]. The debug mode is slow, and should
+// be used only for debugging.
+func SetContextDebug(enabled bool) {
+	context.SetDebug(enabled)
+}
+
+// ContextCancelStack returns the stack of the first context cancel call, or nil.
+//
+// The stack is recorded only in the debug mode, see [SetContextDebug].
+func ContextCancelStack(ctx Context) []byte {
+	return context.CancelStack(ctx)
+}
+
+// Value
+
+// WithValue returns a child context with a value associated with a key.
+// The key must be comparable, and should not be of a built-in type to avoid collisions.
+func WithValue(parent Context, key any, value any) CancelContext {
+	return context.WithValue(parent, key, value)
+}
+
 // Standard
 
 // StdContext returns a standard library context from an async one.
 func StdContext(ctx Context) context_.Context {
 	return context.Std(ctx)
 }
+
+// FromStd returns an async context from a standard library one.
+//
+// The context is cancelled when the standard context is done, and inherits its deadline
+// and values. The context must be freed, freeing it does not cancel the standard context.
+func FromStd(ctx context_.Context) Context {
+	return context.FromStd(ctx)
+}
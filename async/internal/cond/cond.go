@@ -0,0 +1,174 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cond
+
+import (
+	"sync"
+
+	"github.com/basecomplextech/baselibrary/async/internal/context"
+	"github.com/basecomplextech/baselibrary/status"
+)
+
+// Cond is a condition variable which can be waited on with a context.
+//
+// Cond is similar to [sync.Cond], but its wait can be cancelled, and it can be used
+// in select statements via WaitChan.
+//
+// Example:
+//
+//	mu := &sync.Mutex{}
+//	cond := async.NewCond(mu)
+//
+//	mu.Lock()
+//	defer mu.Unlock()
+//
+//	for !ready {
+//		if st := cond.Wait(ctx); !st.OK() {
+//			return st
+//		}
+//	}
+type Cond interface {
+	// Wait atomically unlocks the locker and waits for a signal or the context cancellation,
+	// then locks the locker again before returning. The locker must be held by the caller.
+	//
+	// The method returns the context status when cancelled. The locker is always locked on return.
+	Wait(ctx context.Context) status.Status
+
+	// WaitChan returns a channel which is closed on the next signal or broadcast.
+	//
+	// The channel should be obtained while holding the locker, and awaited after
+	// unlocking it. The channel can be abandoned, so Signal closes all channels
+	// queued before the next Wait waiter, and the caller must recheck the condition.
+	WaitChan() <-chan struct{}
+
+	// Signal wakes one Wait waiter if any, and all WaitChan waiters queued before it.
+	Signal()
+
+	// Broadcast wakes all waiters.
+	Broadcast()
+}
+
+// New returns a new condition variable with a locker.
+func New(l sync.Locker) Cond {
+	return newCond(l)
+}
+
+// internal
+
+var _ Cond = (*cond)(nil)
+
+type cond struct {
+	l sync.Locker
+
+	mu      sync.Mutex
+	waiters []waiter // fifo
+}
+
+type waiter struct {
+	ch   chan struct{}
+	wait bool // blocked in Wait, false for WaitChan
+}
+
+func newCond(l sync.Locker) *cond {
+	return &cond{l: l}
+}
+
+// Wait atomically unlocks the locker and waits for a signal or the context cancellation,
+// then locks the locker again before returning. The locker must be held by the caller.
+//
+// The method returns the context status when cancelled. The locker is always locked on return.
+func (c *cond) Wait(ctx context.Context) status.Status {
+	ch := c.add(true)
+
+	c.l.Unlock()
+	defer c.l.Lock()
+
+	select {
+	case <-ch:
+		return status.OK
+	case <-ctx.Wait():
+	}
+
+	c.cancel(ch)
+	return ctx.Status()
+}
+
+// WaitChan returns a channel which is closed on the next signal or broadcast.
+//
+// The channel should be obtained while holding the locker, and awaited after
+// unlocking it. The channel can be abandoned, so Signal closes all channels
+// queued before the next Wait waiter, and the caller must recheck the condition.
+func (c *cond) WaitChan() <-chan struct{} {
+	return c.add(false)
+}
+
+// Signal wakes one Wait waiter if any, and all WaitChan waiters queued before it.
+//
+// Abandoned WaitChan waiters cannot be detected, so they do not consume the signal.
+func (c *cond) Signal() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for n < len(c.waiters) {
+		w := c.waiters[n]
+		c.waiters[n] = waiter{}
+		close(w.ch)
+		n++
+
+		if w.wait {
+			break
+		}
+	}
+	c.waiters = c.waiters[n:]
+}
+
+// Broadcast wakes all waiters.
+func (c *cond) Broadcast() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, w := range c.waiters {
+		close(w.ch)
+		c.waiters[i] = waiter{}
+	}
+	c.waiters = c.waiters[:0]
+}
+
+// private
+
+func (c *cond) add(wait bool) chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan struct{})
+	c.waiters = append(c.waiters, waiter{ch: ch, wait: wait})
+	return ch
+}
+
+// cancel removes a Wait waiter, or passes a signal to the next waiter if already signalled.
+func (c *cond) cancel(ch chan struct{}) {
+	if ok := c.remove(ch); !ok {
+		c.Signal()
+	}
+}
+
+// remove removes a waiter, returns false if the waiter has been already signalled.
+func (c *cond) remove(ch chan struct{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, w := range c.waiters {
+		if w.ch != ch {
+			continue
+		}
+
+		copy(c.waiters[i:], c.waiters[i+1:])
+		c.waiters[len(c.waiters)-1] = waiter{}
+		c.waiters = c.waiters[:len(c.waiters)-1]
+		return true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cond
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/async/internal/context"
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/stretchr/testify/assert"
+)
+
+// Wait
+
+func TestCond_Wait__should_wait_for_signal(t *testing.T) {
+	mu := &sync.Mutex{}
+	c := New(mu)
+	ready := false
+
+	go func() {
+		time.Sleep(time.Millisecond * 5)
+
+		mu.Lock()
+		ready = true
+		mu.Unlock()
+
+		c.Signal()
+	}()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for !ready {
+		st := c.Wait(context.No())
+		if !st.OK() {
+			t.Fatal(st)
+		}
+	}
+}
+
+func TestCond_Wait__should_return_context_status_when_cancelled(t *testing.T) {
+	mu := &sync.Mutex{}
+	c := New(mu)
+
+	ctx := context.New()
+	defer ctx.Free()
+
+	go func() {
+		time.Sleep(time.Millisecond * 5)
+		ctx.Cancel()
+	}()
+
+	mu.Lock()
+	st := c.Wait(ctx)
+
+	assert.Equal(t, status.Cancelled, st)
+	assert.False(t, mu.TryLock())
+	mu.Unlock()
+}
+
+func TestCond_Wait__should_remove_cancelled_waiter(t *testing.T) {
+	mu := &sync.Mutex{}
+	c := newCond(mu)
+
+	ctx := context.Cancelled()
+	mu.Lock()
+	c.Wait(ctx)
+	mu.Unlock()
+
+	assert.Len(t, c.waiters, 0)
+}
+
+// Broadcast
+
+func TestCond_Broadcast__should_wake_all_waiters(t *testing.T) {
+	mu := &sync.Mutex{}
+	c := New(mu)
+
+	n := 10
+	wg := sync.WaitGroup{}
+	waiting := sync.WaitGroup{}
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		waiting.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			waiting.Done()
+			c.Wait(context.No())
+		}()
+	}
+
+	waiting.Wait()
+	mu.Lock()
+	c.Broadcast()
+	mu.Unlock()
+
+	wg.Wait()
+}
+
+// WaitChan
+
+func TestCond_WaitChan__should_close_channel_on_signal(t *testing.T) {
+	c := New(&sync.Mutex{})
+	ch := c.WaitChan()
+
+	select {
+	case <-ch:
+		t.Fatal("channel should not be closed")
+	default:
+	}
+
+	c.Signal()
+
+	select {
+	case <-ch:
+	default:
+		t.Fatal("channel should be closed")
+	}
+}
+
+func TestCond_Signal__should_not_lose_signal_on_abandoned_channel(t *testing.T) {
+	mu := &sync.Mutex{}
+	c := newCond(mu)
+	c.WaitChan() // abandoned
+
+	done := make(chan status.Status, 1)
+	go func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		done <- c.Wait(context.No())
+	}()
+
+	// Await waiter
+	for {
+		c.mu.Lock()
+		n := len(c.waiters)
+		c.mu.Unlock()
+
+		if n == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Signal()
+
+	select {
+	case st := <-done:
+		assert.True(t, st.OK())
+	case <-time.After(time.Second):
+		t.Fatal("signal lost")
+	}
+}
+
+func TestCond_Signal__should_close_channels_before_wait_waiter(t *testing.T) {
+	c := newCond(&sync.Mutex{})
+	ch0 := c.WaitChan()
+	ch1 := c.WaitChan()
+	c.add(true)
+	ch2 := c.WaitChan()
+
+	c.Signal()
+
+	for _, ch := range []<-chan struct{}{ch0, ch1} {
+		select {
+		case <-ch:
+		default:
+			t.Fatal("channel should be closed")
+		}
+	}
+	select {
+	case <-ch2:
+		t.Fatal("channel should not be closed")
+	default:
+	}
+	assert.Len(t, c.waiters, 1)
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package context
+
+import (
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
+)
+
+// NextTimeout
+
+func BenchmarkNextTimeout_Free(b *testing.B) {
+	parent := New()
+	defer parent.Free()
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			ctx := NextTimeout(parent, time.Second)
+			ctx.Free()
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkNextTimeout_Free_Wheel(b *testing.B) {
+	wheel := clockwork.NewWheel(time.Millisecond, 0)
+	defer wheel.Stop()
+
+	parent := New()
+	defer parent.Free()
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			ctx := NextTimeoutWith(wheel, parent, time.Second)
+			ctx.Free()
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
@@ -6,12 +6,14 @@ package context
 
 import (
 	context_ "context"
+	"reflect"
 	"sync/atomic"
 	"time"
 
 	"github.com/basecomplextech/baselibrary/collect/chans"
 	"github.com/basecomplextech/baselibrary/ref"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 // Context is an async cancellation context.
@@ -30,6 +32,12 @@ type Context interface {
 	// Status returns a cancellation status or OK.
 	Status() status.Status
 
+	// Deadline returns the time when the context will be cancelled, or false if no deadline.
+	Deadline() (time.Time, bool)
+
+	// Value returns a value associated with a key, or nil.
+	Value(key any) any
+
 	// Callbacks
 
 	// AddCallback adds a callback.
@@ -50,6 +58,10 @@ type CancelContext interface {
 
 	// Cancel cancels the context.
 	Cancel()
+
+	// CancelWithStatus cancels the context with a status, the status is propagated to children.
+	// The status should not be OK, and is replaced with Cancelled if it is.
+	CancelWithStatus(st status.Status)
 }
 
 // Callback is called when the context is cancelled.
@@ -79,13 +91,25 @@ func Cancelled() CancelContext {
 
 // Timeout returns a context with a timeout.
 func Timeout(timeout time.Duration) Context {
-	return newContextTimeout(nil /* no parent */, timeout)
+	return TimeoutWith(clockwork.Real(), timeout)
+}
+
+// TimeoutWith returns a context with a timeout which uses a clock.
+func TimeoutWith(clock clockwork.Clock, timeout time.Duration) Context {
+	deadline := clock.Now().Add(timeout)
+	info := timeoutInfo{after: timeout, deadline: deadline, relative: true}
+	return newContextDeadline(nil /* no parent */, clock, info)
 }
 
 // Deadline returns a context with a deadline.
 func Deadline(deadline time.Time) Context {
-	timeout := time.Until(deadline)
-	return newContextTimeout(nil /* no parent */, timeout)
+	return DeadlineWith(clockwork.Real(), deadline)
+}
+
+// DeadlineWith returns a context with a deadline which uses a clock.
+func DeadlineWith(clock clockwork.Clock, deadline time.Time) Context {
+	info := timeoutInfo{deadline: deadline}
+	return newContextDeadline(nil /* no parent */, clock, info)
 }
 
 // Next
@@ -97,13 +121,44 @@ func Next(parent Context) CancelContext {
 
 // NextTimeout returns a child context with a timeout.
 func NextTimeout(parent Context, timeout time.Duration) Context {
-	return newContextTimeout(parent, timeout)
+	return NextTimeoutWith(clockwork.Real(), parent, timeout)
+}
+
+// NextTimeoutWith returns a child context with a timeout which uses a clock.
+func NextTimeoutWith(clock clockwork.Clock, parent Context, timeout time.Duration) Context {
+	deadline := clock.Now().Add(timeout)
+	info := timeoutInfo{after: timeout, deadline: deadline, relative: true}
+	return newContextDeadline(parent, clock, info)
 }
 
 // NextDeadline returns a child context with a deadline.
 func NextDeadline(parent Context, deadline time.Time) Context {
-	timeout := time.Until(deadline)
-	return newContextTimeout(parent, timeout)
+	return NextDeadlineWith(clockwork.Real(), parent, deadline)
+}
+
+// NextDeadlineWith returns a child context with a deadline which uses a clock.
+func NextDeadlineWith(clock clockwork.Clock, parent Context, deadline time.Time) Context {
+	info := timeoutInfo{deadline: deadline}
+	return newContextDeadline(parent, clock, info)
+}
+
+// Value
+
+// WithValue returns a child context with a value associated with a key.
+// The key must be comparable, and should not be of a built-in type to avoid collisions.
+func WithValue(parent Context, key any, value any) CancelContext {
+	if key == nil {
+		panic("nil key")
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		panic("key is not comparable")
+	}
+
+	x := newContext(parent)
+	s := x.state.Load()
+	s.key = key
+	s.value = value
+	return x
 }
 
 // Standard
@@ -113,6 +168,17 @@ func Std(ctx Context) context_.Context {
 	return newStdContext(ctx)
 }
 
+// FromStd returns an async context from a standard library one.
+//
+// The context is cancelled when the standard context is done, and inherits its deadline
+// and values. The context must be freed, freeing it does not cancel the standard context.
+func FromStd(ctx context_.Context) Context {
+	if x, ok := ctx.(*stdContext); ok {
+		return newContext(x.ctx)
+	}
+	return newContextStd(ctx)
+}
+
 // internal
 
 var _ CancelContext = (*context)(nil)
@@ -125,6 +191,9 @@ type context struct {
 
 func newContext(parent Context) *context {
 	s := newState(parent)
+	if parent != nil {
+		s.deadline, _ = parent.Deadline()
+	}
 
 	x := &context{}
 	x.refs.Init(1)
@@ -137,27 +206,70 @@ func newContext(parent Context) *context {
 	return x
 }
 
-func newContextTimeout(parent Context, timeout time.Duration) *context {
+func newContextDeadline(parent Context, clock clockwork.Clock, info timeoutInfo) *context {
 	x := newContext(parent)
+	deadline := info.deadline
+
+	// Use earliest deadline
+	s := x.state.Load()
+	s.timeout = info
+	if s.deadline.IsZero() || deadline.Before(s.deadline) {
+		s.deadline = deadline
+	}
 
 	// Maybe already timed out
+	timeout := clock.Until(deadline)
 	if timeout <= 0 {
 		x.timeout()
 		return x
 	}
 
 	// Start timer
-	timer := time.AfterFunc(timeout, x.timeout)
-	s := x.state.Load()
+	timer := clock.AfterFunc(timeout, x.timeout)
 	s.timer.set(timer)
 	return x
 }
 
+func newContextStd(ctx context_.Context) *context {
+	x := newContext(nil /* no parent */)
+
+	s := x.state.Load()
+	s.std = ctx
+	s.deadline, _ = ctx.Deadline()
+
+	// Maybe already done
+	if err := ctx.Err(); err != nil {
+		x.cancel(stdStatus(err))
+		return x
+	}
+
+	// Skip non-cancellable context
+	if ctx.Done() == nil {
+		return x
+	}
+
+	// Cancel when done
+	stop := context_.AfterFunc(ctx, func() {
+		x.cancel(stdStatus(ctx.Err()))
+	})
+	s.stdStop.set(stop)
+	return x
+}
+
 // Cancel cancels the context.
 func (x *context) Cancel() {
 	x.cancel(status.Cancelled)
 }
 
+// CancelWithStatus cancels the context with a status, the status is propagated to children.
+// The status should not be OK, and is replaced with Cancelled if it is.
+func (x *context) CancelWithStatus(st status.Status) {
+	if st.OK() {
+		st = status.Cancelled
+	}
+	x.cancel(st)
+}
+
 // Done returns true if the context is cancelled.
 func (x *context) Done() bool {
 	s, ok := x.acquire()
@@ -193,6 +305,28 @@ func (x *context) Status() status.Status {
 	return st
 }
 
+// Deadline returns the time when the context will be cancelled, or false if no deadline.
+func (x *context) Deadline() (time.Time, bool) {
+	s, ok := x.acquire()
+	if !ok {
+		return time.Time{}, false
+	}
+	defer x.release()
+
+	return s.deadline, !s.deadline.IsZero()
+}
+
+// Value returns a value associated with a key, or nil.
+func (x *context) Value(key any) any {
+	s, ok := x.acquire()
+	if !ok {
+		return nil
+	}
+	defer x.release()
+
+	return s.lookup(key)
+}
+
 // Callbacks
 
 // AddCallback adds a callback.
@@ -262,7 +396,9 @@ func (x *context) timeout() {
 	}
 	defer x.release()
 
-	s.cancel(x, status.Timeout)
+	// Build status lazily, most contexts never time out
+	st := s.timeout.status()
+	s.cancel(x, st)
 }
 
 // private
@@ -292,3 +428,19 @@ func (x *context) release() {
 	s.reset()
 	releaseState(s)
 }
+
+// util
+
+// timeoutInfo describes a timeout, its status is built only when the context times out.
+type timeoutInfo struct {
+	after    time.Duration
+	deadline time.Time
+	relative bool // timeout is relative, i.e. after a duration
+}
+
+func (t timeoutInfo) status() status.Status {
+	if t.relative {
+		return status.Timeoutf("context timed out after %v", t.after)
+	}
+	return status.Timeoutf("context deadline exceeded at %v", t.deadline.Format(time.RFC3339Nano))
+}
@@ -4,16 +4,23 @@
 
 package context
 
-import "github.com/basecomplextech/baselibrary/status"
+import (
+	"time"
+
+	"github.com/basecomplextech/baselibrary/status"
+)
 
 var no Context = &noContext{}
 
 type noContext struct{}
 
-func (*noContext) Cancel()                 {}
-func (*noContext) Done() bool              { return false }
-func (*noContext) Wait() <-chan struct{}   { return nil }
-func (*noContext) Status() status.Status   { return status.OK }
-func (*noContext) AddCallback(Callback)    {}
-func (*noContext) RemoveCallback(Callback) {}
-func (*noContext) Free()                   {}
+func (*noContext) Cancel()                        {}
+func (*noContext) CancelWithStatus(status.Status) {}
+func (*noContext) Done() bool                     { return false }
+func (*noContext) Wait() <-chan struct{}          { return nil }
+func (*noContext) Status() status.Status          { return status.OK }
+func (*noContext) Deadline() (time.Time, bool)    { return time.Time{}, false }
+func (*noContext) Value(key any) any              { return nil }
+func (*noContext) AddCallback(Callback)           {}
+func (*noContext) RemoveCallback(Callback)        {}
+func (*noContext) Free()                          {}
@@ -6,6 +6,8 @@ package context
 
 import (
 	context_ "context"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/basecomplextech/baselibrary/status"
@@ -23,7 +25,7 @@ func newStdContext(ctx Context) *stdContext {
 
 // Deadline returns the time when work done on behalf of this context should be cancelled.
 func (x *stdContext) Deadline() (deadline time.Time, ok bool) {
-	return
+	return x.ctx.Deadline()
 }
 
 // Done returns a channel that's closed when work done on behalf of this context should be cancelled.
@@ -46,5 +48,54 @@ func (x *stdContext) Err() error {
 // Value returns the value associated with this context for key, or nil
 // if no value is associated with key.
 func (x *stdContext) Value(key any) any {
-	return nil
+	return x.ctx.Value(key)
+}
+
+// stdStop
+
+// stdStop stops a standard context callback, guarded with a mutex to prevent data race
+// when the context is cancelled before the callback is set.
+type stdStop struct {
+	mu      sync.Mutex
+	fn      func() bool
+	stopped bool
+}
+
+func (s *stdStop) set(fn func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		fn()
+		return
+	}
+	s.fn = fn
+}
+
+func (s *stdStop) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	if s.fn == nil {
+		return
+	}
+
+	s.fn()
+	s.fn = nil
+}
+
+// util
+
+// stdStatus returns a cancellation status from a standard context error.
+func stdStatus(err error) status.Status {
+	switch {
+	case err == nil:
+		return status.Cancelled
+	case errors.Is(err, context_.Canceled):
+		return status.Cancelled
+	case errors.Is(err, context_.DeadlineExceeded):
+		return status.Timeout
+	}
+	return status.WrapError(err)
 }
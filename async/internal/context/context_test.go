@@ -5,10 +5,12 @@
 package context
 
 import (
+	context_ "context"
 	"testing"
 	"time"
 
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -81,8 +83,98 @@ func TestContext_Cancel__should_cancel_child_context(t *testing.T) {
 	assert.Equal(t, status.Cancelled, st)
 }
 
+// CancelWithStatus
+
+func TestContext_CancelWithStatus__should_cancel_context_with_status(t *testing.T) {
+	ctx := New()
+	defer ctx.Free()
+
+	st := status.Unavailable("shutdown")
+	ctx.CancelWithStatus(st)
+
+	assert.True(t, ctx.Done())
+	assert.Equal(t, st, ctx.Status())
+}
+
+func TestContext_CancelWithStatus__should_propagate_status_to_children(t *testing.T) {
+	parent := New()
+	defer parent.Free()
+
+	child := Next(parent)
+	defer child.Free()
+
+	child1 := NextTimeout(child, time.Second)
+	defer child1.Free()
+
+	st := status.Unavailable("shutdown")
+	parent.CancelWithStatus(st)
+
+	assert.Equal(t, st, child.Status())
+	assert.Equal(t, st, child1.Status())
+}
+
+func TestContext_CancelWithStatus__should_replace_ok_with_cancelled(t *testing.T) {
+	ctx := New()
+	defer ctx.Free()
+
+	ctx.CancelWithStatus(status.OK)
+	assert.Equal(t, status.Cancelled, ctx.Status())
+}
+
+// CancelStack
+
+func TestCancelStack__should_record_first_cancel_stack_in_debug_mode(t *testing.T) {
+	SetDebug(true)
+	defer SetDebug(false)
+
+	ctx := New()
+	defer ctx.Free()
+
+	child := Next(ctx)
+	defer child.Free()
+
+	ctx.Cancel()
+
+	stack := CancelStack(ctx)
+	assert.Contains(t, string(stack), "TestCancelStack__should_record_first_cancel_stack_in_debug_mode")
+
+	stack = CancelStack(child)
+	assert.Contains(t, string(stack), "TestCancelStack__should_record_first_cancel_stack_in_debug_mode")
+}
+
+func TestCancelStack__should_return_nil_when_debug_disabled(t *testing.T) {
+	ctx := New()
+	defer ctx.Free()
+
+	ctx.Cancel()
+
+	stack := CancelStack(ctx)
+	assert.Nil(t, stack)
+}
+
 // Timeout
 
+func TestContext_Timeout__should_report_timeout_in_status_message(t *testing.T) {
+	ctx := Timeout(time.Millisecond * 5)
+	defer ctx.Free()
+
+	<-ctx.Wait()
+
+	st := ctx.Status()
+	assert.Equal(t, status.CodeTimeout, st.Code)
+	assert.Equal(t, "context timed out after 5ms", st.Message)
+}
+
+func TestContext_Deadline__should_report_deadline_in_status_message(t *testing.T) {
+	deadline := time.Now().Add(-time.Second)
+	ctx := Deadline(deadline)
+	defer ctx.Free()
+
+	st := ctx.Status()
+	assert.Equal(t, status.CodeTimeout, st.Code)
+	assert.Equal(t, "context deadline exceeded at "+deadline.Format(time.RFC3339Nano), st.Message)
+}
+
 func TestContext_Timeout__should_timeout_context(t *testing.T) {
 	ctx := Timeout(time.Millisecond * 5)
 
@@ -92,7 +184,7 @@ func TestContext_Timeout__should_timeout_context(t *testing.T) {
 		t.Fatal("context was not cancelled")
 	}
 	st := ctx.Status()
-	assert.Equal(t, status.Timeout, st)
+	assert.Equal(t, status.CodeTimeout, st.Code)
 
 	select {
 	case <-ctx.Wait():
@@ -100,7 +192,7 @@ func TestContext_Timeout__should_timeout_context(t *testing.T) {
 		t.Fatal("done channel was not closed")
 	}
 	st = ctx.Status()
-	assert.Equal(t, status.Timeout, st)
+	assert.Equal(t, status.CodeTimeout, st.Code)
 }
 
 func TestContext_Timeout__should_timeout_child_context(t *testing.T) {
@@ -114,7 +206,7 @@ func TestContext_Timeout__should_timeout_child_context(t *testing.T) {
 	}
 
 	st := child.Status()
-	assert.Equal(t, status.Timeout, st)
+	assert.Equal(t, status.CodeTimeout, st.Code)
 }
 
 // Next
@@ -140,3 +232,256 @@ func TestNextTimeout__should_not_deadlock(t *testing.T) {
 
 	assert.True(t, ctx1.Done())
 }
+
+// Deadline
+
+func TestContext_Deadline__should_return_deadline(t *testing.T) {
+	deadline := time.Now().Add(time.Second)
+	ctx := Deadline(deadline)
+	defer ctx.Free()
+
+	d, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+}
+
+func TestContext_Deadline__should_return_false_when_no_deadline(t *testing.T) {
+	ctx := New()
+	defer ctx.Free()
+
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+}
+
+func TestContext_Deadline__should_inherit_earlier_parent_deadline(t *testing.T) {
+	deadline := time.Now().Add(time.Second)
+	parent := Deadline(deadline)
+	defer parent.Free()
+
+	child := NextTimeout(parent, time.Minute)
+	defer child.Free()
+
+	d, ok := child.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+
+	child1 := Next(parent)
+	defer child1.Free()
+
+	d, ok = child1.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+}
+
+// Value
+
+type testKey struct{}
+
+func TestWithValue__should_return_value(t *testing.T) {
+	ctx := WithValue(No(), testKey{}, "value")
+	defer ctx.Free()
+
+	v := ctx.Value(testKey{})
+	assert.Equal(t, "value", v)
+	assert.Nil(t, ctx.Value("other"))
+}
+
+func TestWithValue__should_return_parent_value(t *testing.T) {
+	parent := WithValue(No(), testKey{}, "value")
+	defer parent.Free()
+
+	child := NextTimeout(parent, time.Second)
+	defer child.Free()
+
+	v := child.Value(testKey{})
+	assert.Equal(t, "value", v)
+}
+
+func TestWithValue__should_not_cancel_parent_on_free(t *testing.T) {
+	parent := New()
+	defer parent.Free()
+
+	ctx := WithValue(parent, testKey{}, "value")
+	ctx.Free()
+
+	assert.False(t, parent.Done())
+}
+
+func TestWithValue__should_panic_when_key_not_comparable(t *testing.T) {
+	assert.Panics(t, func() {
+		WithValue(No(), []int{}, "value")
+	})
+}
+
+// Std
+
+func TestStd__should_return_deadline_and_values(t *testing.T) {
+	deadline := time.Now().Add(time.Second)
+	parent := Deadline(deadline)
+	defer parent.Free()
+
+	ctx := WithValue(parent, testKey{}, "value")
+	defer ctx.Free()
+
+	std := Std(ctx)
+	d, ok := std.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+	assert.Equal(t, "value", std.Value(testKey{}))
+}
+
+// FromStd
+
+func TestFromStd__should_cancel_context_when_std_cancelled(t *testing.T) {
+	std, cancel := context_.WithCancel(context_.Background())
+	ctx := FromStd(std)
+	defer ctx.Free()
+
+	cancel()
+
+	select {
+	case <-ctx.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled")
+	}
+	assert.Equal(t, status.Cancelled, ctx.Status())
+}
+
+func TestFromStd__should_timeout_context_when_std_deadline_exceeded(t *testing.T) {
+	std, cancel := context_.WithTimeout(context_.Background(), time.Millisecond*5)
+	defer cancel()
+
+	ctx := FromStd(std)
+	defer ctx.Free()
+
+	select {
+	case <-ctx.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled")
+	}
+	assert.Equal(t, status.CodeTimeout, ctx.Status().Code)
+}
+
+func TestFromStd__should_cancel_context_when_std_already_done(t *testing.T) {
+	std, cancel := context_.WithCancel(context_.Background())
+	cancel()
+
+	ctx := FromStd(std)
+	defer ctx.Free()
+
+	assert.True(t, ctx.Done())
+}
+
+func TestFromStd__should_return_deadline_and_values(t *testing.T) {
+	deadline := time.Now().Add(time.Second)
+	std, cancel := context_.WithDeadline(context_.Background(), deadline)
+	defer cancel()
+	std = context_.WithValue(std, testKey{}, "value")
+
+	ctx := FromStd(std)
+	defer ctx.Free()
+
+	d, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+	assert.Equal(t, "value", ctx.Value(testKey{}))
+}
+
+func TestFromStd__should_not_cancel_std_on_free(t *testing.T) {
+	std, cancel := context_.WithCancel(context_.Background())
+	defer cancel()
+
+	ctx := FromStd(std)
+	ctx.Free()
+
+	assert.Nil(t, std.Err())
+}
+
+func TestFromStd__should_unwrap_async_context(t *testing.T) {
+	parent := WithValue(No(), testKey{}, "value")
+	defer parent.Free()
+
+	ctx := FromStd(Std(parent))
+	defer ctx.Free()
+
+	assert.Equal(t, "value", ctx.Value(testKey{}))
+
+	parent.Cancel()
+	assert.True(t, ctx.Done())
+}
+
+// TimeoutWith
+
+func TestTimeoutWith__should_timeout_context_when_clock_advanced(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+	ctx := TimeoutWith(clock, time.Second)
+	defer ctx.Free()
+
+	d, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, clock.Now().Add(time.Second), d)
+
+	clock.Advance(time.Second - 1)
+	assert.False(t, ctx.Done())
+
+	clock.Advance(1)
+	assert.True(t, ctx.Done())
+	assert.Equal(t, status.CodeTimeout, ctx.Status().Code)
+}
+
+func TestNextTimeoutWith__should_stop_timer_when_cancelled(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+	ctx := NextTimeoutWith(clock, No(), time.Second)
+	assert.Equal(t, 1, clock.Waiters())
+
+	ctx.Free()
+	assert.Equal(t, 0, clock.Waiters())
+}
+
+func TestNextTimeoutWith__should_timeout_context_with_timer_wheel(t *testing.T) {
+	wheel := clockwork.NewWheel(time.Millisecond, 0)
+	defer wheel.Stop()
+
+	ctx := NextTimeoutWith(wheel, No(), 5*time.Millisecond)
+	defer ctx.Free()
+
+	select {
+	case <-ctx.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled")
+	}
+	assert.Equal(t, status.CodeTimeout, ctx.Status().Code)
+}
+
+// DeadlineWith
+
+func TestDeadlineWith__should_timeout_context_when_clock_advanced(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+	deadline := clock.Now().Add(time.Second)
+
+	ctx := DeadlineWith(clock, deadline)
+	defer ctx.Free()
+
+	clock.Advance(time.Second - 1)
+	assert.False(t, ctx.Done())
+
+	clock.Advance(1)
+	assert.True(t, ctx.Done())
+	assert.Equal(t, status.CodeTimeout, ctx.Status().Code)
+}
+
+func TestNextDeadlineWith__should_timeout_context_when_clock_advanced(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+	deadline := clock.Now().Add(time.Second)
+
+	ctx := NextDeadlineWith(clock, No(), deadline)
+	defer ctx.Free()
+
+	d, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+
+	clock.Advance(time.Second)
+	assert.True(t, ctx.Done())
+	assert.Equal(t, status.CodeTimeout, ctx.Status().Code)
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package context
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// debugEnabled enables recording cancel stacks.
+var debugEnabled atomic.Bool
+
+// SetDebug enables or disables the debug mode.
+//
+// In the debug mode contexts record the stack of the first cancel call,
+// it can be retrieved via [CancelStack]. The debug mode is slow, and should
+// be used only for debugging.
+func SetDebug(enabled bool) {
+	debugEnabled.Store(enabled)
+}
+
+// CancelStack returns the stack of the first cancel call, or nil.
+//
+// The stack is recorded only in the debug mode, see [SetDebug].
+func CancelStack(ctx Context) []byte {
+	x, ok := ctx.(*context)
+	if !ok {
+		return nil
+	}
+
+	s, ok := x.acquire()
+	if !ok {
+		return nil
+	}
+	defer x.release()
+
+	return s.result.getStack()
+}
+
+// private
+
+func cancelStack() []byte {
+	if !debugEnabled.Load() {
+		return nil
+	}
+	return debug.Stack()
+}
@@ -16,6 +16,7 @@ type result struct {
 	mu      sync.Mutex
 	done    bool
 	cause   status.Status
+	stack   []byte                 // optional, first cancel stack in debug mode
 	channel opt.Opt[chan struct{}] // lazily created
 }
 
@@ -26,7 +27,14 @@ func (r *result) get() (bool, status.Status) {
 	return r.done, r.cause
 }
 
-func (r *result) set(st status.Status) bool {
+func (r *result) getStack() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.stack
+}
+
+func (r *result) set(st status.Status, stack []byte) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -36,6 +44,7 @@ func (r *result) set(st status.Status) bool {
 
 	r.done = true
 	r.cause = st
+	r.stack = stack
 
 	c, ok := r.channel.Unwrap()
 	if ok {
@@ -5,13 +5,25 @@
 package context
 
 import (
+	context_ "context"
+	"time"
+
 	"github.com/basecomplextech/baselibrary/opt"
 	"github.com/basecomplextech/baselibrary/pools"
 	"github.com/basecomplextech/baselibrary/status"
 )
 
 type state struct {
-	parent    opt.Opt[Context]
+	parent   opt.Opt[Context]
+	deadline time.Time
+	timeout  timeoutInfo
+
+	key   any // optional
+	value any
+
+	std     context_.Context // optional, source standard context
+	stdStop stdStop
+
 	timer     timer
 	result    result
 	callbacks callbacks
@@ -24,8 +36,9 @@ func newState(parent Context) *state {
 }
 
 func (s *state) cancel(x *context, st status.Status) {
-	// Set result
-	ok := s.result.set(st)
+	// Set result, maybe record stack
+	stack := cancelStack()
+	ok := s.result.set(st, stack)
 	if !ok {
 		return
 	}
@@ -33,6 +46,9 @@ func (s *state) cancel(x *context, st status.Status) {
 	// Stop timer
 	s.timer.stop()
 
+	// Stop standard context callback
+	s.stdStop.stop()
+
 	// Remove from parent
 	if p, ok := s.parent.Unwrap(); ok {
 		p.RemoveCallback(x)
@@ -42,6 +58,20 @@ func (s *state) cancel(x *context, st status.Status) {
 	s.callbacks.notify(st)
 }
 
+// lookup returns a value from this context, the standard context or the parent.
+func (s *state) lookup(key any) any {
+	if s.key != nil && s.key == key {
+		return s.value
+	}
+	if s.std != nil {
+		return s.std.Value(key)
+	}
+	if p, ok := s.parent.Unwrap(); ok {
+		return p.Value(key)
+	}
+	return nil
+}
+
 func (s *state) reset() {
 	m := s.callbacks.reset()
 
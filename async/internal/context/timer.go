@@ -6,18 +6,20 @@ package context
 
 import (
 	"sync"
-	"time"
 
 	"github.com/basecomplextech/baselibrary/opt"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 // timer is guarded with a mutex to prevent data race in constructor with immediate timeout.
+//
+// The timer is created by a context clock, which can be a timer wheel, see [clockwork.Wheel].
 type timer struct {
 	mu    sync.Mutex
-	timer opt.Opt[*time.Timer]
+	timer opt.Opt[clockwork.Timer]
 }
 
-func (t *timer) set(timer *time.Timer) {
+func (t *timer) set(timer clockwork.Timer) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -0,0 +1,116 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package latch
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Barrier is a cyclic barrier which releases a group of parties when all of them have arrived.
+//
+// The barrier is reset after each release, and can be reused for the next generation.
+//
+// Example:
+//
+//	barrier := async.NewBarrier(len(workers))
+//
+//	for _, w := range workers {
+//		go func() {
+//			for step := range steps {
+//				w.run(step)
+//
+//				select {
+//				case <-ctx.Wait():
+//					return
+//				case <-barrier.Arrive():
+//				}
+//			}
+//		}()
+//	}
+type Barrier interface {
+	// Parties returns the number of parties required to release the barrier.
+	Parties() int
+
+	// Arrived returns the number of parties which have arrived in the current generation.
+	Arrived() int
+
+	// Arrive registers a party arrival, and returns a channel which is closed
+	// when all parties of the current generation have arrived.
+	Arrive() <-chan struct{}
+
+	// Wait returns a channel which is closed when the current generation is released,
+	// without registering an arrival.
+	Wait() <-chan struct{}
+}
+
+// NewBarrier returns a new barrier for a number of parties, panics if parties is not positive.
+func NewBarrier(parties int) Barrier {
+	return newBarrier(parties)
+}
+
+// internal
+
+var _ Barrier = (*barrier)(nil)
+
+type barrier struct {
+	parties int
+
+	mu      sync.Mutex
+	arrived int
+	done    chan struct{} // current generation, closed when released
+}
+
+func newBarrier(parties int) *barrier {
+	if parties <= 0 {
+		panic(fmt.Sprintf("barrier parties must be positive, parties=%d", parties))
+	}
+
+	return &barrier{
+		parties: parties,
+		done:    make(chan struct{}),
+	}
+}
+
+// Parties returns the number of parties required to release the barrier.
+func (b *barrier) Parties() int {
+	return b.parties
+}
+
+// Arrived returns the number of parties which have arrived in the current generation.
+func (b *barrier) Arrived() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.arrived
+}
+
+// Arrive registers a party arrival, and returns a channel which is closed
+// when all parties of the current generation have arrived.
+func (b *barrier) Arrive() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	done := b.done
+	b.arrived++
+	if b.arrived < b.parties {
+		return done
+	}
+
+	// Release generation, start next one
+	close(done)
+	b.arrived = 0
+	b.done = make(chan struct{})
+	return done
+}
+
+// Wait returns a channel which is closed when the current generation is released,
+// without registering an arrival.
+func (b *barrier) Wait() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.done
+}
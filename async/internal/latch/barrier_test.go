@@ -0,0 +1,83 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package latch
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarrier__should_release_parties_when_all_arrived(t *testing.T) {
+	b := NewBarrier(2)
+
+	ch := b.Arrive()
+	select {
+	case <-ch:
+		t.Fatal("barrier should not be released")
+	default:
+	}
+	assert.Equal(t, 1, b.Arrived())
+
+	ch1 := b.Arrive()
+	select {
+	case <-ch:
+	default:
+		t.Fatal("barrier should be released")
+	}
+	assert.Equal(t, ch, ch1)
+	assert.Equal(t, 0, b.Arrived())
+}
+
+func TestBarrier__should_reset_after_release(t *testing.T) {
+	b := NewBarrier(1)
+
+	ch0 := b.Arrive()
+	ch1 := b.Wait()
+	assert.NotEqual(t, ch0, ch1)
+
+	select {
+	case <-ch1:
+		t.Fatal("next generation should not be released")
+	default:
+	}
+}
+
+func TestBarrier__should_synchronize_parties(t *testing.T) {
+	n := 4
+	steps := 10
+	b := NewBarrier(n)
+
+	mu := sync.Mutex{}
+	counts := make([]int, steps)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for step := 0; step < steps; step++ {
+				mu.Lock()
+				counts[step]++
+				mu.Unlock()
+
+				<-b.Arrive()
+
+				mu.Lock()
+				assert.Equal(t, n, counts[step])
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNewBarrier__should_panic_when_parties_not_positive(t *testing.T) {
+	assert.Panics(t, func() {
+		NewBarrier(0)
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package latch
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Latch is a countdown latch which is released when its count reaches zero.
+//
+// Example:
+//
+//	latch := async.NewLatch(len(workers))
+//
+//	for _, w := range workers {
+//		go func() {
+//			defer latch.CountDown()
+//			w.run()
+//		}()
+//	}
+//
+//	select {
+//	case <-ctx.Wait():
+//		return ctx.Status()
+//	case <-latch.Wait():
+//	}
+type Latch interface {
+	// Count returns the current count.
+	// The method uses an atomic integer internally and is non-blocking.
+	Count() int
+
+	// CountDown decrements the count, and releases the waiters when it reaches zero.
+	// The method does nothing if the count is already zero.
+	CountDown()
+
+	// Wait waits for the count to reach zero.
+	Wait() <-chan struct{}
+}
+
+// New returns a new latch with a count, a zero count latch is released immediately.
+func New(count int) Latch {
+	return newLatch(count)
+}
+
+// internal
+
+var _ Latch = (*latch)(nil)
+
+type latch struct {
+	mu    sync.Mutex
+	count atomic.Int64
+	done  chan struct{} // closed when count reaches zero
+}
+
+func newLatch(count int) *latch {
+	l := &latch{
+		done: make(chan struct{}),
+	}
+
+	if count <= 0 {
+		close(l.done)
+		return l
+	}
+
+	l.count.Store(int64(count))
+	return l
+}
+
+// Count returns the current count.
+// The method uses an atomic integer internally and is non-blocking.
+func (l *latch) Count() int {
+	return int(l.count.Load())
+}
+
+// CountDown decrements the count, and releases the waiters when it reaches zero.
+// The method does nothing if the count is already zero.
+func (l *latch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.count.Load()
+	if n == 0 {
+		return
+	}
+
+	n--
+	l.count.Store(n)
+
+	if n == 0 {
+		close(l.done)
+	}
+}
+
+// Wait waits for the count to reach zero.
+func (l *latch) Wait() <-chan struct{} {
+	return l.done
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package latch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatch__should_release_waiters_when_count_reaches_zero(t *testing.T) {
+	l := New(2)
+
+	l.CountDown()
+	select {
+	case <-l.Wait():
+		t.Fatal("latch should not be released")
+	default:
+	}
+	assert.Equal(t, 1, l.Count())
+
+	l.CountDown()
+	select {
+	case <-l.Wait():
+	default:
+		t.Fatal("latch should be released")
+	}
+	assert.Equal(t, 0, l.Count())
+
+	l.CountDown()
+	assert.Equal(t, 0, l.Count())
+}
+
+func TestLatch__should_be_released_when_zero_count(t *testing.T) {
+	l := New(0)
+
+	select {
+	case <-l.Wait():
+	default:
+		t.Fatal("latch should be released")
+	}
+}
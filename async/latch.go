@@ -0,0 +1,60 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import "github.com/basecomplextech/baselibrary/async/internal/latch"
+
+// Latch is a countdown latch which is released when its count reaches zero.
+//
+// Example:
+//
+//	latch := async.NewLatch(len(workers))
+//
+//	for _, w := range workers {
+//		go func() {
+//			defer latch.CountDown()
+//			w.run()
+//		}()
+//	}
+//
+//	select {
+//	case <-ctx.Wait():
+//		return ctx.Status()
+//	case <-latch.Wait():
+//	}
+type Latch = latch.Latch
+
+// Barrier is a cyclic barrier which releases a group of parties when all of them have arrived.
+//
+// The barrier is reset after each release, and can be reused for the next generation.
+//
+// Example:
+//
+//	barrier := async.NewBarrier(len(workers))
+//
+//	for _, w := range workers {
+//		go func() {
+//			for step := range steps {
+//				w.run(step)
+//
+//				select {
+//				case <-ctx.Wait():
+//					return
+//				case <-barrier.Arrive():
+//				}
+//			}
+//		}()
+//	}
+type Barrier = latch.Barrier
+
+// NewLatch returns a new latch with a count, a zero count latch is released immediately.
+func NewLatch(count int) Latch {
+	return latch.New(count)
+}
+
+// NewBarrier returns a new barrier for a number of parties, panics if parties is not positive.
+func NewBarrier(parties int) Barrier {
+	return latch.NewBarrier(parties)
+}
@@ -0,0 +1,311 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"iter"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/basecomplextech/baselibrary/status"
+)
+
+// ParallelMap maps items in parallel with at most n goroutines, and returns the results
+// in the order of the items.
+//
+// The first non-OK status cancels the context passed to the other calls, stops starting
+// new calls, and is returned when all running calls exit. Panics are recovered and converted
+// into statuses. Non-positive n means runtime.GOMAXPROCS(0).
+//
+// Example:
+//
+//	pages, st := async.ParallelMap(ctx, urls, 8,
+//		func(ctx async.Context, url string) (Page, status.Status) {
+//			return fetch(ctx, url)
+//		})
+func ParallelMap[T, R any](ctx Context, items []T, n int,
+	fn func(ctx Context, item T) (R, status.Status)) ([]R, status.Status) {
+
+	results := make([]R, len(items))
+	st := parallelRun(ctx, slices.All(items), n, func(ctx Context, i int, item T) status.Status {
+		result, st := fn(ctx, item)
+		if !st.OK() {
+			return st
+		}
+
+		results[i] = result
+		return status.OK
+	})
+	if !st.OK() {
+		return nil, st
+	}
+	return results, status.OK
+}
+
+// ParallelMapSeq maps a sequence in parallel with at most n goroutines, and returns
+// the results in the order of the sequence, see ParallelMap.
+func ParallelMapSeq[T, R any](ctx Context, seq iter.Seq[T], n int,
+	fn func(ctx Context, item T) (R, status.Status)) ([]R, status.Status) {
+
+	items := slices.Collect(seq)
+	return ParallelMap(ctx, items, n, fn)
+}
+
+// ParallelForEach calls a function for each item in parallel with at most n goroutines,
+// see ParallelMap.
+func ParallelForEach[T any](ctx Context, items []T, n int,
+	fn func(ctx Context, item T) status.Status) status.Status {
+
+	return parallelRun(ctx, slices.All(items), n, func(ctx Context, _ int, item T) status.Status {
+		return fn(ctx, item)
+	})
+}
+
+// ParallelForEachSeq calls a function for each item of a sequence in parallel with
+// at most n goroutines, see ParallelMap.
+//
+// The sequence is consumed lazily, the next item is pulled when a goroutine is available.
+func ParallelForEachSeq[T any](ctx Context, seq iter.Seq[T], n int,
+	fn func(ctx Context, item T) status.Status) status.Status {
+
+	return parallelRun(ctx, parallelEnumerate(seq), n, func(ctx Context, _ int, item T) status.Status {
+		return fn(ctx, item)
+	})
+}
+
+// ParallelStream maps a sequence in parallel with at most n goroutines, and returns
+// a stream of results in the order of completion.
+//
+// The stream returns false and the first non-OK status when a call fails, or false and OK
+// when all items have been processed. Free cancels the running calls and awaits them,
+// the stream must be freed after use.
+//
+// Example:
+//
+//	stream := async.ParallelStream(ctx, slices.Values(urls), 8, fetch)
+//	defer stream.Free()
+//
+//	for {
+//		page, ok, st := stream.Next(ctx)
+//		switch {
+//		case !st.OK():
+//			return st
+//		case !ok:
+//			return status.OK
+//		}
+//
+//		handle(page)
+//	}
+func ParallelStream[T, R any](ctx Context, seq iter.Seq[T], n int,
+	fn func(ctx Context, item T) (R, status.Status)) Stream[R] {
+
+	s := newParallelStream(ctx, seq, n, fn)
+	return NewStreamFree(s.next, s.free)
+}
+
+// internal
+
+var parallelPool = NewPool()
+
+// parallel runs calls in the pool with a limit on the number of running calls.
+type parallel struct {
+	ctx CancelContext
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	mu  sync.Mutex
+	err status.Status // first non-OK status
+}
+
+// parallelRun calls a function for each item in parallel, and returns the first non-OK
+// status or the context status if the context is cancelled.
+func parallelRun[T any](ctx Context, seq iter.Seq2[int, T], n int,
+	fn func(ctx Context, i int, item T) status.Status) status.Status {
+
+	p := newParallel(ctx, n)
+	defer p.ctx.Free()
+
+	st := parallelStart(p, seq, fn)
+	if !st.OK() {
+		return st
+	}
+	return p.status()
+}
+
+func newParallel(ctx Context, n int) *parallel {
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+
+	return &parallel{
+		ctx: NextContext(ctx),
+		sem: make(chan struct{}, n),
+		err: status.OK,
+	}
+}
+
+// parallelStart starts calls and awaits them, returns the first error or the context
+// status if cancelled before all calls have been started.
+func parallelStart[T any](p *parallel, seq iter.Seq2[int, T],
+	fn func(ctx Context, i int, item T) status.Status) status.Status {
+
+	defer p.wg.Wait()
+
+	for i, item := range seq {
+		select {
+		case p.sem <- struct{}{}:
+		case <-p.ctx.Wait():
+			return p.cancelled()
+		}
+
+		p.wg.Add(1)
+		parallelPool.Go(func() {
+			defer p.wg.Done()
+			defer func() { <-p.sem }()
+
+			st := parallelCall(p.ctx, i, item, fn)
+			if !st.OK() {
+				p.fail(st)
+			}
+		})
+	}
+	return status.OK
+}
+
+// cancelled returns the first error, or the context status.
+func (p *parallel) cancelled() status.Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.err.OK() {
+		return p.err
+	}
+	return p.ctx.Status()
+}
+
+// fail records the first error and cancels the context.
+func (p *parallel) fail(st status.Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.err.OK() {
+		return
+	}
+
+	p.err = st
+	p.ctx.CancelWithStatus(st)
+}
+
+func (p *parallel) status() status.Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+// parallelCall calls a function and recovers from panics.
+func parallelCall[T any](ctx Context, i int, item T,
+	fn func(ctx Context, i int, item T) status.Status) (st status.Status) {
+
+	defer func() {
+		if e := recover(); e != nil {
+			st = status.Recover(e)
+		}
+	}()
+
+	return fn(ctx, i, item)
+}
+
+// stream
+
+type parallelStream[T, R any] struct {
+	p       *parallel
+	results chan R
+	done    chan struct{}
+	st      status.Status // set before done is closed
+}
+
+func newParallelStream[T, R any](ctx Context, seq iter.Seq[T], n int,
+	fn func(ctx Context, item T) (R, status.Status)) *parallelStream[T, R] {
+
+	p := newParallel(ctx, n)
+	s := &parallelStream[T, R]{
+		p:       p,
+		results: make(chan R, cap(p.sem)),
+		done:    make(chan struct{}),
+	}
+
+	parallelPool.Go(func() { s.run(seq, fn) })
+	return s
+}
+
+func (s *parallelStream[T, R]) run(seq iter.Seq[T], fn func(ctx Context, item T) (R, status.Status)) {
+	defer close(s.done)
+
+	st := parallelStart(s.p, parallelEnumerate(seq), func(ctx Context, _ int, item T) status.Status {
+		result, st := fn(ctx, item)
+		if !st.OK() {
+			return st
+		}
+
+		select {
+		case s.results <- result:
+			return status.OK
+		case <-ctx.Wait():
+			return ctx.Status()
+		}
+	})
+	if st.OK() {
+		st = s.p.status()
+	}
+	s.st = st
+}
+
+func (s *parallelStream[T, R]) next(ctx Context) (R, bool, status.Status) {
+	var zero R
+
+	// Prefer buffered results
+	select {
+	case result := <-s.results:
+		return result, true, status.OK
+	default:
+	}
+
+	select {
+	case result := <-s.results:
+		return result, true, status.OK
+	case <-s.done:
+	case <-ctx.Wait():
+		return zero, false, ctx.Status()
+	}
+
+	// Drain results sent before done
+	select {
+	case result := <-s.results:
+		return result, true, status.OK
+	default:
+	}
+	return zero, false, s.st
+}
+
+func (s *parallelStream[T, R]) free() {
+	s.p.ctx.Cancel()
+	<-s.done
+	s.p.ctx.Free()
+}
+
+// parallelEnumerate returns a sequence of indexes and items.
+func parallelEnumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for item := range seq {
+			if !yield(i, item) {
+				return
+			}
+			i++
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ParallelMap
+
+func TestParallelMap__should_return_results_in_order(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	results, st := ParallelMap(NoContext(), items, 3, func(ctx Context, item int) (int, status.Status) {
+		time.Sleep(time.Duration(10-item) * time.Millisecond)
+		return item * 10, status.OK
+	})
+	require.True(t, st.OK())
+	assert.Equal(t, []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, results)
+}
+
+func TestParallelMap__should_limit_running_calls(t *testing.T) {
+	items := make([]int, 50)
+
+	var running atomic.Int32
+	var max atomic.Int32
+
+	_, st := ParallelMap(NoContext(), items, 4, func(ctx Context, item int) (int, status.Status) {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			m := max.Load()
+			if n <= m || max.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+		return item, status.OK
+	})
+	require.True(t, st.OK())
+	assert.LessOrEqual(t, max.Load(), int32(4))
+}
+
+func TestParallelMap__should_stop_on_first_error(t *testing.T) {
+	st0 := status.Test("test")
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var calls atomic.Int32
+	results, st := ParallelMap(NoContext(), items, 2, func(ctx Context, item int) (int, status.Status) {
+		calls.Add(1)
+		if item == 3 {
+			return 0, st0
+		}
+
+		select {
+		case <-ctx.Wait():
+			return 0, ctx.Status()
+		case <-time.After(time.Millisecond):
+		}
+		return item, status.OK
+	})
+
+	assert.Nil(t, results)
+	assert.Equal(t, st0, st)
+	assert.Less(t, calls.Load(), int32(100))
+}
+
+func TestParallelMap__should_recover_panics(t *testing.T) {
+	_, st := ParallelMap(NoContext(), []int{1, 2, 3}, 2, func(ctx Context, item int) (int, status.Status) {
+		if item == 2 {
+			panic("test")
+		}
+		return item, status.OK
+	})
+
+	assert.Equal(t, status.CodeError, st.Code)
+}
+
+func TestParallelMap__should_return_context_status_when_cancelled(t *testing.T) {
+	ctx := NewContext()
+	ctx.Cancel()
+
+	_, st := ParallelMap(ctx, []int{1, 2, 3}, 1, func(ctx Context, item int) (int, status.Status) {
+		return item, status.OK
+	})
+	assert.Equal(t, status.Cancelled, st)
+}
+
+// ParallelMapSeq
+
+func TestParallelMapSeq__should_return_results_in_order(t *testing.T) {
+	seq := slices.Values([]int{1, 2, 3})
+
+	results, st := ParallelMapSeq(NoContext(), seq, 0, func(ctx Context, item int) (int, status.Status) {
+		return item * 2, status.OK
+	})
+	require.True(t, st.OK())
+	assert.Equal(t, []int{2, 4, 6}, results)
+}
+
+// ParallelForEach
+
+func TestParallelForEach__should_call_function_for_each_item(t *testing.T) {
+	var sum atomic.Int32
+
+	st := ParallelForEach(NoContext(), []int32{1, 2, 3, 4}, 2, func(ctx Context, item int32) status.Status {
+		sum.Add(item)
+		return status.OK
+	})
+	require.True(t, st.OK())
+	assert.Equal(t, int32(10), sum.Load())
+}
+
+func TestParallelForEachSeq__should_stop_pulling_items_on_error(t *testing.T) {
+	st0 := status.Test("test")
+
+	pulled := 0
+	seq := func(yield func(int) bool) {
+		for i := 0; i < 1000; i++ {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	st := ParallelForEachSeq(NoContext(), seq, 1, func(ctx Context, item int) status.Status {
+		if item == 5 {
+			return st0
+		}
+		return status.OK
+	})
+	assert.Equal(t, st0, st)
+	assert.Less(t, pulled, 1000)
+}
+
+// ParallelStream
+
+func TestParallelStream__should_return_all_results(t *testing.T) {
+	seq := slices.Values([]int{1, 2, 3, 4, 5})
+
+	stream := ParallelStream(NoContext(), seq, 2, func(ctx Context, item int) (int, status.Status) {
+		return item * 10, status.OK
+	})
+	defer stream.Free()
+
+	var results []int
+	for {
+		result, ok, st := stream.Next(NoContext())
+		require.True(t, st.OK())
+		if !ok {
+			break
+		}
+		results = append(results, result)
+	}
+
+	slices.Sort(results)
+	assert.Equal(t, []int{10, 20, 30, 40, 50}, results)
+}
+
+func TestParallelStream__should_return_first_error(t *testing.T) {
+	st0 := status.Test("test")
+	seq := slices.Values([]int{1, 2, 3})
+
+	stream := ParallelStream(NoContext(), seq, 1, func(ctx Context, item int) (int, status.Status) {
+		if item == 2 {
+			return 0, st0
+		}
+		return item, status.OK
+	})
+	defer stream.Free()
+
+	for {
+		_, ok, st := stream.Next(NoContext())
+		if !st.OK() {
+			assert.Equal(t, st0, st)
+			return
+		}
+		require.True(t, ok)
+	}
+}
+
+func TestParallelStream_Free__should_cancel_running_calls(t *testing.T) {
+	var cancelled atomic.Int32
+	seq := slices.Values([]int{1, 2, 3, 4})
+
+	stream := ParallelStream(NoContext(), seq, 4, func(ctx Context, item int) (int, status.Status) {
+		<-ctx.Wait()
+		cancelled.Add(1)
+		return 0, ctx.Status()
+	})
+
+	time.Sleep(10 * time.Millisecond)
+	stream.Free()
+	assert.Equal(t, int32(4), cancelled.Load())
+}
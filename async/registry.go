@@ -0,0 +1,333 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RoutineRegistry is an optional global registry of live routines, which is used
+// to debug hanging routines and services.
+//
+// The registry is disabled by default. When enabled, started routines are registered
+// with their names, start times, parents and states, and unregistered when they exit.
+// Routines started before the registry was enabled are not registered.
+//
+// Named routines also set the "routine" pprof label, which allows to filter CPU profiles
+// by routine names. Labels are set independent of the registry.
+//
+// Example:
+//
+//	async.Registry().Enable(true)
+//
+//	r := async.RunVoidNamed("server", serve)
+//	...
+//	async.Registry().Dump(os.Stderr)
+type RoutineRegistry interface {
+	// Enabled returns true if the registry is enabled.
+	Enabled() bool
+
+	// Enable enables or disables the registry.
+	Enable(enabled bool)
+
+	// Routines returns a snapshot of live registered routines ordered by ids.
+	Routines() []RoutineInfo
+
+	// Dump writes a tree of live registered routines with their goroutine stacks.
+	Dump(w io.Writer) error
+}
+
+// RoutineInfo describes a live registered routine.
+type RoutineInfo struct {
+	ID        uint64       // unique routine id
+	Parent    uint64       // parent routine id or zero
+	Name      string       // optional routine name
+	State     RoutineState // current routine state
+	Start     time.Time    // routine start time
+	Goroutine int64        // goroutine id, zero when not running yet
+}
+
+// RoutineState is a routine state in the registry.
+type RoutineState int32
+
+const (
+	// RoutineStarting indicates that the routine is started but its goroutine is not running yet.
+	RoutineStarting RoutineState = iota
+
+	// RoutineRunning indicates that the routine goroutine is running.
+	RoutineRunning
+
+	// RoutineStopping indicates that the routine has been requested to stop.
+	RoutineStopping
+)
+
+// Registry returns the global routine registry.
+func Registry() RoutineRegistry {
+	return globalRegistry
+}
+
+// String returns a state string.
+func (s RoutineState) String() string {
+	switch s {
+	case RoutineStarting:
+		return "starting"
+	case RoutineRunning:
+		return "running"
+	case RoutineStopping:
+		return "stopping"
+	}
+	return fmt.Sprintf("RoutineState(%d)", int32(s))
+}
+
+// internal
+
+var globalRegistry = newRegistry()
+
+var _ RoutineRegistry = (*registry)(nil)
+
+type registry struct {
+	enabled atomic.Bool
+	nextID  atomic.Uint64
+
+	mu         sync.Mutex
+	entries    map[uint64]*registryEntry
+	goroutines map[int64]*registryEntry
+}
+
+type registryEntry struct {
+	id     uint64
+	parent uint64
+	name   string
+	start  time.Time
+
+	state     atomic.Int32
+	goroutine atomic.Int64
+}
+
+func newRegistry() *registry {
+	return &registry{
+		entries:    make(map[uint64]*registryEntry),
+		goroutines: make(map[int64]*registryEntry),
+	}
+}
+
+// Enabled returns true if the registry is enabled.
+func (r *registry) Enabled() bool {
+	return r.enabled.Load()
+}
+
+// Enable enables or disables the registry.
+func (r *registry) Enable(enabled bool) {
+	r.enabled.Store(enabled)
+}
+
+// Routines returns a snapshot of live registered routines ordered by ids.
+func (r *registry) Routines() []RoutineInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]RoutineInfo, 0, len(r.entries))
+	for _, e := range r.entries {
+		result = append(result, e.info())
+	}
+
+	slices.SortFunc(result, func(a, b RoutineInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return result
+}
+
+// Dump writes a tree of live registered routines with their goroutine stacks.
+func (r *registry) Dump(w io.Writer) error {
+	routines := r.Routines()
+	stacks := goroutineStacks()
+	now := time.Now()
+
+	// Build tree, routines with exited parents are roots
+	ids := make(map[uint64]struct{}, len(routines))
+	for _, info := range routines {
+		ids[info.ID] = struct{}{}
+	}
+
+	var roots []RoutineInfo
+	children := make(map[uint64][]RoutineInfo)
+	for _, info := range routines {
+		if _, ok := ids[info.Parent]; ok {
+			children[info.Parent] = append(children[info.Parent], info)
+			continue
+		}
+		roots = append(roots, info)
+	}
+
+	// Write tree
+	var write func(info RoutineInfo, depth int) error
+	write = func(info RoutineInfo, depth int) error {
+		indent := bytes.Repeat([]byte("    "), depth)
+
+		name := info.Name
+		if name == "" {
+			name = "<unnamed>"
+		}
+		elapsed := now.Sub(info.Start).Round(time.Millisecond)
+
+		_, err := fmt.Fprintf(w, "%sroutine %d %q [%v, %v, goroutine %d]\n",
+			indent, info.ID, name, info.State, elapsed, info.Goroutine)
+		if err != nil {
+			return err
+		}
+
+		// Write stack
+		if stack, ok := stacks[info.Goroutine]; ok {
+			for _, line := range bytes.Split(stack, []byte("\n")) {
+				if _, err := fmt.Fprintf(w, "%s    %s\n", indent, line); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, child := range children[info.ID] {
+			if err := write(child, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, info := range roots {
+		if err := write(info, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// register
+
+// register registers a starting routine, returns nil if the registry is disabled.
+// The method must be called by the goroutine which starts the routine.
+func (r *registry) register(name string) *registryEntry {
+	if !r.enabled.Load() {
+		return nil
+	}
+
+	gid := currentGoroutine()
+	e := &registryEntry{
+		id:    r.nextID.Add(1),
+		name:  name,
+		start: time.Now(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if parent, ok := r.goroutines[gid]; ok {
+		e.parent = parent.id
+	}
+	r.entries[e.id] = e
+	return e
+}
+
+// running marks a routine as running in the current goroutine.
+func (r *registry) running(e *registryEntry) {
+	gid := currentGoroutine()
+	e.goroutine.Store(gid)
+	e.state.CompareAndSwap(int32(RoutineStarting), int32(RoutineRunning))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.goroutines[gid] = e
+}
+
+// unregister removes an exited routine.
+func (r *registry) unregister(e *registryEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, e.id)
+
+	gid := e.goroutine.Load()
+	if r.goroutines[gid] == e {
+		delete(r.goroutines, gid)
+	}
+}
+
+// entry
+
+func (e *registryEntry) info() RoutineInfo {
+	return RoutineInfo{
+		ID:        e.id,
+		Parent:    e.parent,
+		Name:      e.name,
+		State:     RoutineState(e.state.Load()),
+		Start:     e.start,
+		Goroutine: e.goroutine.Load(),
+	}
+}
+
+func (e *registryEntry) stopping() {
+	e.state.Store(int32(RoutineStopping))
+}
+
+// private
+
+// currentGoroutine returns the current goroutine id parsed from its stack header.
+func currentGoroutine() int64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	id, _ := parseGoroutineHeader(buf[:n])
+	return id
+}
+
+// goroutineStacks returns stacks of all goroutines by their ids.
+func goroutineStacks() map[int64][]byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	stacks := make(map[int64][]byte)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		id, ok := parseGoroutineHeader(stack)
+		if !ok {
+			continue
+		}
+		stacks[id] = bytes.TrimSpace(stack)
+	}
+	return stacks
+}
+
+// parseGoroutineHeader parses a goroutine id from "goroutine 123 [running]:".
+func parseGoroutineHeader(stack []byte) (int64, bool) {
+	stack, ok := bytes.CutPrefix(stack, []byte("goroutine "))
+	if !ok {
+		return 0, false
+	}
+
+	i := bytes.IndexByte(stack, ' ')
+	if i < 0 {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(string(stack[:i]), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistry(t *testing.T) *registry {
+	r := newRegistry()
+	r.Enable(true)
+
+	prev := globalRegistry
+	globalRegistry = r
+	t.Cleanup(func() { globalRegistry = prev })
+	return r
+}
+
+func testFindRoutine(routines []RoutineInfo, name string) (RoutineInfo, bool) {
+	for _, info := range routines {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return RoutineInfo{}, false
+}
+
+// Routines
+
+func TestRegistry_Routines__should_return_running_routines(t *testing.T) {
+	reg := testRegistry(t)
+	started := make(chan struct{})
+
+	r := RunVoidNamed("test", func(ctx Context) status.Status {
+		close(started)
+		<-ctx.Wait()
+		return status.OK
+	})
+	<-started
+
+	routines := reg.Routines()
+	require.Len(t, routines, 1)
+
+	info := routines[0]
+	assert.Equal(t, "test", info.Name)
+	assert.Equal(t, RoutineRunning, info.State)
+	assert.NotZero(t, info.Goroutine)
+	assert.False(t, info.Start.IsZero())
+
+	<-r.Stop()
+	assert.Len(t, reg.Routines(), 0)
+}
+
+func TestRegistry_Routines__should_return_stopping_routines(t *testing.T) {
+	reg := testRegistry(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	r := RunVoidNamed("test", func(ctx Context) status.Status {
+		close(started)
+		<-release
+		return status.OK
+	})
+	<-started
+	r.Stop()
+
+	routines := reg.Routines()
+	require.Len(t, routines, 1)
+	assert.Equal(t, RoutineStopping, routines[0].State)
+
+	close(release)
+	<-r.Wait()
+}
+
+func TestRegistry_Routines__should_record_parent_routines(t *testing.T) {
+	reg := testRegistry(t)
+	started := make(chan struct{})
+
+	parent := RunVoidNamed("parent", func(ctx Context) status.Status {
+		child := RunVoidNamed("child", func(ctx Context) status.Status {
+			close(started)
+			<-ctx.Wait()
+			return status.OK
+		})
+		defer func() { <-child.Stop() }()
+
+		<-ctx.Wait()
+		return status.OK
+	})
+	<-started
+
+	routines := reg.Routines()
+	p, ok := testFindRoutine(routines, "parent")
+	require.True(t, ok)
+	c, ok := testFindRoutine(routines, "child")
+	require.True(t, ok)
+
+	assert.Zero(t, p.Parent)
+	assert.Equal(t, p.ID, c.Parent)
+
+	<-parent.Stop()
+}
+
+func TestRegistry_Routines__should_not_register_when_disabled(t *testing.T) {
+	reg := testRegistry(t)
+	reg.Enable(false)
+
+	r := RunVoidNamed("test", func(ctx Context) status.Status {
+		<-ctx.Wait()
+		return status.OK
+	})
+	defer func() { <-r.Stop() }()
+
+	assert.Len(t, reg.Routines(), 0)
+}
+
+// Dump
+
+func TestRegistry_Dump__should_write_routine_tree_with_stacks(t *testing.T) {
+	reg := testRegistry(t)
+	started := make(chan struct{})
+
+	parent := RunVoidNamed("parent", func(ctx Context) status.Status {
+		child := RunVoidNamed("child", func(ctx Context) status.Status {
+			close(started)
+			<-ctx.Wait()
+			return status.OK
+		})
+		defer func() { <-child.Stop() }()
+
+		<-ctx.Wait()
+		return status.OK
+	})
+	defer func() { <-parent.Stop() }()
+	<-started
+
+	var buf bytes.Buffer
+	err := reg.Dump(&buf)
+	require.NoError(t, err)
+
+	s := buf.String()
+	assert.Contains(t, s, `routine 1 "parent" [running`)
+	assert.Contains(t, s, `    routine 2 "child" [running`)
+	assert.Contains(t, s, "TestRegistry_Dump__should_write_routine_tree_with_stacks")
+}
+
+// Labels
+
+func TestRunNamed__should_set_pprof_labels(t *testing.T) {
+	started := make(chan struct{})
+
+	r := RunVoidNamed("test-labels", func(ctx Context) status.Status {
+		close(started)
+		<-ctx.Wait()
+		return status.OK
+	})
+	defer func() { <-r.Stop() }()
+	<-started
+
+	var buf bytes.Buffer
+	err := pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `"routine":"test-labels"`)
+}
+
+// Service
+
+func TestNewServiceNamed__should_register_service_routine(t *testing.T) {
+	reg := testRegistry(t)
+	started := make(chan struct{})
+
+	s := NewServiceNamed("service", func(ctx Context) status.Status {
+		close(started)
+		<-ctx.Wait()
+		return status.OK
+	})
+	s.Start()
+	<-started
+
+	routines := reg.Routines()
+	require.Len(t, routines, 1)
+	assert.Equal(t, "service", routines[0].Name)
+
+	<-s.Stop()
+}
@@ -5,6 +5,8 @@
 package async
 
 import (
+	context_ "context"
+	"runtime/pprof"
 	"sync"
 
 	"github.com/basecomplextech/baselibrary/status"
@@ -74,6 +76,15 @@ func NewRoutineVoid(fn func(ctx Context) status.Status) RoutineVoid {
 	return newRoutine(fn1)
 }
 
+// NewRoutineNamed returns a new named routine, but does not start it.
+//
+// The name is used in the routine registry and as the "routine" pprof label.
+// The label replaces the pprof labels inherited from the starting goroutine,
+// because the standard library does not expose the current goroutine labels.
+func NewRoutineNamed[T any](name string, fn Func[T]) Routine[T] {
+	return newRoutineNamed(name, fn)
+}
+
 // Run
 
 // Run runs a function in a new routine, and returns the result, recovers on panics.
@@ -94,6 +105,16 @@ func Run1[T any, A any](fn Func1[T, A], arg A) Routine[T] {
 	return r
 }
 
+// RunNamed runs a function in a new named routine, and returns the result, recovers on panics.
+//
+// The name is used in the routine registry and as the "routine" pprof label,
+// see [NewRoutineNamed].
+func RunNamed[T any](name string, fn Func[T]) Routine[T] {
+	r := newRoutineNamed(name, fn)
+	r.Start()
+	return r
+}
+
 // RunVoid
 
 // RunVoid runs a procedure in a new routine, recovers on panics.
@@ -118,6 +139,20 @@ func RunVoid1[A any](fn FuncVoid1[A], arg A) RoutineVoid {
 	return r
 }
 
+// RunVoidNamed runs a procedure in a new named routine, recovers on panics.
+//
+// The name is used in the routine registry and as the "routine" pprof label,
+// see [NewRoutineNamed].
+func RunVoidNamed(name string, fn FuncVoid) RoutineVoid {
+	fn1 := func(ctx Context) (struct{}, status.Status) {
+		return struct{}{}, fn(ctx)
+	}
+
+	r := newRoutineNamed(name, fn1)
+	r.Start()
+	return r
+}
+
 // Stopped
 
 // Stopped returns a routine which has stopped with the given result and status.
@@ -139,24 +174,31 @@ func StoppedVoid(st status.Status) RoutineVoid {
 var _ Routine[any] = (*routine1[any])(nil)
 
 type routine1[T any] struct {
-	ctx CancelContext
-	fn  Func[T]
+	ctx  CancelContext
+	fn   Func[T]
+	name string // optional
 
 	mu       sync.Mutex
 	promise  Promise[T]
 	callback func(Routine[T]) // maybe nil
+	entry    *registryEntry   // nil when registry is disabled
 
 	start bool // start has been called
 	stop  bool // stop has been called
 }
 
 func newRoutine[T any](fn Func[T]) *routine1[T] {
+	return newRoutineNamed("", fn)
+}
+
+func newRoutineNamed[T any](name string, fn Func[T]) *routine1[T] {
 	ctx := NewContext()
 	promise := newPromise[T]()
 
 	return &routine1[T]{
 		ctx:     ctx,
 		fn:      fn,
+		name:    name,
 		promise: promise,
 	}
 }
@@ -195,6 +237,7 @@ func (r *routine1[T]) Start() {
 	}
 
 	r.start = true
+	r.entry = globalRegistry.register(r.name)
 	go r.run()
 }
 
@@ -216,6 +259,9 @@ func (r *routine1[T]) Stop() <-chan struct{} {
 	}
 
 	// Cancel context and return wait
+	if r.entry != nil {
+		r.entry.stopping()
+	}
 	r.ctx.Cancel()
 	r.stop = true
 	return r.promise.Wait()
@@ -251,6 +297,24 @@ func (r *routine1[T]) OnStop(fn func(Routine[T])) bool {
 // private
 
 func (r *routine1[T]) run() {
+	// Register goroutine, unregistered on complete
+	if r.entry != nil {
+		globalRegistry.running(r.entry)
+	}
+
+	// Set pprof labels, replaces inherited labels
+	if r.name != "" {
+		labels := pprof.Labels("routine", r.name)
+		pprof.Do(context_.Background(), labels, func(context_.Context) {
+			r.call()
+		})
+		return
+	}
+
+	r.call()
+}
+
+func (r *routine1[T]) call() {
 	defer r.ctx.Free()
 	defer func() {
 		if e := recover(); e != nil {
@@ -272,6 +336,11 @@ func (r *routine1[T]) complete(result T, st status.Status) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Unregister before completing promise
+	if r.entry != nil {
+		globalRegistry.unregister(r.entry)
+	}
+
 	// Complete promise
 	ok := r.promise.Complete(result, st)
 	if !ok {
@@ -0,0 +1,169 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"sync"
+
+	"github.com/basecomplextech/baselibrary/status"
+)
+
+// Scope is a structured concurrency scope, which runs child routines with a shared context.
+//
+// The first non-OK status cancels the scope context, and so all siblings. The scope
+// does not return until all children have exited. Panics in children are recovered
+// and converted into statuses.
+//
+// Example:
+//
+//	st := async.RunScope(ctx, func(s *async.Scope) status.Status {
+//		for _, url := range urls {
+//			s.Go(func(ctx async.Context) status.Status {
+//				return fetch(ctx, url)
+//			})
+//		}
+//		return status.OK
+//	})
+type Scope struct {
+	ctx CancelContext
+	sem chan struct{} // optional, limits running children
+	wg  sync.WaitGroup
+
+	mu  sync.Mutex
+	err status.Status // first non-OK status
+}
+
+// RunScope runs a function in a new scope, awaits all children, and returns the first
+// non-OK status of the function or the children.
+func RunScope(ctx Context, fn func(s *Scope) status.Status) status.Status {
+	return RunScopeLimit(ctx, 0, fn)
+}
+
+// RunScopeLimit runs a function in a new scope with a max number of running children,
+// awaits all children, and returns the first non-OK status of the function or the children.
+//
+// Zero limit means no limit.
+func RunScopeLimit(ctx Context, limit int, fn func(s *Scope) status.Status) status.Status {
+	s := newScope(ctx, limit)
+	defer s.ctx.Free()
+
+	st := s.call(fn)
+	if !st.OK() {
+		s.fail(st)
+	}
+
+	s.wg.Wait()
+	return s.status()
+}
+
+// Context returns the scope context, the context is cancelled on the first error.
+func (s *Scope) Context() Context {
+	return s.ctx
+}
+
+// Go starts a child routine with the scope context.
+//
+// When the scope has a limit, the method blocks until a child exits. The method
+// returns false and does not start the child if the scope context is cancelled,
+// the scope then returns the context status, if there is no other error.
+// Go must not be called after the scope has returned.
+func (s *Scope) Go(fn FuncVoid) bool {
+	if s.ctx.Done() {
+		return s.cancelled()
+	}
+
+	if s.sem != nil {
+		select {
+		case s.sem <- struct{}{}:
+		case <-s.ctx.Wait():
+			return s.cancelled()
+		}
+
+		// Select picks a random ready case, check cancellation again
+		if s.ctx.Done() {
+			<-s.sem
+			return s.cancelled()
+		}
+	}
+
+	s.wg.Add(1)
+	go s.run(fn)
+	return true
+}
+
+// internal
+
+func newScope(ctx Context, limit int) *Scope {
+	s := &Scope{
+		ctx: NextContext(ctx),
+		err: status.OK,
+	}
+	if limit > 0 {
+		s.sem = make(chan struct{}, limit)
+	}
+	return s
+}
+
+func (s *Scope) run(fn FuncVoid) {
+	defer s.wg.Done()
+	defer func() {
+		if s.sem != nil {
+			<-s.sem
+		}
+	}()
+
+	st := s.callChild(fn)
+	if !st.OK() {
+		s.fail(st)
+	}
+}
+
+// call calls the scope function and recovers from panics.
+func (s *Scope) call(fn func(s *Scope) status.Status) (st status.Status) {
+	defer func() {
+		if e := recover(); e != nil {
+			st = status.Recover(e)
+		}
+	}()
+
+	return fn(s)
+}
+
+// callChild calls a child function and recovers from panics.
+func (s *Scope) callChild(fn FuncVoid) (st status.Status) {
+	defer func() {
+		if e := recover(); e != nil {
+			st = status.Recover(e)
+		}
+	}()
+
+	return fn(s.ctx)
+}
+
+// fail records the first error and cancels the scope context.
+func (s *Scope) fail(st status.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.err.OK() {
+		return
+	}
+
+	s.err = st
+	s.ctx.CancelWithStatus(st)
+}
+
+// cancelled records the context status when a child is not started, and returns false.
+func (s *Scope) cancelled() bool {
+	s.fail(s.ctx.Status())
+	return false
+}
+
+func (s *Scope) status() status.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
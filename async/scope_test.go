@@ -0,0 +1,243 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunScope__should_await_all_children(t *testing.T) {
+	var n atomic.Int32
+
+	st := RunScope(NoContext(), func(s *Scope) status.Status {
+		for i := 0; i < 10; i++ {
+			s.Go(func(ctx Context) status.Status {
+				time.Sleep(time.Millisecond)
+				n.Add(1)
+				return status.OK
+			})
+		}
+		return status.OK
+	})
+
+	assert.Equal(t, status.OK, st)
+	assert.Equal(t, int32(10), n.Load())
+}
+
+func TestRunScope__should_cancel_siblings_on_first_error(t *testing.T) {
+	st0 := status.Test("test")
+
+	var cancelled atomic.Bool
+	st := RunScope(NoContext(), func(s *Scope) status.Status {
+		s.Go(func(ctx Context) status.Status {
+			<-ctx.Wait()
+			cancelled.Store(true)
+			return ctx.Status()
+		})
+		s.Go(func(ctx Context) status.Status {
+			return st0
+		})
+		return status.OK
+	})
+
+	assert.Equal(t, st0, st)
+	assert.True(t, cancelled.Load())
+}
+
+func TestRunScope__should_return_function_error(t *testing.T) {
+	st0 := status.Test("test")
+
+	st := RunScope(NoContext(), func(s *Scope) status.Status {
+		s.Go(func(ctx Context) status.Status {
+			<-ctx.Wait()
+			return ctx.Status()
+		})
+		return st0
+	})
+
+	assert.Equal(t, st0, st)
+}
+
+func TestRunScope__should_recover_child_panic(t *testing.T) {
+	st := RunScope(NoContext(), func(s *Scope) status.Status {
+		s.Go(func(ctx Context) status.Status {
+			panic("test")
+		})
+		return status.OK
+	})
+
+	assert.Equal(t, status.CodeError, st.Code)
+}
+
+func TestRunScope__should_recover_function_panic(t *testing.T) {
+	st := RunScope(NoContext(), func(s *Scope) status.Status {
+		panic("test")
+	})
+
+	assert.Equal(t, status.CodeError, st.Code)
+}
+
+func TestRunScope__should_cancel_children_when_parent_cancelled(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Free()
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		ctx.Cancel()
+	}()
+
+	st := RunScope(ctx, func(s *Scope) status.Status {
+		s.Go(func(ctx Context) status.Status {
+			<-ctx.Wait()
+			return ctx.Status()
+		})
+		return status.OK
+	})
+
+	assert.Equal(t, status.Cancelled, st)
+}
+
+func TestRunScope__should_return_context_status_when_parent_cancelled_before_go(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Free()
+	ctx.Cancel()
+
+	var ok bool
+	st := RunScope(ctx, func(s *Scope) status.Status {
+		ok = s.Go(func(ctx Context) status.Status {
+			return status.OK
+		})
+		return status.OK
+	})
+
+	assert.False(t, ok)
+	assert.Equal(t, status.Cancelled, st)
+}
+
+// Limit
+
+func TestRunScopeLimit__should_limit_running_children(t *testing.T) {
+	var running atomic.Int32
+	var maxRunning atomic.Int32
+
+	st := RunScopeLimit(NoContext(), 2, func(s *Scope) status.Status {
+		for i := 0; i < 10; i++ {
+			s.Go(func(ctx Context) status.Status {
+				n := running.Add(1)
+				defer running.Add(-1)
+
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+
+				time.Sleep(time.Millisecond)
+				return status.OK
+			})
+		}
+		return status.OK
+	})
+
+	assert.Equal(t, status.OK, st)
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestRunScopeLimit__should_not_start_children_when_cancelled(t *testing.T) {
+	st0 := status.Test("test")
+
+	var started atomic.Int32
+	var ok bool
+
+	st := RunScopeLimit(NoContext(), 1, func(s *Scope) status.Status {
+		s.Go(func(ctx Context) status.Status {
+			started.Add(1)
+			return st0
+		})
+
+		ok = s.Go(func(ctx Context) status.Status {
+			started.Add(1)
+			return status.OK
+		})
+		return status.OK
+	})
+
+	assert.Equal(t, st0, st)
+	assert.False(t, ok)
+	assert.Equal(t, int32(1), started.Load())
+}
+
+func TestRunScope__should_not_start_children_when_cancelled(t *testing.T) {
+	st0 := status.Test("test")
+
+	var started atomic.Int32
+	var ok bool
+
+	st := RunScope(NoContext(), func(s *Scope) status.Status {
+		s.fail(st0)
+
+		ok = s.Go(func(ctx Context) status.Status {
+			started.Add(1)
+			return status.OK
+		})
+		return status.OK
+	})
+
+	assert.Equal(t, st0, st)
+	assert.False(t, ok)
+	assert.Equal(t, int32(0), started.Load())
+}
+
+func TestRunScopeLimit__should_not_start_children_when_cancelled_and_slot_free(t *testing.T) {
+	st0 := status.Test("test")
+
+	var started atomic.Int32
+	for i := 0; i < 100; i++ {
+		RunScopeLimit(NoContext(), 1, func(s *Scope) status.Status {
+			s.fail(st0)
+
+			s.Go(func(ctx Context) status.Status {
+				started.Add(1)
+				return status.OK
+			})
+			return status.OK
+		})
+	}
+
+	assert.Equal(t, int32(0), started.Load())
+}
+
+func TestRunScopeLimit__should_return_context_status_when_parent_cancelled_during_go(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Free()
+
+	var ok bool
+	st := RunScopeLimit(ctx, 1, func(s *Scope) status.Status {
+		s.Go(func(ctx Context) status.Status {
+			time.Sleep(10 * time.Millisecond)
+			return status.OK
+		})
+
+		// Cancel parent while waiting for a slot
+		go func() {
+			time.Sleep(time.Millisecond)
+			ctx.Cancel()
+		}()
+
+		ok = s.Go(func(ctx Context) status.Status {
+			return status.OK
+		})
+		return status.OK
+	})
+
+	assert.False(t, ok)
+	assert.Equal(t, status.Cancelled, st)
+}
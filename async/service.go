@@ -42,12 +42,22 @@ func NewService(fn func(ctx Context) status.Status) Service {
 	return newService(fn)
 }
 
+// NewServiceNamed returns a new stopped named service.
+//
+// The name is used as the service routine name in the routine registry and pprof labels.
+func NewServiceNamed(name string, fn func(ctx Context) status.Status) Service {
+	s := newService(fn)
+	s.name = name
+	return s
+}
+
 // internal
 
 var _ Service = (*service)(nil)
 
 type service struct {
-	fn func(ctx Context) status.Status
+	fn   func(ctx Context) status.Status
+	name string // optional
 
 	// flags
 	running MutFlag
@@ -109,7 +119,7 @@ func (s *service) Start() status.Status {
 	s.stopped.Unset()
 
 	// Make routine
-	r = NewRoutineVoid(s.run)
+	r = newRoutineNamed(s.name, s.run)
 	r.OnStop(s.onStop)
 	s.routine.Set(r)
 
@@ -144,11 +154,11 @@ func (s *service) Wait() <-chan struct{} {
 
 // private
 
-func (s *service) run(ctx Context) status.Status {
+func (s *service) run(ctx Context) (struct{}, status.Status) {
 	defer s.running.Unset()
 	s.running.Set()
 
-	return s.fn(ctx)
+	return struct{}{}, s.fn(ctx)
 }
 
 func (s *service) onStop(r RoutineVoid) {
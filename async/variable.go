@@ -5,6 +5,7 @@
 package async
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/basecomplextech/baselibrary/async/internal/context"
@@ -14,13 +15,53 @@ import (
 )
 
 // [Experimental] Variable is an asynchronous variable which can be set, cleared, or failed.
+//
+// Each change increments the variable version, the changes can be awaited
+// via WaitChange or watched via Watch.
 type Variable[T any] interface {
 	// Get returns the current value/error, or false if pending.
 	Get() (T, bool, status.Status)
 
 	// GetWait returns the current value or waits for it or an error.
+	//
+	// The method returns the context status if the context is cancelled while waiting.
 	GetWait(ctx context.Context) (T, status.Status)
 
+	// State returns the current value, status and version.
+	State() VariableState[T]
+
+	// Version returns the current version, the version is incremented on each change.
+	Version() uint64
+
+	// Changes
+
+	// WaitChange waits until the version is greater than the given one, and returns
+	// the current state, or the context status.
+	//
+	// Intermediate changes are coalesced, use Watch with [WatchAll] to receive each change.
+	WaitChange(ctx context.Context, version uint64) (VariableState[T], status.Status)
+
+	// Watch returns a stream of variable states, the stream starts with the current state.
+	// The stream must be freed after use.
+	//
+	// Example:
+	//
+	//	stream := config.Watch(async.WatchLatest)
+	//	defer stream.Free()
+	//
+	//	for {
+	//		state, ok, st := stream.Next(ctx)
+	//		switch {
+	//		case !st.OK():
+	//			return st
+	//		case !ok:
+	//			return status.OK
+	//		}
+	//
+	//		apply(state.Value)
+	//	}
+	Watch(policy WatchPolicy) Stream[VariableState[T]]
+
 	// Set
 
 	// Clear clears the variable.
@@ -46,6 +87,28 @@ func NewVariable[T any]() Variable[T] {
 	return newVariable[T]()
 }
 
+// VariableState is a variable value, status and version.
+type VariableState[T any] struct {
+	Value   T
+	Status  status.Status // none when pending
+	Done    bool          // false when pending or cleared
+	Version uint64
+}
+
+// WatchPolicy specifies how a variable watch handles changes, when a watcher is slower
+// than the variable updates.
+type WatchPolicy int
+
+const (
+	// WatchLatest coalesces changes, a slow watcher skips intermediate states
+	// and receives only the latest one.
+	WatchLatest WatchPolicy = iota
+
+	// WatchAll buffers changes, a slow watcher receives every state in order.
+	// The buffer is unbounded.
+	WatchAll
+)
+
 // internal
 
 var _ Variable[any] = (*variable[any])(nil)
@@ -59,6 +122,10 @@ type variable[T any] struct {
 	st    status.Status
 
 	promise opt.Opt[Promise[T]]
+
+	version  uint64
+	changed  chan struct{}                    // closed on change, lazily allocated
+	watchers map[*variableWatcher[T]]struct{} // watchers with WatchAll policy
 }
 
 func newVariable[T any]() *variable[T] {
@@ -76,6 +143,8 @@ func (v *variable[T]) Get() (T, bool, status.Status) {
 }
 
 // GetWait returns the current value or waits for it or an error.
+//
+// The method returns the context status if the context is cancelled while waiting.
 func (v *variable[T]) GetWait(ctx context.Context) (T, status.Status) {
 	// Get value or promise
 	value, ok, st, promise := v.get()
@@ -87,12 +156,62 @@ func (v *variable[T]) GetWait(ctx context.Context) (T, status.Status) {
 	p, _ := promise.Unwrap()
 	select {
 	case <-ctx.Wait():
-		return value, st
+		return value, ctx.Status()
 	case <-p.Wait():
 		return p.Result()
 	}
 }
 
+// State returns the current value, status and version.
+func (v *variable[T]) State() VariableState[T] {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.state()
+}
+
+// Version returns the current version, the version is incremented on each change.
+func (v *variable[T]) Version() uint64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.version
+}
+
+// Changes
+
+// WaitChange waits until the version is greater than the given one, and returns
+// the current state, or the context status.
+func (v *variable[T]) WaitChange(ctx context.Context, version uint64) (VariableState[T], status.Status) {
+	for {
+		state, changed := v.stateAfter(version)
+		if changed == nil {
+			return state, status.OK
+		}
+
+		select {
+		case <-ctx.Wait():
+			return state, ctx.Status()
+		case <-changed:
+		}
+	}
+}
+
+// Watch returns a stream of variable states, the stream starts with the current state.
+func (v *variable[T]) Watch(policy WatchPolicy) Stream[VariableState[T]] {
+	switch policy {
+	case WatchLatest:
+		w := &variableLatestWatcher[T]{v: v}
+		return NewStream(w.next)
+
+	case WatchAll:
+		w := v.addWatcher()
+		return NewStreamFree(w.next, func() { v.removeWatcher(w) })
+	}
+
+	panic(fmt.Sprintf("unknown watch policy %d", policy))
+}
+
 // Set
 
 // Clear clears the variable.
@@ -111,6 +230,9 @@ func (v *variable[T]) Clear() {
 	if !ok || p.Done() {
 		v.promise.Set(NewPromise[T]())
 	}
+
+	// Notify watchers
+	v.notify()
 }
 
 // Complete sets the value and status.
@@ -128,6 +250,9 @@ func (v *variable[T]) Complete(value T, st status.Status) {
 	if ok {
 		p.Complete(value, st)
 	}
+
+	// Notify watchers
+	v.notify()
 }
 
 // Fail sets the error.
@@ -162,3 +287,116 @@ func (v *variable[T]) get() (T, bool, status.Status, opt.Opt[Promise[T]]) {
 
 	return v.value, v.done, v.st, v.promise
 }
+
+func (v *variable[T]) state() VariableState[T] {
+	return VariableState[T]{
+		Value:   v.value,
+		Status:  v.st,
+		Done:    v.done,
+		Version: v.version,
+	}
+}
+
+// stateAfter returns the current state if its version is greater than the given one,
+// otherwise returns a channel which is closed on the next change.
+func (v *variable[T]) stateAfter(version uint64) (VariableState[T], <-chan struct{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	state := v.state()
+	if v.version > version {
+		return state, nil
+	}
+
+	if v.changed == nil {
+		v.changed = make(chan struct{})
+	}
+	return state, v.changed
+}
+
+// notify increments the version, notifies waiters and watchers, must be called under lock.
+func (v *variable[T]) notify() {
+	v.version++
+
+	if v.changed != nil {
+		close(v.changed)
+		v.changed = nil
+	}
+
+	if len(v.watchers) == 0 {
+		return
+	}
+
+	state := v.state()
+	for w := range v.watchers {
+		w.queue.Push(state)
+	}
+}
+
+// watchers
+
+func (v *variable[T]) addWatcher() *variableWatcher[T] {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	w := &variableWatcher[T]{queue: newQueue(v.state())}
+	if v.watchers == nil {
+		v.watchers = make(map[*variableWatcher[T]]struct{})
+	}
+	v.watchers[w] = struct{}{}
+	return w
+}
+
+func (v *variable[T]) removeWatcher(w *variableWatcher[T]) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.watchers, w)
+}
+
+// watcher
+
+// variableWatcher buffers all changes.
+type variableWatcher[T any] struct {
+	queue *queue[VariableState[T]]
+}
+
+func (w *variableWatcher[T]) next(ctx context.Context) (VariableState[T], bool, status.Status) {
+	for {
+		state, ok := w.queue.Poll()
+		if ok {
+			return state, true, status.OK
+		}
+
+		select {
+		case <-ctx.Wait():
+			return state, false, ctx.Status()
+		case <-w.queue.Wait():
+		}
+	}
+}
+
+// variableLatestWatcher receives only the latest changes.
+type variableLatestWatcher[T any] struct {
+	v       *variable[T]
+	version uint64
+	started bool
+}
+
+func (w *variableLatestWatcher[T]) next(ctx context.Context) (VariableState[T], bool, status.Status) {
+	// Return current state first
+	if !w.started {
+		state := w.v.State()
+		w.started = true
+		w.version = state.Version
+		return state, true, status.OK
+	}
+
+	state, st := w.v.WaitChange(ctx, w.version)
+	if !st.OK() {
+		return state, false, st
+	}
+
+	w.version = state.Version
+	return state, true, status.OK
+}
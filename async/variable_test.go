@@ -0,0 +1,209 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Version
+
+func TestVariable_Version__should_increment_on_each_change(t *testing.T) {
+	v := newVariable[int]()
+	assert.Equal(t, uint64(0), v.Version())
+
+	v.Set(1)
+	assert.Equal(t, uint64(1), v.Version())
+
+	v.Clear()
+	assert.Equal(t, uint64(2), v.Version())
+
+	v.Fail(status.Test("test"))
+	assert.Equal(t, uint64(3), v.Version())
+
+	state := v.State()
+	assert.Equal(t, uint64(3), state.Version)
+	assert.True(t, state.Done)
+	assert.Equal(t, status.Test("test"), state.Status)
+}
+
+// GetWait
+
+func TestVariable_GetWait__should_return_context_status_when_cancelled(t *testing.T) {
+	v := newVariable[int]()
+
+	ctx := NewContext()
+	ctx.Cancel()
+
+	_, st := v.GetWait(ctx)
+	assert.Equal(t, status.Cancelled, st)
+}
+
+func TestVariable_GetWait__should_return_context_status_when_cancelled_while_waiting(t *testing.T) {
+	v := newVariable[int]()
+	ctx := NewContext()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ctx.Cancel()
+	}()
+
+	_, st := v.GetWait(ctx)
+	assert.Equal(t, status.Cancelled, st)
+}
+
+func TestVariable_GetWait__should_return_value_when_set_while_waiting(t *testing.T) {
+	v := newVariable[int]()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		v.Set(1)
+	}()
+
+	value, st := v.GetWait(NoContext())
+	require.True(t, st.OK())
+	assert.Equal(t, 1, value)
+}
+
+// WaitChange
+
+func TestVariable_WaitChange__should_return_state_if_version_is_greater(t *testing.T) {
+	v := newVariable[int]()
+	v.Set(1)
+
+	state, st := v.WaitChange(NoContext(), 0)
+	require.True(t, st.OK())
+	assert.Equal(t, 1, state.Value)
+	assert.Equal(t, uint64(1), state.Version)
+}
+
+func TestVariable_WaitChange__should_await_next_change(t *testing.T) {
+	v := newVariable[int]()
+	v.Set(1)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		v.Set(2)
+	}()
+
+	state, st := v.WaitChange(NoContext(), 1)
+	require.True(t, st.OK())
+	assert.Equal(t, 2, state.Value)
+	assert.Equal(t, uint64(2), state.Version)
+}
+
+func TestVariable_WaitChange__should_return_context_status_when_cancelled(t *testing.T) {
+	v := newVariable[int]()
+	v.Set(1)
+
+	ctx := NewContext()
+	ctx.Cancel()
+
+	_, st := v.WaitChange(ctx, 1)
+	assert.Equal(t, status.Cancelled, st)
+}
+
+// Watch
+
+func TestVariable_Watch__should_start_with_current_state(t *testing.T) {
+	for _, policy := range []WatchPolicy{WatchLatest, WatchAll} {
+		v := newVariable[int]()
+		v.Set(1)
+
+		stream := v.Watch(policy)
+		defer stream.Free()
+
+		state, ok, st := stream.Next(NoContext())
+		require.True(t, st.OK())
+		require.True(t, ok)
+		assert.Equal(t, 1, state.Value)
+		assert.Equal(t, uint64(1), state.Version)
+	}
+}
+
+func TestVariable_Watch__should_coalesce_changes_with_latest_policy(t *testing.T) {
+	v := newVariable[int]()
+
+	stream := v.Watch(WatchLatest)
+	defer stream.Free()
+
+	_, _, st := stream.Next(NoContext())
+	require.True(t, st.OK())
+
+	v.Set(1)
+	v.Set(2)
+	v.Set(3)
+
+	state, ok, st := stream.Next(NoContext())
+	require.True(t, st.OK())
+	require.True(t, ok)
+	assert.Equal(t, 3, state.Value)
+	assert.Equal(t, uint64(3), state.Version)
+}
+
+func TestVariable_Watch__should_receive_all_changes_with_all_policy(t *testing.T) {
+	v := newVariable[int]()
+
+	stream := v.Watch(WatchAll)
+	defer stream.Free()
+
+	v.Set(1)
+	v.Clear()
+	v.Set(2)
+
+	var states []VariableState[int]
+	for i := 0; i < 4; i++ {
+		state, ok, st := stream.Next(NoContext())
+		require.True(t, st.OK())
+		require.True(t, ok)
+		states = append(states, state)
+	}
+
+	assert.Equal(t, uint64(0), states[0].Version)
+	assert.False(t, states[0].Done)
+
+	assert.Equal(t, 1, states[1].Value)
+	assert.True(t, states[1].Done)
+
+	assert.Equal(t, uint64(2), states[2].Version)
+	assert.False(t, states[2].Done)
+
+	assert.Equal(t, 2, states[3].Value)
+	assert.Equal(t, uint64(3), states[3].Version)
+}
+
+func TestVariable_Watch__should_return_context_status_when_cancelled(t *testing.T) {
+	for _, policy := range []WatchPolicy{WatchLatest, WatchAll} {
+		v := newVariable[int]()
+
+		stream := v.Watch(policy)
+		defer stream.Free()
+
+		_, _, st := stream.Next(NoContext())
+		require.True(t, st.OK())
+
+		ctx := NewContext()
+		ctx.Cancel()
+
+		_, ok, st := stream.Next(ctx)
+		assert.False(t, ok)
+		assert.Equal(t, status.Cancelled, st)
+	}
+}
+
+func TestVariable_Watch__should_remove_watcher_on_free(t *testing.T) {
+	v := newVariable[int]()
+
+	stream := v.Watch(WatchAll)
+	assert.Len(t, v.watchers, 1)
+
+	stream.Free()
+	assert.Len(t, v.watchers, 0)
+}
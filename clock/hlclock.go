@@ -7,9 +7,9 @@ package clock
 import (
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/basecomplextech/baselibrary/proto/pclock"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 // HLClock is a thread-safe hybrid logical clock.
@@ -29,7 +29,12 @@ type HLClock interface {
 
 // NewHLClock returns a new hybrid logical clock.
 func NewHLClock() HLClock {
-	return newHLClock()
+	return newHLClock(clockwork.Real())
+}
+
+// NewHLClockWith returns a new hybrid logical clock which uses a physical clock.
+func NewHLClockWith(clock clockwork.Clock) HLClock {
+	return newHLClock(clock)
 }
 
 // internal
@@ -37,19 +42,21 @@ func NewHLClock() HLClock {
 var _ HLClock = (*hlClock)(nil)
 
 type hlClock struct {
+	clock clockwork.Clock
+
 	mu    sync.RWMutex
 	wall  int64 // can be accessed atomically by readers
 	logic uint32
 }
 
-func newHLClock() *hlClock {
-	return &hlClock{}
+func newHLClock(clock clockwork.Clock) *hlClock {
+	return &hlClock{clock: clock}
 }
 
 // Read returns the current time, does not update the last time, non-blocking.
 func (c *hlClock) Read() pclock.HLTimestamp {
 	// Return now if greater than last
-	now := time.Now().UnixNano()
+	now := c.clock.Now().UnixNano()
 	last := c.loadWall()
 	if now > last {
 		return pclock.HLTimestamp{Wall: now}
@@ -64,7 +71,7 @@ func (c *hlClock) Next() pclock.HLTimestamp {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	next := time.Now().UnixNano()
+	next := c.clock.Now().UnixNano()
 	if next > c.wall {
 		c.logic = 0
 		c.storeWall(next)
@@ -9,24 +9,37 @@ import (
 	"time"
 
 	"github.com/basecomplextech/baselibrary/proto/pclock"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestHLClock_Read__should_return_current_time(t *testing.T) {
-	c := newHLClock()
+	c := newHLClock(clockwork.Real())
 	now := c.Read()
 
 	assert.NotZero(t, now.Wall)
 	assert.Zero(t, now.Logic)
 }
 
+func TestHLClock_Read__should_return_physical_clock_time(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+	c := newHLClock(clock)
+
+	now := c.Read()
+	assert.Equal(t, clock.Now().UnixNano(), now.Wall)
+
+	clock.Advance(time.Second)
+	now = c.Read()
+	assert.Equal(t, clock.Now().UnixNano(), now.Wall)
+}
+
 func TestHLClock_Read__should_return_last_time_if_now_less(t *testing.T) {
 	a := pclock.HLTimestamp{
 		Wall:  time.Now().UnixNano() + 1000_000,
 		Logic: 123,
 	}
 
-	c := newHLClock()
+	c := newHLClock(clockwork.Real())
 	c.mu.Lock()
 	c.wall = a.Wall
 	c.logic = a.Logic
@@ -39,7 +52,7 @@ func TestHLClock_Read__should_return_last_time_if_now_less(t *testing.T) {
 // Next
 
 func TestHLClock_Next__should_return_next_time(t *testing.T) {
-	c := newHLClock()
+	c := newHLClock(clockwork.Real())
 	a := c.Next()
 	b := c.Next()
 
@@ -52,7 +65,7 @@ func TestHLClock_Next__should_increment_logic_when_now_less_than_last_wall(t *te
 		Logic: 123,
 	}
 
-	c := newHLClock()
+	c := newHLClock(clockwork.Real())
 	c.mu.Lock()
 	c.wall = a.Wall
 	c.logic = a.Logic
@@ -64,7 +77,7 @@ func TestHLClock_Next__should_increment_logic_when_now_less_than_last_wall(t *te
 }
 
 func TestHLClock_Next__should_update_last_time(t *testing.T) {
-	c := newHLClock()
+	c := newHLClock(clockwork.Real())
 	a := c.Next()
 	b := c.load()
 
@@ -79,7 +92,7 @@ func TestHLClock_Update__should_update_last_time_increment_logicuence(t *testing
 		Logic: 123,
 	}
 
-	c := newHLClock()
+	c := newHLClock(clockwork.Real())
 
 	b := c.Update(a)
 	assert.Equal(t, a.Wall, b.Wall)
@@ -92,7 +105,7 @@ func TestHLClock_Update__should_increment_logic_when_equal_wall_times(t *testing
 		Logic: 123,
 	}
 
-	c := newHLClock()
+	c := newHLClock(clockwork.Real())
 
 	_ = c.Update(a)
 	b := c.Update(a)
@@ -13,11 +13,21 @@ func Sum32(b []byte) uint32 {
 	return uint32(xxh3.Hash(b) >> 32)
 }
 
+// Sum32String returns a XXH3 hash of a string high bits as uint32.
+func Sum32String(s string) uint32 {
+	return uint32(xxh3.HashString(s) >> 32)
+}
+
 // Sum64 returns a XXH3 hash as uint64.
 func Sum64(b []byte) uint64 {
 	return xxh3.Hash(b)
 }
 
+// Sum64String returns a XXH3 hash of a string as uint64.
+func Sum64String(s string) uint64 {
+	return xxh3.HashString(s)
+}
+
 // Hash64
 
 // Hash64 computes an XXH3 hash and returns it as uint64.
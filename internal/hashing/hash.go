@@ -9,9 +9,11 @@ import (
 	"math"
 
 	"github.com/basecomplextech/baselibrary/bin"
+	"github.com/basecomplextech/baselibrary/crypto/xxh3"
 )
 
 // Hash returns a hash of a key, panics if the key type is not supported.
+// Strings and byte slices are hashed with XXH3, other keys can implement [Hasher].
 // Add more types as needed.
 func Hash[K any](key K) uint32 {
 	switch v := any(key).(type) {
@@ -49,18 +51,9 @@ func Hash[K any](key K) uint32 {
 		return uint32(v1 ^ (v1 >> 32)) // xor of two halves
 
 	case string:
-		h := uint32(0)
-		for _, c := range v {
-			h = h*31 + uint32(c)
-		}
-		return h
-
+		return xxh3.Sum32String(v)
 	case []byte:
-		h := uint32(0)
-		for _, c := range v {
-			h = h*31 + uint32(c)
-		}
-		return h
+		return xxh3.Sum32(v)
 
 	case bin.Bin64:
 		return v.Hash32()
@@ -77,6 +70,8 @@ func Hash[K any](key K) uint32 {
 	panic(fmt.Sprintf("unsupported type %T", key))
 }
 
+// Hasher is implemented by keys which compute their own hashes.
 type Hasher interface {
+	// Hash32 returns a 32-bit hash.
 	Hash32() uint32
 }
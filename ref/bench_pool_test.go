@@ -0,0 +1,43 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import "testing"
+
+func BenchmarkPool(b *testing.B) {
+	p := newRefPool(PoolOptions[*poolTestObject]{
+		New: func() *poolTestObject { return &poolTestObject{} },
+	})
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r := p.Acquire()
+		r.Release()
+	}
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkPool_Parallel(b *testing.B) {
+	p := newRefPool(PoolOptions[*poolTestObject]{
+		New: func() *poolTestObject { return &poolTestObject{} },
+	})
+
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r := p.Acquire()
+			r.Release()
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
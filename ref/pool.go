@@ -0,0 +1,257 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/basecomplextech/baselibrary/pools"
+)
+
+// Pool is a pool of objects which are returned to the pool on the final release
+// of their references.
+//
+// Leaked references are counted in the stats, and reported with their stacks by
+// reference tracking, see [EnableTracking].
+//
+// Usage:
+//
+//	pool := ref.NewPool(ref.PoolOptions[*Buffer]{
+//		New:   newBuffer,
+//		Reset: (*Buffer).Reset,
+//	})
+//
+//	buf := pool.Acquire()
+//	defer buf.Release()
+type Pool[T any] interface {
+	// Acquire returns a reference to a pooled or a new object.
+	// The final release resets the object and returns it to the pool.
+	Acquire() R[T]
+
+	// Stats returns the pool statistics.
+	Stats() PoolStats
+}
+
+// PoolOptions specifies the pool options.
+type PoolOptions[T any] struct {
+	// New returns a new object, required.
+	New func() T
+
+	// Reset resets an object before returning it to the pool, nil means no reset.
+	Reset func(T)
+
+	// Pool is the underlying pool, nil means a new pool.
+	//
+	// The pool should not have a new function, objects returned by its new function
+	// are not counted as created.
+	Pool pools.Pool[T]
+
+	// MaxIdle is the max number of idle objects in the pool, zero means unlimited.
+	// Objects released above the limit are dropped.
+	//
+	// The limit is approximate, because the underlying pool can drop idle objects on GC.
+	MaxIdle int
+}
+
+// PoolStats holds pool statistics.
+type PoolStats struct {
+	Acquired int64 // acquired references
+	Released int64 // finally released references
+	Created  int64 // new objects
+	Dropped  int64 // released objects dropped above the max idle limit
+	Idle     int64 // approximate number of idle objects
+}
+
+// NewPool returns a new reference pool, panics if the new function is nil.
+func NewPool[T any](opts PoolOptions[T]) Pool[T] {
+	return newRefPool(opts)
+}
+
+// Outstanding returns the number of acquired but not released references,
+// i.e. the number of leaked references when all users have released them.
+func (s PoolStats) Outstanding() int64 {
+	return s.Acquired - s.Released
+}
+
+// internal
+
+var _ Pool[any] = (*refPool[any])(nil)
+
+type refPool[T any] struct {
+	opts PoolOptions[T]
+	pool pools.Pool[T]
+
+	acquired atomic.Int64
+	released atomic.Int64
+	created  atomic.Int64
+	dropped  atomic.Int64
+	idle     atomic.Int64
+}
+
+func newRefPool[T any](opts PoolOptions[T]) *refPool[T] {
+	if opts.New == nil {
+		panic("ref pool new function is required")
+	}
+
+	pool := opts.Pool
+	if pool == nil {
+		pool = pools.NewPool[T]()
+	}
+
+	return &refPool[T]{
+		opts: opts,
+		pool: pool,
+	}
+}
+
+// Acquire returns a reference to a pooled or a new object.
+// The final release resets the object and returns it to the pool.
+func (p *refPool[T]) Acquire() R[T] {
+	idle := p.idle.Load()
+
+	obj, ok := p.pool.Get()
+	if ok {
+		p.decIdle()
+	} else {
+		// Idle objects could have been dropped on GC,
+		// do not reset the counter if an object has been put concurrently.
+		p.idle.CompareAndSwap(idle, 0)
+		p.created.Add(1)
+		obj = p.opts.New()
+	}
+
+	p.acquired.Add(1)
+	return newPoolRef(p, obj)
+}
+
+// Stats returns the pool statistics.
+func (p *refPool[T]) Stats() PoolStats {
+	return PoolStats{
+		Acquired: p.acquired.Load(),
+		Released: p.released.Load(),
+		Created:  p.created.Load(),
+		Dropped:  p.dropped.Load(),
+		Idle:     p.idle.Load(),
+	}
+}
+
+// private
+
+// put resets an object and returns it to the pool, or drops it above the max idle limit.
+func (p *refPool[T]) put(obj T) {
+	p.released.Add(1)
+
+	if reset := p.opts.Reset; reset != nil {
+		reset(obj)
+	}
+
+	if limit := p.opts.MaxIdle; limit > 0 {
+		if p.idle.Add(1) > int64(limit) {
+			p.idle.Add(-1)
+			p.dropped.Add(1)
+			return
+		}
+	} else {
+		p.idle.Add(1)
+	}
+
+	p.pool.Put(obj)
+}
+
+// decIdle decrements the number of idle objects, but not below zero,
+// because the pool can return objects which have not been put, e.g. from its new function.
+func (p *refPool[T]) decIdle() {
+	for {
+		n := p.idle.Load()
+		if n <= 0 {
+			return
+		}
+		if p.idle.CompareAndSwap(n, n-1) {
+			return
+		}
+	}
+}
+
+// ref
+
+var _ R[any] = (*poolRef[any])(nil)
+
+type poolRef[T any] struct {
+	refs Atomic64
+	pool *refPool[T]
+	obj  T
+}
+
+func newPoolRef[T any](pool *refPool[T], obj T) *poolRef[T] {
+	r := &poolRef[T]{
+		pool: pool,
+		obj:  obj,
+	}
+	r.refs.Init(1)
+
+	if trackEnabled.Load() {
+		trackNew(r)
+	}
+	return r
+}
+
+// Refcount returns the number of current references.
+func (r *poolRef[T]) Refcount() int64 {
+	return r.refs.Refcount()
+}
+
+// Acquire tries to increment refcount and returns true, or false if already released.
+func (r *poolRef[T]) Acquire() bool {
+	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
+		return true
+	}
+
+	r.refs.Release()
+	return false
+}
+
+// Retain increments refcount, panics when count is <= 0.
+func (r *poolRef[T]) Retain() {
+	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
+		return
+	}
+
+	r.refs.Release()
+	panic(fmt.Sprintf("retain: %T already released", r.obj))
+}
+
+// Release decrements refcount and returns the object to the pool if the count is 0.
+func (r *poolRef[T]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
+	released := r.refs.Release()
+	if !released {
+		return
+	}
+
+	var zero T
+	obj := r.obj
+	r.obj = zero
+
+	r.pool.put(obj)
+}
+
+// Unwrap returns the object or panics if the refcount is 0.
+func (r *poolRef[T]) Unwrap() T {
+	v := r.refs.Refcount()
+	if v <= 0 {
+		panic(fmt.Sprintf("unwrap: %T already released", r.obj))
+	}
+	return r.obj
+}
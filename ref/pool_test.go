@@ -0,0 +1,207 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import (
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type poolTestObject struct {
+	val int
+}
+
+func testPool(t *testing.T, maxIdle int) *refPool[*poolTestObject] {
+	return newRefPool(PoolOptions[*poolTestObject]{
+		New:     func() *poolTestObject { return &poolTestObject{} },
+		Reset:   func(obj *poolTestObject) { obj.val = 0 },
+		Pool:    &testSlicePool[*poolTestObject]{},
+		MaxIdle: maxIdle,
+	})
+}
+
+// testSlicePool is a deterministic pool, unlike sync.Pool which drops objects on GC
+// and randomly in race mode.
+type testSlicePool[T any] struct {
+	objs   []T
+	onMiss func() // called when the pool is empty, simulates concurrent puts
+}
+
+func (p *testSlicePool[T]) Get() (obj T, ok bool) {
+	n := len(p.objs)
+	if n == 0 {
+		if p.onMiss != nil {
+			p.onMiss()
+		}
+		return
+	}
+
+	obj = p.objs[n-1]
+	p.objs = p.objs[:n-1]
+	return obj, true
+}
+
+func (p *testSlicePool[T]) New() T {
+	obj, ok := p.Get()
+	if !ok {
+		panic("no pool new function")
+	}
+	return obj
+}
+
+func (p *testSlicePool[T]) Put(obj T) {
+	p.objs = append(p.objs, obj)
+}
+
+// New
+
+func TestNewPool__should_panic_without_new_function(t *testing.T) {
+	assert.Panics(t, func() {
+		NewPool(PoolOptions[int]{})
+	})
+}
+
+// Acquire
+
+func TestPool_Acquire__should_create_new_object(t *testing.T) {
+	p := testPool(t, 0)
+
+	r := p.Acquire()
+	require.NotNil(t, r.Unwrap())
+	assert.Equal(t, int64(1), r.Refcount())
+
+	stats := p.Stats()
+	assert.Equal(t, int64(1), stats.Acquired)
+	assert.Equal(t, int64(1), stats.Created)
+	assert.Equal(t, int64(1), stats.Outstanding())
+	r.Release()
+}
+
+func TestPool_Acquire__should_reuse_released_object(t *testing.T) {
+	p := testPool(t, 0)
+
+	r := p.Acquire()
+	obj := r.Unwrap()
+	obj.val = 123
+	r.Release()
+	assert.Equal(t, int64(1), p.Stats().Idle)
+
+	r1 := p.Acquire()
+	defer r1.Release()
+
+	assert.Same(t, obj, r1.Unwrap())
+	assert.Equal(t, 0, r1.Unwrap().val)
+
+	stats := p.Stats()
+	assert.Equal(t, int64(2), stats.Acquired)
+	assert.Equal(t, int64(1), stats.Created)
+	assert.Equal(t, int64(0), stats.Idle)
+}
+
+func TestPool_Acquire__should_not_reset_idle_objects_put_concurrently(t *testing.T) {
+	p := testPool(t, 0)
+	r0 := p.Acquire()
+
+	pool := p.pool.(*testSlicePool[*poolTestObject])
+	pool.onMiss = func() {
+		pool.onMiss = nil
+		r0.Release()
+	}
+
+	r1 := p.Acquire()
+	defer r1.Release()
+
+	stats := p.Stats()
+	assert.Equal(t, int64(2), stats.Created)
+	assert.Equal(t, int64(1), stats.Idle)
+}
+
+func TestPool_Acquire__should_not_count_objects_from_pool_new_function(t *testing.T) {
+	p := newRefPool(PoolOptions[*poolTestObject]{
+		New:  func() *poolTestObject { return &poolTestObject{} },
+		Pool: pools.NewPoolFunc(func() *poolTestObject { return &poolTestObject{} }),
+	})
+
+	refs := []R[*poolTestObject]{p.Acquire(), p.Acquire()}
+	for _, r := range refs {
+		r.Release()
+	}
+	refs = []R[*poolTestObject]{p.Acquire(), p.Acquire(), p.Acquire()}
+	for _, r := range refs {
+		r.Release()
+	}
+
+	stats := p.Stats()
+	assert.Equal(t, int64(5), stats.Acquired)
+	assert.Equal(t, int64(0), stats.Created)
+	assert.GreaterOrEqual(t, stats.Idle, int64(0))
+	assert.LessOrEqual(t, stats.Idle, int64(3))
+}
+
+// Release
+
+func TestPool_Release__should_return_object_on_final_release(t *testing.T) {
+	p := testPool(t, 0)
+
+	r := p.Acquire()
+	r.Retain()
+
+	r.Release()
+	assert.Equal(t, int64(0), p.Stats().Released)
+
+	r.Release()
+	assert.Equal(t, int64(1), p.Stats().Released)
+	assert.Equal(t, int64(0), p.Stats().Outstanding())
+
+	assert.Panics(t, func() {
+		r.Unwrap()
+	})
+}
+
+func TestPool_Release__should_drop_objects_above_max_idle(t *testing.T) {
+	p := testPool(t, 2)
+
+	refs := []R[*poolTestObject]{p.Acquire(), p.Acquire(), p.Acquire()}
+	for _, r := range refs {
+		r.Release()
+	}
+
+	stats := p.Stats()
+	assert.Equal(t, int64(3), stats.Released)
+	assert.Equal(t, int64(1), stats.Dropped)
+	assert.Equal(t, int64(2), stats.Idle)
+}
+
+// Stats
+
+func TestPool_Stats__should_count_outstanding_references(t *testing.T) {
+	p := testPool(t, 0)
+
+	r0 := p.Acquire()
+	r1 := p.Acquire()
+	r0.Release()
+
+	assert.Equal(t, int64(1), p.Stats().Outstanding())
+	r1.Release()
+	assert.Equal(t, int64(0), p.Stats().Outstanding())
+}
+
+func TestPool_Acquire__should_track_references(t *testing.T) {
+	testTracking(t, true)
+	p := testPool(t, 0)
+
+	mark := TrackingMark()
+	r := p.Acquire()
+
+	refs := TrackedRefs(mark)
+	require.Len(t, refs, 1)
+	assert.Contains(t, refs[0].String(), "TestPool_Acquire__should_track_references")
+
+	r.Release()
+	assert.Empty(t, TrackedRefs(mark))
+}
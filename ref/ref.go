@@ -47,6 +47,10 @@ type Ref interface {
 func New[T Freer](obj T) R[T] {
 	r := &ref[T]{obj: obj}
 	r.refs.Init(1)
+
+	if trackEnabled.Load() {
+		trackNew(r)
+	}
 	return r
 }
 
@@ -68,23 +72,33 @@ func (r *ref[T]) Refcount() int64 {
 
 func (r *ref[T]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.Release()
+	r.refs.Release()
 	return false
 }
 
 func (r *ref[T]) Retain() {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 
-	r.Release()
+	r.refs.Release()
 	panic(fmt.Sprintf("retain: %T already released", r.obj))
 }
 
 func (r *ref[T]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	released := r.refs.Release()
 	if !released {
 		return
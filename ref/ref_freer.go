@@ -26,6 +26,10 @@ func NewFreer[T any](obj T, freer Freer) R[T] {
 			freer: freer,
 		}
 		r.refs.Init(1)
+
+		if trackEnabled.Load() {
+			trackNew(r)
+		}
 		return r
 	}
 
@@ -35,6 +39,10 @@ func NewFreer[T any](obj T, freer Freer) R[T] {
 
 	r := &refFreerPooled[T]{refFreerState: s}
 	r.refs.Init(1)
+
+	if trackEnabled.Load() {
+		trackNew(r)
+	}
 	return r
 }
 
@@ -55,23 +63,33 @@ func (r *refFreer[T]) Refcount() int64 {
 // Acquire tries to increment refcount and returns true, or false if already released.
 func (r *refFreer[T]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.Release()
+	r.refs.Release()
 	return false
 }
 
 func (r *refFreer[T]) Retain() {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 
-	r.Release()
+	r.refs.Release()
 	panic(fmt.Sprintf("retain: %T already released", r.obj))
 }
 
 func (r *refFreer[T]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	released := r.refs.Release()
 	if !released {
 		return
@@ -112,23 +130,33 @@ func (r *refFreerPooled[T]) Refcount() int64 {
 
 func (r *refFreerPooled[T]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.Release()
+	r.refs.Release()
 	return false
 }
 
 func (r *refFreerPooled[T]) Retain() {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 
-	r.Release()
+	r.refs.Release()
 	panic(fmt.Sprintf("retain: %T already released", r))
 }
 
 func (r *refFreerPooled[T]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	released := r.refs.Release()
 	if !released {
 		return
@@ -28,6 +28,10 @@ func Next[T, T1 any](obj T, parent R[T1]) R[T] {
 			parent: parent,
 		}
 		r.refs.Init(1)
+
+		if trackEnabled.Load() {
+			trackNew(r)
+		}
 		return r
 	}
 
@@ -37,6 +41,10 @@ func Next[T, T1 any](obj T, parent R[T1]) R[T] {
 
 	r := &refNextPooled[T, T1]{refNextState: s}
 	r.refs.Init(1)
+
+	if trackEnabled.Load() {
+		trackNew(r)
+	}
 	return r
 }
 
@@ -70,23 +78,33 @@ func (r *refNext[T, T1]) Refcount() int64 {
 
 func (r *refNext[T, T1]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.Release()
+	r.refs.Release()
 	return false
 }
 
 func (r *refNext[T, T1]) Retain() {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 
-	r.Release()
+	r.refs.Release()
 	panic(fmt.Sprintf("retain: %T already released", r.obj))
 }
 
 func (r *refNext[T, T1]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	released := r.refs.Release()
 	if !released {
 		return
@@ -127,23 +145,33 @@ func (r *refNextPooled[T, T1]) Refcount() int64 {
 
 func (r *refNextPooled[T, T1]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.Release()
+	r.refs.Release()
 	return false
 }
 
 func (r *refNextPooled[T, T1]) Retain() {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 
-	r.Release()
+	r.refs.Release()
 	panic(fmt.Sprintf("retain: %T already released", r))
 }
 
 func (r *refNextPooled[T, T1]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	released := r.refs.Release()
 	if !released {
 		return
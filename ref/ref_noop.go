@@ -12,6 +12,10 @@ import (
 func NewNoop[T any](obj T) R[T] {
 	r := &refNoop[T]{obj: obj}
 	r.refs.Init(1)
+
+	if trackEnabled.Load() {
+		trackNew(r)
+	}
 	return r
 }
 
@@ -30,15 +34,21 @@ func (r *refNoop[T]) Refcount() int64 {
 
 func (r *refNoop[T]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.Release()
+	r.refs.Release()
 	return false
 }
 
 func (r *refNoop[T]) Retain() {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 
@@ -46,6 +56,10 @@ func (r *refNoop[T]) Retain() {
 }
 
 func (r *refNoop[T]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	r.refs.Release()
 }
 
@@ -0,0 +1,241 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/bin"
+	"github.com/basecomplextech/baselibrary/compare"
+	"github.com/basecomplextech/baselibrary/ref"
+	"github.com/basecomplextech/baselibrary/ref/refmap"
+)
+
+const benchMapSize = 100_000
+
+// benchMap is implemented by refhamt and refmap maps.
+type benchMap[K comparable] interface {
+	Get(key K) (ref.R[*Value], bool)
+	SetRetain(key K, value ref.R[*Value])
+	Delete(key K)
+	Free()
+}
+
+// benchStringKeys returns keys in random order, so that both maps are accessed randomly.
+func benchStringKeys() []string {
+	keys := make([]string, 0, benchMapSize)
+	for i := 0; i < benchMapSize; i++ {
+		key := fmt.Sprintf("key-%08d", i)
+		keys = append(keys, key)
+	}
+
+	rand.Shuffle(len(keys), func(i, j int) {
+		keys[i], keys[j] = keys[j], keys[i]
+	})
+	return keys
+}
+
+func benchBin128Keys() []bin.Bin128 {
+	keys := make([]bin.Bin128, 0, benchMapSize)
+	for i := 0; i < benchMapSize; i++ {
+		keys = append(keys, bin.Random128())
+	}
+	return keys
+}
+
+func benchFill[K comparable](m benchMap[K], keys []K, value ref.R[*Value]) {
+	for _, key := range keys {
+		m.SetRetain(key, value)
+	}
+}
+
+func benchGet[K comparable](b *testing.B, m benchMap[K], keys []K) {
+	value := testValue(1)
+	benchFill(m, keys, value)
+	defer m.Free()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		key := keys[i%len(keys)]
+		_, ok := m.Get(key)
+		if !ok {
+			b.Fatal("key not found")
+		}
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func benchSetRetain[K comparable](b *testing.B, m benchMap[K], keys []K) {
+	value := testValue(1)
+	defer m.Free()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		key := keys[i%len(keys)]
+		m.SetRetain(key, value)
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func benchDelete[K comparable](b *testing.B, newMap func() benchMap[K], keys []K) {
+	value := testValue(1)
+	m := newMap()
+	benchFill(m, keys, value)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		j := i % len(keys)
+		if i > 0 && j == 0 {
+			b.StopTimer()
+			m.Free()
+			m = newMap()
+			benchFill(m, keys, value)
+			b.StartTimer()
+		}
+
+		m.Delete(keys[j])
+	}
+
+	// Exclude refill time
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+
+	m.Free()
+}
+
+// String
+
+func BenchmarkString_Get(b *testing.B) {
+	keys := benchStringKeys()
+
+	b.Run("refhamt", func(b *testing.B) {
+		m := New[string, *Value](true)
+		benchGet(b, m, keys)
+	})
+	b.Run("refmap", func(b *testing.B) {
+		m := refmap.New[string, *Value](true, compare.String)
+		benchGet(b, m, keys)
+	})
+}
+
+func BenchmarkString_SetRetain(b *testing.B) {
+	keys := benchStringKeys()
+
+	b.Run("refhamt", func(b *testing.B) {
+		m := New[string, *Value](true)
+		benchSetRetain(b, m, keys)
+	})
+	b.Run("refmap", func(b *testing.B) {
+		m := refmap.New[string, *Value](true, compare.String)
+		benchSetRetain(b, m, keys)
+	})
+}
+
+func BenchmarkString_Delete(b *testing.B) {
+	keys := benchStringKeys()
+
+	b.Run("refhamt", func(b *testing.B) {
+		benchDelete(b, func() benchMap[string] {
+			return New[string, *Value](true)
+		}, keys)
+	})
+	b.Run("refmap", func(b *testing.B) {
+		benchDelete(b, func() benchMap[string] {
+			return refmap.New[string, *Value](true, compare.String)
+		}, keys)
+	})
+}
+
+// Bin128
+
+func BenchmarkBin128_Get(b *testing.B) {
+	keys := benchBin128Keys()
+
+	b.Run("refhamt", func(b *testing.B) {
+		m := New[bin.Bin128, *Value](true)
+		benchGet(b, m, keys)
+	})
+	b.Run("refmap", func(b *testing.B) {
+		m := refmap.New[bin.Bin128, *Value](true, compare.Bin128)
+		benchGet(b, m, keys)
+	})
+}
+
+func BenchmarkBin128_SetRetain(b *testing.B) {
+	keys := benchBin128Keys()
+
+	b.Run("refhamt", func(b *testing.B) {
+		m := New[bin.Bin128, *Value](true)
+		benchSetRetain(b, m, keys)
+	})
+	b.Run("refmap", func(b *testing.B) {
+		m := refmap.New[bin.Bin128, *Value](true, compare.Bin128)
+		benchSetRetain(b, m, keys)
+	})
+}
+
+func BenchmarkBin128_Delete(b *testing.B) {
+	keys := benchBin128Keys()
+
+	b.Run("refhamt", func(b *testing.B) {
+		benchDelete(b, func() benchMap[bin.Bin128] {
+			return New[bin.Bin128, *Value](true)
+		}, keys)
+	})
+	b.Run("refmap", func(b *testing.B) {
+		benchDelete(b, func() benchMap[bin.Bin128] {
+			return refmap.New[bin.Bin128, *Value](true, compare.Bin128)
+		}, keys)
+	})
+}
+
+// Clone
+
+func BenchmarkBin128_CloneSet(b *testing.B) {
+	keys := benchBin128Keys()
+	value := testValue(1)
+
+	m := New[bin.Bin128, *Value](true)
+	benchFill(m, keys, value)
+	m.Freeze()
+	defer m.Free()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		key := keys[i%len(keys)]
+
+		m1 := m.Clone()
+		m1.SetRetain(key, value)
+		m1.Free()
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
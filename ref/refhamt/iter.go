@@ -0,0 +1,182 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"github.com/basecomplextech/baselibrary/collect/slices2"
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// Iterator sequentially iterates over map items in the order of key hashes.
+//
+// Usage:
+//
+//	it := refhamt.Iterator()
+//	defer it.Free()
+//
+//	it.SeekToStart()
+//
+//	for it.Next() {
+//		key := it.Key()
+//		value := it.Value()
+//	}
+type Iterator[K comparable, V any] interface {
+	// OK returns true when the iterator points to a valid item, or false on end.
+	OK() bool
+
+	// Key returns the current key or zero, the key is valid until the next iteration.
+	Key() K
+
+	// Value returns the current value or nil, the value is valid until the next iteration.
+	Value() ref.R[V]
+
+	// Iterating
+
+	// Next moves to the next item.
+	Next() bool
+
+	// Seeking
+
+	// SeekToStart positions the iterator at the start.
+	SeekToStart() bool
+
+	// Internal
+
+	// Free frees the iterator, implements the ref.Free interface.
+	Free()
+}
+
+// internal
+
+var _ Iterator[int, any] = (*iterator[int, any])(nil)
+
+type iterator[K comparable, V any] struct {
+	*iterState[K, V]
+}
+
+type iterState[K comparable, V any] struct {
+	m   *hamt[K, V]
+	mod int // track concurrent modifications
+
+	stack []iterFrame[K, V]
+	entry *entry[K, V] // current entry or nil
+}
+
+// iterFrame is a node with an index of the next entry, followed by the children.
+type iterFrame[K comparable, V any] struct {
+	node  node[K, V]
+	index int
+}
+
+func newIterator[K comparable, V any](m *hamt[K, V]) *iterator[K, V] {
+	it := &iterator[K, V]{acquireIterState[K, V]()}
+	it.m = m
+	it.mod = m.mod
+	return it
+}
+
+func (s *iterState[K, V]) reset() {
+	stack := slices2.Truncate(s.stack)
+
+	*s = iterState[K, V]{
+		stack: stack,
+	}
+}
+
+// OK returns true when the iterator points to a valid item, or false on end.
+func (it *iterator[K, V]) OK() bool {
+	return it.entry != nil
+}
+
+// Key returns the current key or zero, the key is valid until the next iteration.
+func (it *iterator[K, V]) Key() (key K) {
+	if it.entry == nil {
+		return
+	}
+	return it.entry.key
+}
+
+// Value returns the current value or nil, the value is valid until the next iteration.
+func (it *iterator[K, V]) Value() ref.R[V] {
+	if it.entry == nil {
+		return nil
+	}
+	return it.entry.value
+}
+
+// Iterating
+
+// Next moves to the next item.
+func (it *iterator[K, V]) Next() bool {
+	if it.mod != it.m.mod {
+		panic("refhamt concurrent modification error")
+	}
+
+	for len(it.stack) > 0 {
+		frame := &it.stack[len(it.stack)-1]
+		node := frame.node
+
+		// Return next entry
+		n := node.entryCount()
+		if frame.index < n {
+			it.entry = node.entry(frame.index)
+			frame.index++
+			return true
+		}
+
+		// Push next child
+		i := frame.index - n
+		if i < node.childCount() {
+			frame.index++
+			it.stack = append(it.stack, iterFrame[K, V]{node: node.child(i)})
+			continue
+		}
+
+		// Pop node
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+
+	it.entry = nil
+	return false
+}
+
+// Seeking
+
+// SeekToStart positions the iterator at the start.
+func (it *iterator[K, V]) SeekToStart() bool {
+	it.mod = it.m.mod
+	it.entry = nil
+
+	it.stack = it.stack[:0]
+	it.stack = append(it.stack, iterFrame[K, V]{node: it.m.root})
+	return true
+}
+
+// Internal
+
+// Free frees the iterator, implements the ref.Free interface.
+func (it *iterator[K, V]) Free() {
+	state := it.iterState
+	it.iterState = nil
+	releaseIterState(state)
+}
+
+// state pool
+
+var iterStatePools = pools.NewPools()
+
+func acquireIterState[K comparable, V any]() *iterState[K, V] {
+	v, ok := pools.Acquire[*iterState[K, V]](iterStatePools)
+	if ok {
+		return v
+	}
+	return &iterState[K, V]{}
+}
+
+func releaseIterState[K comparable, V any](s *iterState[K, V]) {
+	s.reset()
+	pools.Release(iterStatePools, s)
+}
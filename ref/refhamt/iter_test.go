@@ -0,0 +1,111 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator_Next__should_iterate_all_items(t *testing.T) {
+	m := testHamt(t, testValues(1000)...)
+	defer m.Free()
+
+	it := m.Iterator()
+	defer it.Free()
+
+	var keys []int
+	for it.Next() {
+		require.Equal(t, it.Key(), it.Value().Unwrap().val)
+		keys = append(keys, it.Key())
+	}
+	slices.Sort(keys)
+
+	assert.Equal(t, testRange(1000), keys)
+	assert.False(t, it.OK())
+	assert.Nil(t, it.Value())
+}
+
+func TestIterator_Next__should_iterate_colliding_keys(t *testing.T) {
+	m := testHamtWithHash(t, testCollideHash, testValues(100)...)
+	defer m.Free()
+
+	it := m.Iterator()
+	defer it.Free()
+
+	var keys []int
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	slices.Sort(keys)
+
+	assert.Equal(t, testRange(100), keys)
+}
+
+func TestIterator_Next__should_return_false_when_empty(t *testing.T) {
+	m := testHamt(t)
+	defer m.Free()
+
+	it := m.Iterator()
+	defer it.Free()
+
+	assert.False(t, it.Next())
+	assert.False(t, it.OK())
+}
+
+func TestIterator_Next__should_panic_on_concurrent_modification(t *testing.T) {
+	m := testHamt(t, testValues(10)...)
+	defer m.Free()
+
+	it := m.Iterator()
+	defer it.Free()
+
+	it.Next()
+	m.SetRetain(10, testValue(10))
+
+	assert.Panics(t, func() {
+		it.Next()
+	})
+}
+
+func TestIterator_SeekToStart__should_restart_iteration(t *testing.T) {
+	m := testHamt(t, testValues(100)...)
+	defer m.Free()
+
+	it := m.Iterator()
+	defer it.Free()
+
+	var keys0 []int
+	for it.Next() {
+		keys0 = append(keys0, it.Key())
+	}
+
+	it.SeekToStart()
+
+	var keys1 []int
+	for it.Next() {
+		keys1 = append(keys1, it.Key())
+	}
+
+	assert.Len(t, keys0, 100)
+	assert.Equal(t, keys0, keys1)
+}
+
+func TestMap_All__should_stop_on_break(t *testing.T) {
+	m := testHamt(t, testValues(100)...)
+	defer m.Free()
+
+	n := 0
+	for range m.All() {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, n)
+}
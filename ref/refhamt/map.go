@@ -0,0 +1,333 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"iter"
+
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// Map is an immutable unordered map which stores countable references,
+// implemented as a hash array mapped trie.
+//
+// The map retains/releases references internally, but does not retain them when iterating
+// or returning. Clones share unmodified nodes with the original map. Items are iterated
+// in the order of key hashes.
+type Map[K comparable, V any] interface {
+	// Empty returns true if the map is empty.
+	Empty() bool
+
+	// Length returns the number of items in this map.
+	Length() int64
+
+	// Mutable returns true if the map is mutable.
+	Mutable() bool
+
+	// Clone
+
+	// Clone returns a mutable clone of the map.
+	Clone() Map[K, V]
+
+	// Freeze makes the map immutable.
+	Freeze()
+
+	// Read
+
+	// Get returns an item by a key, does not retain the value.
+	Get(key K) (ref.R[V], bool)
+
+	// Contains returns true if the map contains a key.
+	Contains(key K) bool
+
+	// Iterator returns an iterator, the iterator does not retain the values.
+	Iterator() Iterator[K, V]
+
+	// Keys returns all keys.
+	Keys() []K
+
+	// Sequences
+
+	// All returns a sequence of all items, does not retain the values.
+	// The map must not be modified during iteration.
+	All() iter.Seq2[K, ref.R[V]]
+
+	// Write
+
+	// Set adds an item to the map, wraps it into a reference.
+	Set(key K, value V)
+
+	// SetRetain adds an item reference to the map, and retains it.
+	SetRetain(key K, value ref.R[V])
+
+	// SetNoRetain adds an item reference to the map, does not retain it.
+	SetNoRetain(key K, value ref.R[V])
+
+	// Delete deletes an item by a key, releases its value.
+	Delete(key K)
+
+	// Internal
+
+	// Free frees the map, releases all values.
+	Free()
+}
+
+// New returns an empty map with the default hash function.
+func New[K comparable, V any](mutable bool) Map[K, V] {
+	return NewWithHash[K, V](mutable, Hash[K])
+}
+
+// NewWithHash returns an empty map with a custom hash function.
+func NewWithHash[K comparable, V any](mutable bool, hash HashFunc[K]) Map[K, V] {
+	m := newHamt[K, V](hash)
+	if !mutable {
+		m.Freeze()
+	}
+	return m
+}
+
+// NewRef returns an empty map wrapped in a ref.
+func NewRef[K comparable, V any](mutable bool) ref.R[Map[K, V]] {
+	m := New[K, V](mutable)
+	return ref.New(m)
+}
+
+// internal
+
+var _ Map[int, any] = (*hamt[int, any])(nil)
+
+type hamt[K comparable, V any] struct {
+	*state[K, V]
+}
+
+type state[K comparable, V any] struct {
+	hash HashFunc[K]
+
+	root    node[K, V]
+	mod     int // track concurrent modifications
+	length  int64
+	mutable bool
+}
+
+func newHamt[K comparable, V any](hash HashFunc[K]) *hamt[K, V] {
+	m := &hamt[K, V]{acquireState[K, V]()}
+	m.hash = hash
+	m.root = newBitmapNode[K, V]()
+	m.mutable = true
+	return m
+}
+
+func (s *state[K, V]) reset() {
+	*s = state[K, V]{}
+}
+
+// Empty returns true if the map is empty.
+func (m *hamt[K, V]) Empty() bool {
+	return m.length == 0
+}
+
+// Length returns the number of items in this map.
+func (m *hamt[K, V]) Length() int64 {
+	return m.length
+}
+
+// Mutable returns true if the map is mutable.
+func (m *hamt[K, V]) Mutable() bool {
+	return m.mutable
+}
+
+// Clone
+
+// Clone returns a mutable clone of the map.
+func (m *hamt[K, V]) Clone() Map[K, V] {
+	if m.mutable {
+		panic("cannot clone mutable refhamt")
+	}
+
+	m1 := &hamt[K, V]{acquireState[K, V]()}
+	m1.hash = m.hash
+	m1.root = m.root.clone()
+	m1.length = m.length
+	m1.mutable = true
+	return m1
+}
+
+// Freeze makes the map immutable.
+func (m *hamt[K, V]) Freeze() {
+	if !m.mutable {
+		return
+	}
+
+	m.mutable = false
+	m.root.freeze()
+}
+
+// Read
+
+// Get returns an item by a key, does not retain the value.
+func (m *hamt[K, V]) Get(key K) (ref.R[V], bool) {
+	hash := m.hash(key)
+	return m.root.get(key, hash, 0)
+}
+
+// Contains returns true if the map contains a key.
+func (m *hamt[K, V]) Contains(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Iterator returns an iterator, the iterator does not retain the values.
+func (m *hamt[K, V]) Iterator() Iterator[K, V] {
+	it := newIterator(m)
+	it.SeekToStart()
+	return it
+}
+
+// Keys returns all keys.
+func (m *hamt[K, V]) Keys() []K {
+	if m.length == 0 {
+		return nil
+	}
+
+	keys := make([]K, 0, m.length)
+	for key := range m.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Sequences
+
+// All returns a sequence of all items, does not retain the values.
+func (m *hamt[K, V]) All() iter.Seq2[K, ref.R[V]] {
+	return func(yield func(K, ref.R[V]) bool) {
+		it := newIterator(m)
+		defer it.Free()
+
+		it.SeekToStart()
+		for it.Next() {
+			if !yield(it.Key(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Write
+
+// Set adds an item to the map, wraps it into a reference.
+func (m *hamt[K, V]) Set(key K, value V) {
+	var r ref.R[V]
+
+	v, ok := (any)(value).(ref.Freer)
+	if ok {
+		r = ref.NewFreer(value, v)
+	} else {
+		r = ref.NewNoop(value)
+	}
+
+	m.SetRetain(key, r)
+	r.Release()
+}
+
+// SetRetain adds an item reference to the map, and retains it.
+func (m *hamt[K, V]) SetRetain(key K, value ref.R[V]) {
+	if !m.mutable {
+		panic("operation on immutable refhamt")
+	}
+
+	// Mutate root
+	hash := m.hash(key)
+	root := m.mutateRoot()
+
+	// Insert item
+	m.mod++
+	added := root.put(key, hash, value, 0)
+	if !added {
+		return
+	}
+
+	// Increment length
+	m.length++
+}
+
+// SetNoRetain adds an item reference to the map, does not retain it.
+func (m *hamt[K, V]) SetNoRetain(key K, value ref.R[V]) {
+	m.SetRetain(key, value)
+	value.Release()
+}
+
+// Delete deletes an item by a key, releases its value.
+func (m *hamt[K, V]) Delete(key K) {
+	if !m.mutable {
+		panic("operation on immutable refhamt")
+	}
+
+	// Return if absent, do not clone nodes
+	hash := m.hash(key)
+	if _, ok := m.root.get(key, hash, 0); !ok {
+		return
+	}
+
+	// Mutate root
+	root := m.mutateRoot()
+
+	// Delete item
+	root.delete(key, hash, 0)
+
+	// Decrement length
+	m.mod++
+	m.length--
+}
+
+// Internal
+
+// Free frees the map, releases all values.
+func (m *hamt[K, V]) Free() {
+	m.root.release()
+	m.root = nil
+	m.length = 0
+
+	// Release state
+	s := m.state
+	m.state = nil
+	releaseState[K, V](s)
+}
+
+// private
+
+func (m *hamt[K, V]) mutateRoot() node[K, V] {
+	if m.root.mutable() {
+		return m.root
+	}
+
+	// Clone and replace root
+	prev := m.root
+	next := m.root.clone()
+	m.root = next
+	m.mod++
+
+	// Release previous
+	prev.release()
+	return next
+}
+
+// state pool
+
+var statePools = pools.NewPools()
+
+func acquireState[K comparable, V any]() *state[K, V] {
+	v, ok := pools.Acquire[*state[K, V]](statePools)
+	if ok {
+		return v
+	}
+	return &state[K, V]{}
+}
+
+func releaseState[K comparable, V any](s *state[K, V]) {
+	s.reset()
+	pools.Release(statePools, s)
+}
@@ -0,0 +1,417 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/ref"
+	"github.com/basecomplextech/baselibrary/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHamt(t tests.T, values ...ref.R[*Value]) *hamt[int, *Value] {
+	m := newHamt[int, *Value](Hash[int])
+	for _, value := range values {
+		m.SetRetain(value.Unwrap().val, value)
+	}
+	return m
+}
+
+func testHamtWithHash(t tests.T, hash HashFunc[int], values ...ref.R[*Value]) *hamt[int, *Value] {
+	m := newHamt[int, *Value](hash)
+	for _, value := range values {
+		m.SetRetain(value.Unwrap().val, value)
+	}
+	return m
+}
+
+func testValues(n int) []ref.R[*Value] {
+	values := make([]ref.R[*Value], 0, n)
+	for i := 0; i < n; i++ {
+		values = append(values, testValue(i))
+	}
+	return values
+}
+
+func testKeys(m Map[int, *Value]) []int {
+	keys := m.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+func testRange(n int) []int {
+	keys := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, i)
+	}
+	return keys
+}
+
+// testCollideHash returns the same hash for keys with equal remainders.
+func testCollideHash(key int) uint32 {
+	return uint32(key % 4)
+}
+
+// testCheckCanonical checks that no child node can be inlined into its parent.
+func testCheckCanonical(t *testing.T, m *hamt[int, *Value]) {
+	walk(m.root, func(n node[int, *Value]) {
+		b, ok := n.(*bitmapNode[int, *Value])
+		if !ok {
+			return
+		}
+
+		for _, child := range b.children {
+			single := child.childCount() == 0 && child.entryCount() == 1
+			require.False(t, single, "child node with a single entry")
+		}
+	})
+}
+
+// New
+
+func TestNew__should_return_empty_map(t *testing.T) {
+	m := New[string, *Value](true)
+	defer m.Free()
+
+	assert.True(t, m.Empty())
+	assert.Equal(t, int64(0), m.Length())
+	assert.True(t, m.Mutable())
+	assert.Nil(t, m.Keys())
+}
+
+// Free
+
+func TestMap_Free__should_release_values(t *testing.T) {
+	values := testValues(1000)
+	m := testHamt(t, values...)
+
+	for _, value := range values {
+		assert.Equal(t, int64(2), value.Refcount())
+	}
+
+	m.Free()
+	for _, value := range values {
+		assert.Equal(t, int64(1), value.Refcount())
+	}
+}
+
+// Get
+
+func TestMap_Get__should_return_item_value(t *testing.T) {
+	values := testValues(1000)
+	m := testHamt(t, values...)
+	defer m.Free()
+
+	for i, value := range values {
+		v, ok := m.Get(i)
+		require.True(t, ok)
+		assert.Same(t, value, v)
+	}
+
+	_, ok := m.Get(1000)
+	assert.False(t, ok)
+	assert.False(t, m.Contains(-1))
+}
+
+func TestMap_Get__should_not_retain_value(t *testing.T) {
+	value := testValue(1)
+	m := testHamt(t, value)
+	defer m.Free()
+
+	m.Get(1)
+	assert.Equal(t, int64(2), value.Refcount())
+}
+
+// Set
+
+func TestMap_SetRetain__should_retain_value(t *testing.T) {
+	m := testHamt(t)
+	defer m.Free()
+
+	value := testValue(1)
+	m.SetRetain(1, value)
+
+	assert.Equal(t, int64(2), value.Refcount())
+	assert.Equal(t, int64(1), m.Length())
+}
+
+func TestMap_SetRetain__should_retain_release_value_on_replace(t *testing.T) {
+	value0 := testValue(1)
+	m := testHamt(t, value0)
+	defer m.Free()
+
+	value1 := testValue(1)
+	m.SetRetain(1, value1)
+
+	assert.Equal(t, int64(1), value0.Refcount())
+	assert.Equal(t, int64(2), value1.Refcount())
+	assert.Equal(t, int64(1), m.Length())
+}
+
+func TestMap_SetRetain__should_panic_when_immutable(t *testing.T) {
+	m := testHamt(t)
+	defer m.Free()
+	m.Freeze()
+
+	value := testValue(1)
+	assert.Panics(t, func() {
+		m.SetRetain(1, value)
+	})
+}
+
+func TestMap_Set__should_wrap_value_into_reference(t *testing.T) {
+	m := New[string, *Value](true)
+
+	value := &Value{val: 1}
+	m.Set("a", value)
+
+	v, ok := m.Get("a")
+	require.True(t, ok)
+	assert.Same(t, value, v.Unwrap())
+
+	m.Free()
+	assert.True(t, value.freed)
+}
+
+func TestMap_SetRetain__should_store_colliding_keys(t *testing.T) {
+	values := testValues(100)
+	m := testHamtWithHash(t, testCollideHash, values...)
+	defer m.Free()
+
+	assert.Equal(t, int64(100), m.Length())
+	assert.Equal(t, testRange(100), testKeys(m))
+
+	for i, value := range values {
+		v, ok := m.Get(i)
+		require.True(t, ok)
+		assert.Same(t, value, v)
+	}
+}
+
+// Delete
+
+func TestMap_Delete__should_delete_items(t *testing.T) {
+	values := testValues(1000)
+	m := testHamt(t, values...)
+	defer m.Free()
+
+	for i := 0; i < 1000; i += 2 {
+		m.Delete(i)
+	}
+
+	assert.Equal(t, int64(500), m.Length())
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, i%2 == 1, m.Contains(i))
+	}
+	testCheckCanonical(t, m)
+}
+
+func TestMap_Delete__should_release_values(t *testing.T) {
+	values := testValues(100)
+	m := testHamt(t, values...)
+	defer m.Free()
+
+	for i := range values {
+		m.Delete(i)
+	}
+
+	assert.True(t, m.Empty())
+	for _, value := range values {
+		assert.Equal(t, int64(1), value.Refcount())
+	}
+}
+
+func TestMap_Delete__should_ignore_absent_keys(t *testing.T) {
+	m := testHamt(t, testValues(100)...)
+	defer m.Free()
+	m.Freeze()
+
+	m1 := testUnwrap(m.Clone())
+	defer m1.Free()
+
+	m1.Delete(1000)
+	assert.Equal(t, int64(100), m1.Length())
+}
+
+func TestMap_Delete__should_delete_colliding_keys(t *testing.T) {
+	values := testValues(100)
+	m := testHamtWithHash(t, testCollideHash, values...)
+	defer m.Free()
+
+	for i := 0; i < 100; i++ {
+		if i%4 != 0 || i < 96 {
+			m.Delete(i)
+		}
+	}
+
+	assert.Equal(t, []int{96}, testKeys(m))
+	testCheckCanonical(t, m)
+
+	for _, value := range values {
+		if value.Unwrap().val != 96 {
+			assert.Equal(t, int64(1), value.Refcount())
+		}
+	}
+}
+
+// Clone
+
+func TestMap_Clone__should_panic_when_mutable(t *testing.T) {
+	m := testHamt(t)
+	defer m.Free()
+
+	assert.Panics(t, func() {
+		m.Clone()
+	})
+}
+
+func TestMap_Clone__should_not_modify_original_on_mutation(t *testing.T) {
+	values := testValues(1000)
+	m := testHamt(t, values...)
+	defer m.Free()
+	m.Freeze()
+
+	m1 := m.Clone()
+	defer m1.Free()
+
+	m1.SetRetain(1000, testValue(1000))
+	m1.SetRetain(1, testValue(-1))
+	m1.Delete(2)
+
+	assert.Equal(t, testRange(1000), testKeys(m))
+	v, _ := m.Get(1)
+	assert.Same(t, values[1], v)
+
+	assert.Equal(t, int64(1000), m1.Length())
+	v, _ = m1.Get(1)
+	assert.Equal(t, -1, v.Unwrap().val)
+	assert.False(t, m1.Contains(2))
+}
+
+func TestMap_Clone__should_share_unmodified_nodes(t *testing.T) {
+	m := testHamt(t, testValues(1000)...)
+	defer m.Free()
+	m.Freeze()
+
+	m1 := testUnwrap(m.Clone())
+	defer m1.Free()
+
+	m1.SetRetain(1, testValue(-1))
+
+	root := m.root.(*bitmapNode[int, *Value])
+	root1 := m1.root.(*bitmapNode[int, *Value])
+
+	shared := 0
+	for i, child := range root.children {
+		if child == root1.children[i] {
+			shared++
+		}
+	}
+	assert.Equal(t, len(root.children)-1, shared)
+}
+
+func TestMap_Clone__should_release_values_on_free(t *testing.T) {
+	values := testValues(1000)
+	m := testHamt(t, values...)
+	m.Freeze()
+
+	m1 := m.Clone()
+	for i := 0; i < 1000; i += 3 {
+		m1.Delete(i)
+	}
+	m1.SetRetain(1, testValue(-1))
+
+	m.Free()
+	m1.Free()
+
+	for _, value := range values {
+		assert.Equal(t, int64(1), value.Refcount())
+	}
+}
+
+// Freeze
+
+func TestMap_Freeze__should_recursively_freeze_trie(t *testing.T) {
+	m := testHamt(t, testValues(1000)...)
+	defer m.Free()
+
+	m.Freeze()
+	assert.False(t, m.Mutable())
+
+	walk(m.root, func(n node[int, *Value]) {
+		assert.False(t, n.mutable())
+	})
+}
+
+// Random
+
+func TestMap__should_match_builtin_map(t *testing.T) {
+	hashes := map[string]HashFunc[int]{
+		"default":   Hash[int],
+		"collision": func(key int) uint32 { return uint32(key % 64) },
+	}
+
+	for name, hash := range hashes {
+		t.Run(name, func(t *testing.T) {
+			m := testHamtWithHash(t, hash)
+			defer m.Free()
+
+			model := make(map[int]int)
+			for i := 0; i < 10_000; i++ {
+				key := rand.IntN(1000)
+
+				if rand.IntN(3) == 0 {
+					m.Delete(key)
+					delete(model, key)
+					continue
+				}
+
+				m.SetNoRetain(key, testValue(i))
+				model[key] = i
+			}
+
+			require.Equal(t, int64(len(model)), m.Length())
+			for key, val := range model {
+				v, ok := m.Get(key)
+				require.True(t, ok)
+				require.Equal(t, val, v.Unwrap().val)
+			}
+			testCheckCanonical(t, m)
+		})
+	}
+}
+
+// private
+
+func testUnwrap[K comparable, V any](m Map[K, V]) *hamt[K, V] {
+	return m.(*hamt[K, V])
+}
+
+func walk[K comparable, V any](n node[K, V], fn func(node[K, V])) {
+	fn(n)
+
+	for i := 0; i < n.childCount(); i++ {
+		walk(n.child(i), fn)
+	}
+}
+
+// test value
+
+type Value struct {
+	val   int
+	freed bool
+}
+
+func testValue(v int) ref.R[*Value] {
+	return ref.New(&Value{val: v})
+}
+
+func (v *Value) Free() {
+	v.freed = true
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"math/bits"
+
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+const (
+	levelBits = 5
+	levelMask = 1<<levelBits - 1
+	maxShift  = 32 // hash bits, collision nodes are below this shift
+)
+
+type node[K comparable, V any] interface {
+	retain()
+	release()
+	refcount() int64
+
+	clone() node[K, V]
+	freeze()
+	mutable() bool
+
+	entryCount() int
+	entry(i int) *entry[K, V]
+	childCount() int
+	child(i int) node[K, V]
+
+	get(key K, hash uint32, shift uint) (ref.R[V], bool)
+	put(key K, hash uint32, value ref.R[V], shift uint) bool
+	delete(key K, hash uint32, shift uint) bool
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	hash  uint32
+	value ref.R[V]
+}
+
+// mergeEntries returns a new mutable node with two entries, moves the entries to it.
+func mergeEntries[K comparable, V any](a entry[K, V], b entry[K, V], shift uint) node[K, V] {
+	if shift >= maxShift {
+		return newCollisionNode(a.hash, a, b)
+	}
+
+	n := newBitmapNode[K, V]()
+	bitA := bitpos(a.hash, shift)
+	bitB := bitpos(b.hash, shift)
+
+	// Same position, push entries down
+	if bitA == bitB {
+		child := mergeEntries(a, b, shift+levelBits)
+		n.nodemap = bitA
+		n.children = append(n.children, child)
+		return n
+	}
+
+	// Different positions, store entries in order
+	n.datamap = bitA | bitB
+	if bitA < bitB {
+		n.entries = append(n.entries, a, b)
+	} else {
+		n.entries = append(n.entries, b, a)
+	}
+	return n
+}
+
+// bitpos returns a bitmap bit of a hash at a shift.
+func bitpos(hash uint32, shift uint) uint32 {
+	return 1 << ((hash >> shift) & levelMask)
+}
+
+// bitindex returns an index of a bit in a bitmap.
+func bitindex(bitmap uint32, bit uint32) int {
+	return bits.OnesCount32(bitmap & (bit - 1))
+}
@@ -0,0 +1,300 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/basecomplextech/baselibrary/collect/slices2"
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+var _ node[int, any] = (*bitmapNode[int, any])(nil)
+
+// bitmapNode stores entries and child nodes at hash positions of its level,
+// the positions are marked in the datamap and nodemap bitmaps.
+type bitmapNode[K comparable, V any] struct {
+	datamap  uint32
+	nodemap  uint32
+	entries  []entry[K, V]
+	children []node[K, V]
+
+	mut  bool
+	refs int64
+}
+
+// newBitmapNode returns a new mutable node.
+func newBitmapNode[K comparable, V any]() *bitmapNode[K, V] {
+	n := acquireBitmap[K, V]()
+	n.mut = true
+	n.refs = 1
+	return n
+}
+
+// cloneBitmapNode returns a mutable node clone.
+func cloneBitmapNode[K comparable, V any](n *bitmapNode[K, V]) *bitmapNode[K, V] {
+	n1 := acquireBitmap[K, V]()
+	n1.mut = true
+	n1.refs = 1
+
+	n1.datamap = n.datamap
+	n1.nodemap = n.nodemap
+	n1.entries = append(n1.entries, n.entries...)
+	n1.children = append(n1.children, n.children...)
+
+	// Retain entries and children
+	for _, e := range n1.entries {
+		e.value.Retain()
+	}
+	for _, child := range n1.children {
+		child.retain()
+	}
+	return n1
+}
+
+// state
+
+func (n *bitmapNode[K, V]) reset() {
+	entries := slices2.Truncate(n.entries)
+	children := slices2.Truncate(n.children)
+
+	*n = bitmapNode[K, V]{
+		entries:  entries,
+		children: children,
+	}
+}
+
+// retain/release
+
+func (n *bitmapNode[K, V]) retain() {
+	v := atomic.AddInt64(&n.refs, 1)
+	if v == 1 {
+		panic("retained already released node")
+	}
+}
+
+func (n *bitmapNode[K, V]) release() {
+	v := atomic.AddInt64(&n.refs, -1)
+	switch {
+	case v < 0:
+		panic("released already released node")
+	case v > 0:
+		return
+	}
+
+	// Release entries and children
+	for _, e := range n.entries {
+		e.value.Release()
+	}
+	for _, child := range n.children {
+		child.release()
+	}
+
+	// Release node
+	releaseBitmap[K, V](n)
+}
+
+func (n *bitmapNode[K, V]) refcount() int64 {
+	return n.refs
+}
+
+// mutate
+
+func (n *bitmapNode[K, V]) clone() node[K, V] {
+	return cloneBitmapNode(n)
+}
+
+func (n *bitmapNode[K, V]) freeze() {
+	if !n.mut {
+		return
+	}
+
+	for _, child := range n.children {
+		child.freeze()
+	}
+
+	n.mut = false
+}
+
+func (n *bitmapNode[K, V]) mutable() bool {
+	return n.mut
+}
+
+// attrs
+
+func (n *bitmapNode[K, V]) entryCount() int {
+	return len(n.entries)
+}
+
+func (n *bitmapNode[K, V]) entry(i int) *entry[K, V] {
+	return &n.entries[i]
+}
+
+func (n *bitmapNode[K, V]) childCount() int {
+	return len(n.children)
+}
+
+func (n *bitmapNode[K, V]) child(i int) node[K, V] {
+	return n.children[i]
+}
+
+// methods
+
+func (n *bitmapNode[K, V]) get(key K, hash uint32, shift uint) (v ref.R[V], ok bool) {
+	bit := bitpos(hash, shift)
+
+	switch {
+	case n.datamap&bit != 0:
+		i := bitindex(n.datamap, bit)
+		e := n.entries[i]
+		if e.key != key {
+			return
+		}
+		return e.value, true
+
+	case n.nodemap&bit != 0:
+		i := bitindex(n.nodemap, bit)
+		return n.children[i].get(key, hash, shift+levelBits)
+	}
+	return
+}
+
+func (n *bitmapNode[K, V]) put(key K, hash uint32, value ref.R[V], shift uint) bool {
+	if !n.mut {
+		panic("operation on immutable node")
+	}
+
+	bit := bitpos(hash, shift)
+	switch {
+	case n.datamap&bit != 0:
+		// Replace existing value
+		i := bitindex(n.datamap, bit)
+		e := &n.entries[i]
+		if e.key == key {
+			e.value = ref.SwapRetain(e.value, value)
+			return false
+		}
+
+		// Move existing and new entries to a child
+		value.Retain()
+		next := entry[K, V]{key: key, hash: hash, value: value}
+		child := mergeEntries(*e, next, shift+levelBits)
+
+		n.removeEntry(i, bit)
+		n.insertChild(child, bit)
+		return true
+
+	case n.nodemap&bit != 0:
+		i := bitindex(n.nodemap, bit)
+		child := n.mutateChild(i)
+		return child.put(key, hash, value, shift+levelBits)
+	}
+
+	// Insert new entry
+	value.Retain()
+	e := entry[K, V]{key: key, hash: hash, value: value}
+	n.insertEntry(e, bit)
+	return true
+}
+
+func (n *bitmapNode[K, V]) delete(key K, hash uint32, shift uint) bool {
+	if !n.mut {
+		panic("operation on immutable node")
+	}
+
+	bit := bitpos(hash, shift)
+	switch {
+	case n.datamap&bit != 0:
+		i := bitindex(n.datamap, bit)
+		e := n.entries[i]
+		if e.key != key {
+			return false
+		}
+
+		// Release and remove entry
+		e.value.Release()
+		n.removeEntry(i, bit)
+		return true
+
+	case n.nodemap&bit != 0:
+		i := bitindex(n.nodemap, bit)
+		child := n.mutateChild(i)
+		if !child.delete(key, hash, shift+levelBits) {
+			return false
+		}
+
+		// Inline child with a single entry
+		if child.childCount() == 0 && child.entryCount() == 1 {
+			e := *child.entry(0)
+			e.value.Retain()
+
+			n.removeChild(i, bit)
+			n.insertEntry(e, bit)
+			child.release()
+		}
+		return true
+	}
+	return false
+}
+
+// private
+
+// mutateChild returns a mutable child, clones and replaces the child when immutable.
+func (n *bitmapNode[K, V]) mutateChild(i int) node[K, V] {
+	child := n.children[i]
+	if child.mutable() {
+		return child
+	}
+
+	next := child.clone()
+	n.children[i] = next
+	child.release()
+	return next
+}
+
+// insertEntry inserts an entry at a bit, does not retain the value.
+func (n *bitmapNode[K, V]) insertEntry(e entry[K, V], bit uint32) {
+	i := bitindex(n.datamap, bit)
+	n.entries = slices.Insert(n.entries, i, e)
+	n.datamap |= bit
+}
+
+// removeEntry removes an entry at an index, does not release the value.
+func (n *bitmapNode[K, V]) removeEntry(i int, bit uint32) {
+	n.entries = slices.Delete(n.entries, i, i+1)
+	n.datamap &^= bit
+}
+
+// insertChild inserts a child at a bit, does not retain the child.
+func (n *bitmapNode[K, V]) insertChild(child node[K, V], bit uint32) {
+	i := bitindex(n.nodemap, bit)
+	n.children = slices.Insert(n.children, i, child)
+	n.nodemap |= bit
+}
+
+// removeChild removes a child at an index, does not release the child.
+func (n *bitmapNode[K, V]) removeChild(i int, bit uint32) {
+	n.children = slices.Delete(n.children, i, i+1)
+	n.nodemap &^= bit
+}
+
+// bitmap pool
+
+var bitmapNodePools = pools.NewPools()
+
+func acquireBitmap[K comparable, V any]() *bitmapNode[K, V] {
+	v, ok := pools.Acquire[*bitmapNode[K, V]](bitmapNodePools)
+	if ok {
+		return v
+	}
+	return &bitmapNode[K, V]{}
+}
+
+func releaseBitmap[K comparable, V any](n *bitmapNode[K, V]) {
+	n.reset()
+	pools.Release(bitmapNodePools, n)
+}
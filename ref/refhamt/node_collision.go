@@ -0,0 +1,192 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refhamt
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/basecomplextech/baselibrary/collect/slices2"
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+var _ node[int, any] = (*collisionNode[int, any])(nil)
+
+// collisionNode stores entries with equal hashes below all hash levels.
+type collisionNode[K comparable, V any] struct {
+	hash    uint32
+	entries []entry[K, V]
+
+	mut  bool
+	refs int64
+}
+
+// newCollisionNode returns a new mutable node, moves the entries to it.
+func newCollisionNode[K comparable, V any](hash uint32, entries ...entry[K, V]) *collisionNode[K, V] {
+	n := acquireCollision[K, V]()
+	n.hash = hash
+	n.entries = append(n.entries, entries...)
+	n.mut = true
+	n.refs = 1
+	return n
+}
+
+// cloneCollisionNode returns a mutable node clone.
+func cloneCollisionNode[K comparable, V any](n *collisionNode[K, V]) *collisionNode[K, V] {
+	n1 := newCollisionNode(n.hash, n.entries...)
+
+	// Retain entries
+	for _, e := range n1.entries {
+		e.value.Retain()
+	}
+	return n1
+}
+
+// state
+
+func (n *collisionNode[K, V]) reset() {
+	entries := slices2.Truncate(n.entries)
+
+	*n = collisionNode[K, V]{
+		entries: entries,
+	}
+}
+
+// retain/release
+
+func (n *collisionNode[K, V]) retain() {
+	v := atomic.AddInt64(&n.refs, 1)
+	if v == 1 {
+		panic("retained already released node")
+	}
+}
+
+func (n *collisionNode[K, V]) release() {
+	v := atomic.AddInt64(&n.refs, -1)
+	switch {
+	case v < 0:
+		panic("released already released node")
+	case v > 0:
+		return
+	}
+
+	// Release entries
+	for _, e := range n.entries {
+		e.value.Release()
+	}
+
+	// Release node
+	releaseCollision[K, V](n)
+}
+
+func (n *collisionNode[K, V]) refcount() int64 {
+	return n.refs
+}
+
+// mutate
+
+func (n *collisionNode[K, V]) clone() node[K, V] {
+	return cloneCollisionNode(n)
+}
+
+func (n *collisionNode[K, V]) freeze() {
+	n.mut = false
+}
+
+func (n *collisionNode[K, V]) mutable() bool {
+	return n.mut
+}
+
+// attrs
+
+func (n *collisionNode[K, V]) entryCount() int {
+	return len(n.entries)
+}
+
+func (n *collisionNode[K, V]) entry(i int) *entry[K, V] {
+	return &n.entries[i]
+}
+
+func (n *collisionNode[K, V]) childCount() int {
+	return 0
+}
+
+func (n *collisionNode[K, V]) child(i int) node[K, V] {
+	panic("collision node has no children")
+}
+
+// methods
+
+func (n *collisionNode[K, V]) get(key K, hash uint32, shift uint) (v ref.R[V], ok bool) {
+	i := n.indexOf(key)
+	if i < 0 {
+		return
+	}
+	return n.entries[i].value, true
+}
+
+func (n *collisionNode[K, V]) put(key K, hash uint32, value ref.R[V], shift uint) bool {
+	if !n.mut {
+		panic("operation on immutable node")
+	}
+
+	// Replace existing value
+	i := n.indexOf(key)
+	if i >= 0 {
+		e := &n.entries[i]
+		e.value = ref.SwapRetain(e.value, value)
+		return false
+	}
+
+	// Append new entry
+	value.Retain()
+	n.entries = append(n.entries, entry[K, V]{key: key, hash: hash, value: value})
+	return true
+}
+
+func (n *collisionNode[K, V]) delete(key K, hash uint32, shift uint) bool {
+	if !n.mut {
+		panic("operation on immutable node")
+	}
+
+	i := n.indexOf(key)
+	if i < 0 {
+		return false
+	}
+
+	// Release and remove entry
+	n.entries[i].value.Release()
+	n.entries = slices.Delete(n.entries, i, i+1)
+	return true
+}
+
+// private
+
+func (n *collisionNode[K, V]) indexOf(key K) int {
+	for i, e := range n.entries {
+		if e.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// collision pool
+
+var collisionNodePools = pools.NewPools()
+
+func acquireCollision[K comparable, V any]() *collisionNode[K, V] {
+	v, ok := pools.Acquire[*collisionNode[K, V]](collisionNodePools)
+	if ok {
+		return v
+	}
+	return &collisionNode[K, V]{}
+}
+
+func releaseCollision[K comparable, V any](n *collisionNode[K, V]) {
+	n.reset()
+	pools.Release(collisionNodePools, n)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"io"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func BenchmarkSnapshot_Get(b *testing.B) {
+	items := testItemsN(benchTableSize)
+	s := testSnapshot(b, SnapshotOptions{}, items...)
+	defer s.Free()
+
+	keys := make([]int, len(items))
+	for i := range keys {
+		keys[i] = i
+	}
+	rand.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		key := keys[i%len(keys)]
+		value, ok := s.Get(key)
+		if !ok {
+			b.Fatal(key)
+		}
+		value.Release()
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkSnapshot_Iterator(b *testing.B) {
+	items := testItemsN(benchTableSize)
+	s := testSnapshot(b, SnapshotOptions{}, items...)
+	defer s.Free()
+
+	it := s.Iterator()
+	defer it.Free()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		if !it.Next() {
+			it.SeekToStart()
+			continue
+		}
+		it.Key()
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkSnapshotWriter_Write(b *testing.B) {
+	items := testItemsN(benchTableSize)
+	btree := testBtree(b, items...)
+	defer btree.Free()
+	btree.Freeze()
+
+	w := NewSnapshotWriter[int, *Value](testIntCodec{}, testValueCodec{}, SnapshotOptions{})
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := w.Write(io.Discard, btree); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N*len(items)) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
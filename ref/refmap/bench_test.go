@@ -149,3 +149,22 @@ func BenchmarkTree_Iterator(b *testing.B) {
 	ops := float64(b.N) / sec
 	b.ReportMetric(ops/1000_000, "mops")
 }
+
+func BenchmarkTree_BulkLoad(b *testing.B) {
+	compare := func(a, b int) int { return a - b }
+	items := testItemsN(benchTableSize)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		btree := bulkLoad(compare, items)
+		btree.Free()
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N*len(items)) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
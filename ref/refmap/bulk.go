@@ -0,0 +1,63 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+// BulkLoad returns a new map built bottom-up from sorted items, retains the values.
+//
+// The method builds full leaves and branches, it is much faster than adding items one by one.
+// Panics if the items are not sorted or contain duplicate keys.
+func BulkLoad[K, V any](mutable bool, compare CompareFunc[K], items []Item[K, V]) Map[K, V] {
+	t := bulkLoad(compare, items)
+	if !mutable {
+		t.Freeze()
+	}
+	return t
+}
+
+// internal
+
+func bulkLoad[K, V any](compare CompareFunc[K], items []Item[K, V]) *btree[K, V] {
+	t := newBtree[K, V](compare)
+	if len(items) == 0 {
+		return t
+	}
+
+	// Check items are sorted
+	for i := 1; i < len(items); i++ {
+		if compare(items[i-1].Key, items[i].Key) >= 0 {
+			t.Free()
+			panic("refmap bulk load items are not sorted or contain duplicates")
+		}
+	}
+
+	// Build leaves
+	nodes := make([]node[K, V], 0, (len(items)+maxItems-1)/maxItems)
+	for i := 0; i < len(items); i += maxItems {
+		j := min(i+maxItems, len(items))
+		leaf := newLeafNode(items[i:j]...)
+		nodes = append(nodes, leaf)
+	}
+
+	// Build branches until a single root
+	height := 1
+	for len(nodes) > 1 {
+		next := make([]node[K, V], 0, (len(nodes)+maxItems-1)/maxItems)
+		for i := 0; i < len(nodes); i += maxItems {
+			j := min(i+maxItems, len(nodes))
+			branch := newBranchNode(nodes[i:j]...)
+			next = append(next, branch)
+		}
+
+		nodes = next
+		height++
+	}
+
+	// Replace root
+	t.root.release()
+	t.root = nodes[0]
+	t.height = height
+	t.length = int64(len(items))
+	return t
+}
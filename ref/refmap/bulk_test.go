@@ -0,0 +1,199 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBulkLoad(t *testing.T, items ...Item[int, *Value]) *btree[int, *Value] {
+	compare := func(a, b int) int { return a - b }
+	return bulkLoad(compare, items)
+}
+
+// testCheckCounts checks that node counts match their subtrees.
+func testCheckCounts(t *testing.T, btree *btree[int, *Value]) {
+	walk(btree.root, func(n node[int, *Value]) {
+		branch, ok := n.(*branchNode[int, *Value])
+		if !ok {
+			return
+		}
+
+		sum := int64(0)
+		for _, item := range branch.items {
+			sum += item.node.count()
+		}
+		require.Equal(t, sum, branch.count())
+	})
+
+	require.Equal(t, btree.length, btree.root.count())
+}
+
+// BulkLoad
+
+func TestBulkLoad__should_build_map_from_sorted_items(t *testing.T) {
+	for _, n := range []int{0, 1, maxItems, maxItems + 1, 1000, 10_000} {
+		items := testItemsN(n)
+		btree := testBulkLoad(t, items...)
+
+		assert.Equal(t, int64(n), btree.Length())
+		assert.Equal(t, items, btree.items())
+		testCheckCounts(t, btree)
+
+		for _, item := range items {
+			require.Equal(t, int64(2), item.Value.Refcount())
+		}
+
+		btree.Free()
+		for _, item := range items {
+			require.Equal(t, int64(1), item.Value.Refcount())
+		}
+	}
+}
+
+func TestBulkLoad__should_build_full_leaves(t *testing.T) {
+	items := testItemsN(maxItems * maxItems)
+	btree := testBulkLoad(t, items...)
+	defer btree.Free()
+
+	assert.Equal(t, 2, btree.height)
+
+	root := btree.root.(*branchNode[int, *Value])
+	for _, item := range root.items {
+		assert.Equal(t, maxItems, item.node.length())
+	}
+}
+
+func TestBulkLoad__should_panic_on_unsorted_items(t *testing.T) {
+	items := []Item[int, *Value]{testItem(2), testItem(1)}
+
+	assert.Panics(t, func() {
+		testBulkLoad(t, items...)
+	})
+}
+
+func TestBulkLoad__should_support_modifications(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	btree := testBulkLoad(t, testItemsN(1000)...)
+	defer btree.Free()
+
+	model := &testModel{}
+	for i := 0; i < 1000; i++ {
+		model.set(i)
+	}
+
+	for i := 0; i < 5000; i++ {
+		key := random.Intn(2000)
+		if random.Intn(2) == 0 {
+			btree.Delete(key)
+			model.delete(key)
+		} else {
+			btree.SetNoRetain(key, testValue(key))
+			model.set(key)
+		}
+	}
+
+	assert.Equal(t, model.keys, btree.Keys())
+	testCheckCounts(t, btree)
+}
+
+// Rank/At/SeekIndex
+
+func TestMap_Rank_At__should_match_model_under_random_operations(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	model := &testModel{}
+
+	btree := testBtree(t)
+	for i := 0; i < 3000; i++ {
+		key := random.Intn(1000)
+		if random.Intn(3) == 0 {
+			btree.Delete(key)
+			model.delete(key)
+		} else {
+			btree.SetNoRetain(key, testValue(key))
+			model.set(key)
+		}
+
+		// Clone sometimes
+		if i%100 == 0 {
+			btree.Freeze()
+			prev := btree
+			btree = testUnwrap(prev.Clone())
+			prev.Free()
+		}
+
+		if i%50 != 0 {
+			continue
+		}
+
+		require.Equal(t, int64(len(model.keys)), btree.Length())
+		testCheckCounts(t, btree)
+
+		for k := -1; k <= 1001; k += 7 {
+			rank := 0
+			for rank < len(model.keys) && model.keys[rank] < k {
+				rank++
+			}
+			require.Equal(t, int64(rank), btree.Rank(k), "key=%d", k)
+		}
+
+		for index, key := range model.keys {
+			key1, value, ok := btree.At(int64(index))
+			require.True(t, ok)
+			require.Equal(t, key, key1)
+			require.Equal(t, key, value.Unwrap().val)
+		}
+
+		_, _, ok := btree.At(int64(len(model.keys)))
+		require.False(t, ok)
+	}
+}
+
+func TestIterator_SeekIndex__should_position_before_item_at_index(t *testing.T) {
+	items := testItemsN(1000)
+	btree := testBulkLoad(t, items...)
+	defer btree.Free()
+
+	it := btree.Iterator()
+	defer it.Free()
+
+	for i := 0; i < len(items); i += 13 {
+		require.True(t, it.SeekIndex(int64(i)))
+		require.True(t, it.Next())
+		require.Equal(t, i, it.Key())
+
+		require.True(t, it.SeekIndex(int64(i)))
+		if i > 0 {
+			require.True(t, it.Previous())
+			require.Equal(t, i-1, it.Key())
+		}
+	}
+
+	require.False(t, it.SeekIndex(1000))
+	require.False(t, it.Next())
+}
+
+func TestIterator_SeekIndex__should_clamp_to_range(t *testing.T) {
+	btree := testBulkLoad(t, testItemsN(100)...)
+	defer btree.Free()
+
+	it := btree.Range(10, 20, RangeIncludeFrom)
+	defer it.Free()
+
+	it.SeekIndex(5)
+	require.True(t, it.Next())
+	assert.Equal(t, 10, it.Key())
+
+	it.SeekIndex(15)
+	require.True(t, it.Next())
+	assert.Equal(t, 15, it.Key())
+
+	it.SeekIndex(50)
+	require.False(t, it.Next())
+}
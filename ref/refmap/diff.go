@@ -0,0 +1,271 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// DiffIterator iterates over changes between two maps in key order.
+//
+// Usage:
+//
+//	it := refmap.Diff(old, new)
+//	defer it.Free()
+//
+//	for it.Next() {
+//		change := it.Change()
+//		switch change.Kind {
+//		case refmap.ChangeAdded:
+//		case refmap.ChangeRemoved:
+//		case refmap.ChangeUpdated:
+//		}
+//	}
+type DiffIterator[K, V any] interface {
+	// Next moves to the next change.
+	Next() bool
+
+	// Change returns the current change, the values are valid until the next iteration.
+	Change() Change[K, V]
+
+	// Internal
+
+	// Free frees the iterator.
+	Free()
+}
+
+// Change is a key change between two maps.
+type Change[K, V any] struct {
+	Kind ChangeKind
+	Key  K
+	Old  ref.R[V] // nil when added
+	New  ref.R[V] // nil when removed
+}
+
+// ChangeKind is a kind of key change.
+type ChangeKind int
+
+const (
+	ChangeUndefined ChangeKind = iota
+	ChangeAdded
+	ChangeRemoved
+	ChangeUpdated
+)
+
+// Diff returns an iterator over changes between two maps, the iterator does not retain the values.
+//
+// The iterator skips subtrees shared by the maps, so the cost of diffing a map and its clone
+// scales with the number of changes, not the size of the maps. Values are compared by reference
+// identity. The maps must use the same compare function and must not be modified during iteration.
+func Diff[K, V any](old Map[K, V], new Map[K, V]) DiffIterator[K, V] {
+	t0 := old.(*btree[K, V])
+	t1 := new.(*btree[K, V])
+	return newDiffIterator(t0, t1)
+}
+
+// String returns the change kind name.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeUpdated:
+		return "updated"
+	}
+	return "undefined"
+}
+
+// internal
+
+var _ DiffIterator[any, any] = (*diffIterator[any, any])(nil)
+
+type diffIterator[K, V any] struct {
+	compare CompareFunc[K]
+	old     diffWalker[K, V]
+	new     diffWalker[K, V]
+
+	change Change[K, V]
+}
+
+func newDiffIterator[K, V any](old *btree[K, V], new *btree[K, V]) *diffIterator[K, V] {
+	it := &diffIterator[K, V]{compare: new.compare}
+	it.old.init(old)
+	it.new.init(new)
+	return it
+}
+
+// Next moves to the next change.
+func (it *diffIterator[K, V]) Next() bool {
+	it.change = Change[K, V]{}
+
+	for {
+		a, aok := it.old.peek()
+		b, bok := it.new.peek()
+
+		switch {
+		case !aok && !bok:
+			return false
+
+		case !aok:
+			if b.node != nil {
+				it.new.expand()
+				continue
+			}
+
+			it.new.pop()
+			it.change = Change[K, V]{Kind: ChangeAdded, Key: b.key, New: b.value}
+			return true
+
+		case !bok:
+			if a.node != nil {
+				it.old.expand()
+				continue
+			}
+
+			it.old.pop()
+			it.change = Change[K, V]{Kind: ChangeRemoved, Key: a.key, Old: a.value}
+			return true
+		}
+
+		// Skip shared subtrees,
+		// expand the higher node or both
+		if a.node != nil && b.node != nil {
+			switch {
+			case a.node == b.node:
+				it.old.pop()
+				it.new.pop()
+			case a.height > b.height:
+				it.old.expand()
+			case a.height < b.height:
+				it.new.expand()
+			default:
+				it.old.expand()
+				it.new.expand()
+			}
+			continue
+		}
+
+		// Return an item before a subtree,
+		// otherwise expand the subtree
+		if a.node != nil {
+			if a.node.length() > 0 && it.compare(b.key, a.node.minKey()) < 0 {
+				it.new.pop()
+				it.change = Change[K, V]{Kind: ChangeAdded, Key: b.key, New: b.value}
+				return true
+			}
+
+			it.old.expand()
+			continue
+		}
+		if b.node != nil {
+			if b.node.length() > 0 && it.compare(a.key, b.node.minKey()) < 0 {
+				it.old.pop()
+				it.change = Change[K, V]{Kind: ChangeRemoved, Key: a.key, Old: a.value}
+				return true
+			}
+
+			it.new.expand()
+			continue
+		}
+
+		// Compare items
+		cmp := it.compare(a.key, b.key)
+		switch {
+		case cmp < 0:
+			it.old.pop()
+			it.change = Change[K, V]{Kind: ChangeRemoved, Key: a.key, Old: a.value}
+			return true
+
+		case cmp > 0:
+			it.new.pop()
+			it.change = Change[K, V]{Kind: ChangeAdded, Key: b.key, New: b.value}
+			return true
+		}
+
+		it.old.pop()
+		it.new.pop()
+		if a.value == b.value {
+			continue
+		}
+
+		it.change = Change[K, V]{Kind: ChangeUpdated, Key: a.key, Old: a.value, New: b.value}
+		return true
+	}
+}
+
+// Change returns the current change, the values are valid until the next iteration.
+func (it *diffIterator[K, V]) Change() Change[K, V] {
+	return it.change
+}
+
+// Internal
+
+// Free frees the iterator.
+func (it *diffIterator[K, V]) Free() {
+	*it = diffIterator[K, V]{}
+}
+
+// walker
+
+// diffWalker walks a btree in key order, and returns either whole subtrees or leaf items.
+type diffWalker[K, V any] struct {
+	stack []diffElem[K, V] // next element is on top
+}
+
+// diffElem is either a subtree node or a leaf item.
+type diffElem[K, V any] struct {
+	node   node[K, V] // nil for items
+	height int        // node height, leaf nodes have height 1
+
+	key   K
+	value ref.R[V]
+}
+
+func (w *diffWalker[K, V]) init(t *btree[K, V]) {
+	elem := diffElem[K, V]{
+		node:   t.root,
+		height: t.height,
+	}
+	w.stack = append(w.stack, elem)
+}
+
+func (w *diffWalker[K, V]) peek() (diffElem[K, V], bool) {
+	if len(w.stack) == 0 {
+		return diffElem[K, V]{}, false
+	}
+	return w.stack[len(w.stack)-1], true
+}
+
+func (w *diffWalker[K, V]) pop() {
+	w.stack = w.stack[:len(w.stack)-1]
+}
+
+// expand replaces the top node with its children or items in reverse order.
+func (w *diffWalker[K, V]) expand() {
+	elem := w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+
+	switch n := elem.node.(type) {
+	case *branchNode[K, V]:
+		for i := len(n.items) - 1; i >= 0; i-- {
+			child := diffElem[K, V]{
+				node:   n.items[i].node,
+				height: elem.height - 1,
+			}
+			w.stack = append(w.stack, child)
+		}
+
+	case *leafNode[K, V]:
+		for i := len(n.items) - 1; i >= 0; i-- {
+			item := n.items[i]
+			child := diffElem[K, V]{
+				key:   item.key,
+				value: item.value,
+			}
+			w.stack = append(w.stack, child)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/ref"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDiff(t *testing.T, old, new Map[int, *Value]) []Change[int, *Value] {
+	it := Diff(old, new)
+	defer it.Free()
+
+	var changes []Change[int, *Value]
+	for it.Next() {
+		changes = append(changes, it.Change())
+	}
+	return changes
+}
+
+// testDiffCompares returns changes and the number of key comparisons.
+func testDiffCompares(old, new *btree[int, *Value]) ([]Change[int, *Value], int) {
+	it := newDiffIterator(old, new)
+	defer it.Free()
+
+	var compares int
+	compare := it.compare
+	it.compare = func(a, b int) int {
+		compares++
+		return compare(a, b)
+	}
+
+	var changes []Change[int, *Value]
+	for it.Next() {
+		changes = append(changes, it.Change())
+	}
+	return changes, compares
+}
+
+// testDiffModel returns expected changes by comparing all items.
+func testDiffModel(old, new Map[int, *Value]) []Change[int, *Value] {
+	var changes []Change[int, *Value]
+
+	keys := append(old.Keys(), new.Keys()...)
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	for _, key := range keys {
+		v0, ok0 := old.Get(key)
+		v1, ok1 := new.Get(key)
+
+		switch {
+		case !ok0:
+			changes = append(changes, Change[int, *Value]{Kind: ChangeAdded, Key: key, New: v1})
+		case !ok1:
+			changes = append(changes, Change[int, *Value]{Kind: ChangeRemoved, Key: key, Old: v0})
+		case v0 != v1:
+			changes = append(changes, Change[int, *Value]{Kind: ChangeUpdated, Key: key, Old: v0, New: v1})
+		}
+	}
+	return changes
+}
+
+// Diff
+
+func TestDiff__should_return_added_removed_and_updated_keys(t *testing.T) {
+	old := testBtree(t, testItemsN(100)...)
+	old.Freeze()
+
+	new := old.Clone()
+	new.Delete(10)
+	new.SetNoRetain(20, testValue(200))
+	new.SetNoRetain(1000, testValue(1000))
+
+	changes := testDiff(t, old, new)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, ChangeRemoved, changes[0].Kind)
+	assert.Equal(t, 10, changes[0].Key)
+	assert.Nil(t, changes[0].New)
+
+	assert.Equal(t, ChangeUpdated, changes[1].Kind)
+	assert.Equal(t, 20, changes[1].Key)
+	assert.Equal(t, 20, changes[1].Old.Unwrap().val)
+	assert.Equal(t, 200, changes[1].New.Unwrap().val)
+
+	assert.Equal(t, ChangeAdded, changes[2].Kind)
+	assert.Equal(t, 1000, changes[2].Key)
+	assert.Nil(t, changes[2].Old)
+}
+
+func TestDiff__should_return_all_items_for_empty_map(t *testing.T) {
+	old := testBtree(t)
+	new := testBtree(t, testItemsN(100)...)
+
+	changes := testDiff(t, old, new)
+	require.Len(t, changes, 100)
+	for i, change := range changes {
+		assert.Equal(t, ChangeAdded, change.Kind)
+		assert.Equal(t, i, change.Key)
+	}
+
+	changes = testDiff(t, new, old)
+	require.Len(t, changes, 100)
+	assert.Equal(t, ChangeRemoved, changes[0].Kind)
+}
+
+func TestDiff__should_skip_shared_subtrees(t *testing.T) {
+	n := 100_000
+	old := testBtree(t, testItemsN(n)...)
+	old.Freeze()
+
+	new := testUnwrap(old.Clone())
+	new.SetNoRetain(n/2, testValue(-1))
+
+	changes, compares := testDiffCompares(old, new)
+	require.Len(t, changes, 1)
+	assert.Equal(t, n/2, changes[0].Key)
+
+	// Only changed paths must be compared
+	max := old.height * 2 * maxItems * 2
+	assert.Less(t, compares, max)
+}
+
+func TestDiff__should_skip_shared_subtrees_when_nodes_split(t *testing.T) {
+	n := 100_000
+	old := testBtree(t)
+	for i := 0; i < n; i++ {
+		old.SetNoRetain(i*10, testValue(i*10))
+	}
+	old.Freeze()
+
+	// Insert keys between existing ones to split leaves
+	new := testUnwrap(old.Clone())
+	for i := 0; i < 10*maxItems; i++ {
+		key := (n/2+i)*10 + 5
+		new.SetNoRetain(key, testValue(key))
+	}
+
+	changes, compares := testDiffCompares(old, new)
+	require.Equal(t, testDiffModel(old, new), changes)
+	require.Len(t, changes, 10*maxItems)
+
+	max := (len(changes) + old.height*2) * maxItems * 2
+	assert.Less(t, compares, max)
+}
+
+func TestDiff__should_skip_shared_subtrees_when_nodes_deleted(t *testing.T) {
+	n := 100_000
+	old := testBtree(t, testItemsN(n)...)
+	old.Freeze()
+
+	// Delete a range of keys to delete whole leaves
+	new := testUnwrap(old.Clone())
+	for i := 0; i < 10*maxItems; i++ {
+		new.Delete(n/2 + i)
+	}
+
+	changes, compares := testDiffCompares(old, new)
+	require.Equal(t, testDiffModel(old, new), changes)
+	require.Len(t, changes, 10*maxItems)
+
+	max := (len(changes) + old.height*2) * maxItems * 2
+	assert.Less(t, compares, max)
+}
+
+func TestDiff__should_return_changes_when_height_grows(t *testing.T) {
+	small := testBtree(t, testItemsN(maxItems/2)...)
+	small.Freeze()
+
+	// Grow
+	large := testUnwrap(small.Clone())
+	for i := maxItems / 2; i < 4*maxItems*maxItems; i++ {
+		large.SetNoRetain(i, testValue(i))
+	}
+	large.Freeze()
+	require.Greater(t, large.height, small.height)
+
+	changes := testDiff(t, small, large)
+	require.Equal(t, testDiffModel(small, large), changes)
+
+	// Delete all but the first key, the height does not shrink
+	shrunk := testUnwrap(large.Clone())
+	for i := 1; i < 4*maxItems*maxItems; i++ {
+		shrunk.Delete(i)
+	}
+
+	changes = testDiff(t, large, shrunk)
+	require.Equal(t, testDiffModel(large, shrunk), changes)
+}
+
+func TestDiff__should_match_model_under_random_operations(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+
+	m := testBtree(t)
+	for i := 0; i < 50; i++ {
+		// Snapshot
+		m.Freeze()
+		old := m
+		m = testUnwrap(old.Clone())
+
+		// Modify
+		ops := random.Intn(200)
+		for j := 0; j < ops; j++ {
+			key := random.Intn(2000)
+			if random.Intn(3) == 0 {
+				m.Delete(key)
+			} else {
+				m.SetNoRetain(key, testValue(key))
+			}
+		}
+
+		expected := testDiffModel(old, m)
+		changes := testDiff(t, old, m)
+		require.Equal(t, expected, changes)
+	}
+}
+
+// Merge
+
+func TestMerge__should_apply_non_conflicting_changes(t *testing.T) {
+	base := testBtree(t, testItemsN(100)...)
+	base.Freeze()
+
+	ours := base.Clone()
+	ours.Delete(1)
+	ours.SetNoRetain(2, testValue(20))
+	ours.Freeze()
+
+	theirs := base.Clone()
+	theirs.Delete(3)
+	theirs.SetNoRetain(4, testValue(40))
+	theirs.SetNoRetain(200, testValue(200))
+	theirs.Freeze()
+
+	merged := Merge(base, ours, theirs, func(key int, base, ours, theirs ref.R[*Value]) (ref.R[*Value], bool) {
+		t.Fatal("unexpected conflict")
+		return nil, false
+	})
+
+	assert.False(t, merged.Contains(1))
+	assert.False(t, merged.Contains(3))
+
+	v, _ := merged.Get(2)
+	assert.Equal(t, 20, v.Unwrap().val)
+	v, _ = merged.Get(4)
+	assert.Equal(t, 40, v.Unwrap().val)
+	v, _ = merged.Get(200)
+	assert.Equal(t, 200, v.Unwrap().val)
+	assert.Equal(t, int64(99), merged.Length())
+}
+
+func TestMerge__should_not_conflict_on_same_changes(t *testing.T) {
+	base := testBtree(t, testItemsN(10)...)
+	base.Freeze()
+
+	value := testValue(50)
+	ours := base.Clone()
+	ours.Delete(1)
+	ours.SetRetain(5, value)
+	ours.Freeze()
+
+	theirs := base.Clone()
+	theirs.Delete(1)
+	theirs.SetRetain(5, value)
+	theirs.Freeze()
+
+	merged := Merge(base, ours, theirs, func(key int, base, ours, theirs ref.R[*Value]) (ref.R[*Value], bool) {
+		t.Fatal("unexpected conflict")
+		return nil, false
+	})
+
+	assert.False(t, merged.Contains(1))
+	v, _ := merged.Get(5)
+	assert.Equal(t, value, v)
+}
+
+func TestMerge__should_resolve_conflicts_with_callback(t *testing.T) {
+	base := testBtree(t, testItemsN(10)...)
+	base.Freeze()
+
+	ours := base.Clone()
+	ours.SetNoRetain(1, testValue(10))
+	ours.Delete(2)
+	ours.Freeze()
+
+	theirs := base.Clone()
+	theirs.SetNoRetain(1, testValue(11))
+	theirs.SetNoRetain(2, testValue(22))
+	theirs.Freeze()
+
+	var conflicts []int
+	merged := Merge(base, ours, theirs, func(key int, b, o, th ref.R[*Value]) (ref.R[*Value], bool) {
+		conflicts = append(conflicts, key)
+
+		switch key {
+		case 1:
+			assert.Equal(t, 1, b.Unwrap().val)
+			assert.Equal(t, 10, o.Unwrap().val)
+			assert.Equal(t, 11, th.Unwrap().val)
+			return th, true
+		case 2:
+			assert.Nil(t, o)
+			return nil, false
+		}
+		return nil, false
+	})
+
+	assert.Equal(t, []int{1, 2}, conflicts)
+
+	v, _ := merged.Get(1)
+	assert.Equal(t, 11, v.Unwrap().val)
+	assert.False(t, merged.Contains(2))
+}
@@ -53,6 +53,17 @@ type Iterator[K any, V any] interface {
 	// SeekBefore positions the iterator before an item with key >= key.
 	SeekBefore(key K) bool
 
+	// SeekAfter positions the iterator after an item with key <= key,
+	// i.e. before an item with key > key.
+	SeekAfter(key K) bool
+
+	// SeekTo positions the iterator at an item with key >= key, and returns true if found.
+	SeekTo(key K) bool
+
+	// SeekIndex positions the iterator before an item with an index in key order,
+	// and returns true if found.
+	SeekIndex(index int64) bool
+
 	// Internal
 
 	// Free frees the iterator, implements the ref.Free interface.
@@ -85,6 +96,9 @@ type iterState[K, V any] struct {
 	mod   int              // track concurrent modifications
 	pos   position         // current iterator position
 	stack []iterElem[K, V] // the last element is always a leaf node
+
+	lower rangeBound[K] // optional range start
+	upper rangeBound[K] // optional range end
 }
 
 // iterElem combines a node and an iteration index of the node elements.
@@ -167,6 +181,196 @@ func (it *iterator[K, V]) Next() bool {
 		panic("refmap concurrent modification error")
 	}
 
+	// Stopped at range end
+	if it.st.Code == status.CodeEnd && it.pos == positionBefore {
+		return false
+	}
+
+	if !it.next() {
+		return false
+	}
+
+	// Stop before an item beyond range end,
+	// so that previous returns the last item in range.
+	if it.aboveUpper(it.Key()) {
+		it.pos = positionBefore
+		it.st = status.End
+		return false
+	}
+	return true
+}
+
+// Previous moves to the previous item.
+func (it *iterator[K, V]) Previous() bool {
+	switch it.st.Code {
+	case status.CodeOK,
+		status.CodeEnd,
+		status.CodeNone:
+	default:
+		return false
+	}
+
+	if it.mod != it.tree.mod {
+		it.st = status.Errorf("refmap concurrent modification error")
+		panic("refmap concurrent modification error")
+	}
+
+	// Stopped at range start
+	if it.st.Code == status.CodeEnd && it.pos == positionItem {
+		return false
+	}
+
+	if !it.previous() {
+		return false
+	}
+
+	// Stop at an item before range start,
+	// so that next returns the first item in range.
+	if it.belowLower(it.Key()) {
+		it.pos = positionItem
+		it.st = status.End
+		return false
+	}
+	return true
+}
+
+// Seeking
+
+// SeekToStart positions the iterator at the start.
+func (it *iterator[K, V]) SeekToStart() bool {
+	switch it.st.Code {
+	case status.CodeOK,
+		status.CodeEnd,
+		status.CodeNone:
+	default:
+		return false
+	}
+
+	if it.lower.set {
+		it.seek(it.lower.key, !it.lower.inclusive)
+		it.st = status.None
+		return true
+	}
+
+	it.st = status.None
+	it.mod = it.tree.mod
+	it.pos = positionStart
+	it.stack = it.stack[:0]
+	return true
+}
+
+// SeekToEnd positions the iterator at the end.
+func (it *iterator[K, V]) SeekToEnd() bool {
+	switch it.st.Code {
+	case status.CodeOK,
+		status.CodeEnd,
+		status.CodeNone:
+	default:
+		return false
+	}
+
+	if it.upper.set {
+		it.seek(it.upper.key, it.upper.inclusive)
+		it.st = status.None
+		return true
+	}
+
+	it.st = status.None
+	it.mod = it.tree.mod
+	it.pos = positionEnd
+	it.stack = it.stack[:0]
+	return true
+}
+
+// SeekBefore positions the iterator before an item with key >= key, and returns ok/end/error.
+func (it *iterator[K, V]) SeekBefore(key K) bool {
+	switch it.st.Code {
+	case status.CodeOK,
+		status.CodeEnd,
+		status.CodeNone:
+	default:
+		return false
+	}
+
+	switch {
+	case it.belowLower(key):
+		return it.SeekToStart()
+	case it.aboveUpper(key):
+		return it.SeekToEnd()
+	}
+	return it.seek(key, false)
+}
+
+// SeekAfter positions the iterator after an item with key <= key,
+// i.e. before an item with key > key, and returns ok/end/error.
+func (it *iterator[K, V]) SeekAfter(key K) bool {
+	switch it.st.Code {
+	case status.CodeOK,
+		status.CodeEnd,
+		status.CodeNone:
+	default:
+		return false
+	}
+
+	switch {
+	case it.belowLower(key):
+		return it.SeekToStart()
+	case it.aboveUpper(key):
+		return it.SeekToEnd()
+	}
+	return it.seek(key, true)
+}
+
+// SeekTo positions the iterator at an item with key >= key, and returns true if found.
+func (it *iterator[K, V]) SeekTo(key K) bool {
+	if !it.SeekBefore(key) {
+		return false
+	}
+	return it.Next()
+}
+
+// SeekIndex positions the iterator before an item with an index in key order,
+// and returns true if found.
+func (it *iterator[K, V]) SeekIndex(index int64) bool {
+	switch it.st.Code {
+	case status.CodeOK,
+		status.CodeEnd,
+		status.CodeNone:
+	default:
+		return false
+	}
+
+	if !it.seekIndex(index) {
+		return false
+	}
+
+	// Clamp to range
+	elem := it.stack[len(it.stack)-1]
+	leaf := elem.node.(*leafNode[K, V])
+	key := leaf.items[elem.index].key
+
+	switch {
+	case it.belowLower(key):
+		return it.SeekToStart()
+	case it.aboveUpper(key):
+		return it.SeekToEnd()
+	}
+	return true
+}
+
+// Internal
+
+// Free frees the iterator.
+func (it *iterator[K, V]) Free() {
+	state := it.iterState
+	it.iterState = nil
+	releaseIterState(state)
+}
+
+// private
+
+// next moves to the next item ignoring range bounds.
+func (it *iterator[K, V]) next() bool {
 	switch it.pos {
 	case positionBefore:
 		// Stack already points to the next item
@@ -240,21 +444,8 @@ func (it *iterator[K, V]) Next() bool {
 	return false
 }
 
-// Previous moves to the previous item.
-func (it *iterator[K, V]) Previous() bool {
-	switch it.st.Code {
-	case status.CodeOK,
-		status.CodeEnd,
-		status.CodeNone:
-	default:
-		return false
-	}
-
-	if it.mod != it.tree.mod {
-		it.st = status.Errorf("refmap concurrent modification error")
-		panic("refmap concurrent modification error")
-	}
-
+// previous moves to the previous item ignoring range bounds.
+func (it *iterator[K, V]) previous() bool {
 	switch it.pos {
 	case positionStart:
 		// Cannot proceed
@@ -320,111 +511,120 @@ func (it *iterator[K, V]) Previous() bool {
 	return false
 }
 
-// Seeking
-
-// SeekToStart positions the iterator at the start.
-func (it *iterator[K, V]) SeekToStart() bool {
-	switch it.st.Code {
-	case status.CodeOK,
-		status.CodeEnd,
-		status.CodeNone:
-	default:
-		return false
-	}
-
-	it.st = status.None
-	it.mod = it.tree.mod
-	it.pos = positionStart
-	it.stack = it.stack[:0]
-	return true
-}
-
-// SeekToEnd positions the iterator at the end.
-func (it *iterator[K, V]) SeekToEnd() bool {
-	switch it.st.Code {
-	case status.CodeOK,
-		status.CodeEnd,
-		status.CodeNone:
-	default:
-		return false
-	}
-
+// seek positions the iterator before the first item with key >= key,
+// or key > key when after is true.
+func (it *iterator[K, V]) seek(key K, after bool) bool {
 	it.st = status.None
 	it.mod = it.tree.mod
-	it.pos = positionEnd
-	it.stack = it.stack[:0]
-	return true
-}
-
-// SeekBefore positions the iterator before an item with key >= key, and returns ok/end/error.
-func (it *iterator[K, V]) SeekBefore(key K) bool {
-	switch it.st.Code {
-	case status.CodeOK,
-		status.CodeEnd,
-		status.CodeNone:
-	default:
-		return false
-	}
-
-	it.st = status.None
 	it.stack = it.stack[:0]
 
 	// Recursively push nodes onto the stack
 	// And position them at elements >= key
 	node := it.tree.root
 	for node != nil {
-		index := node.indexOf(key, it.tree.compare)
-		elem := iterElem[K, V]{
-			node:  node,
-			index: index,
-		}
-
 		// Push element onto the stack
 		// If this is a branch node
 		branch, ok := node.(*branchNode[K, V])
 		if ok {
+			index := branch.indexOf(key, it.tree.compare)
+			elem := iterElem[K, V]{
+				node:  node,
+				index: index,
+			}
+
 			node = branch.child(index)
 			it.stack = append(it.stack, elem)
 			continue
 		}
 
-		// Check if a leaf node contains a key >= given key,
-		// Otherwise clear the stack to end iteration
+		// Check if a leaf node contains a matching key
 		leaf := node.(*leafNode[K, V])
-		if index >= len(leaf.items) {
-			it.stack = it.stack[:0]
+		if len(leaf.items) == 0 {
 			break
 		}
 
+		index := leaf.search(key, after, it.tree.compare)
+		if index < len(leaf.items) {
+			elem := iterElem[K, V]{
+				node:  node,
+				index: index,
+			}
+
+			it.stack = append(it.stack, elem)
+			it.pos = positionBefore
+			return true
+		}
+
+		// Otherwise, the key is greater than all leaf keys,
+		// but less than the min key of the next leaf, move to it.
+		elem := iterElem[K, V]{
+			node:  node,
+			index: len(leaf.items) - 1,
+		}
 		it.stack = append(it.stack, elem)
+		it.pos = positionItem
+
+		if it.next() {
+			it.st = status.None
+			it.pos = positionBefore
+			return true
+		}
 		break
 	}
 
-	if len(it.stack) == 0 {
+	it.st = status.End
+	it.pos = positionEnd
+	it.stack = it.stack[:0]
+	return false
+}
+
+// seekIndex positions the iterator before an item with an index ignoring range bounds.
+func (it *iterator[K, V]) seekIndex(index int64) bool {
+	it.st = status.None
+	it.mod = it.tree.mod
+	it.stack = it.stack[:0]
+
+	if index < 0 || index >= it.tree.root.count() {
 		it.st = status.End
-		it.mod = it.tree.mod
 		it.pos = positionEnd
 		return false
 	}
 
-	it.st = status.None
-	it.mod = it.tree.mod
-	it.pos = positionBefore
-	return true
+	// Recursively push nodes onto the stack
+	// And position them at the index
+	node := it.tree.root
+	for {
+		branch, ok := node.(*branchNode[K, V])
+		if !ok {
+			break
+		}
 
-}
+		i := 0
+		for ; i < len(branch.items)-1; i++ {
+			count := branch.items[i].node.count()
+			if index < count {
+				break
+			}
+			index -= count
+		}
 
-// Internal
+		elem := iterElem[K, V]{
+			node:  node,
+			index: i,
+		}
+		it.stack = append(it.stack, elem)
+		node = branch.child(i)
+	}
 
-// Free frees the iterator.
-func (it *iterator[K, V]) Free() {
-	state := it.iterState
-	it.iterState = nil
-	releaseIterState(state)
+	elem := iterElem[K, V]{
+		node:  node,
+		index: int(index),
+	}
+	it.stack = append(it.stack, elem)
+	it.pos = positionBefore
+	return true
 }
 
-// private
-
 // pushStart recursively pushes nodes onto the stack
 // and positions them at start elements.
 func (it *iterator[K, V]) pushStart(node node[K, V]) {
@@ -0,0 +1,148 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"iter"
+
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// RangeFlags specify which range bounds are inclusive.
+type RangeFlags int
+
+const (
+	// RangeExclusive excludes both bounds, i.e. (from, to).
+	RangeExclusive RangeFlags = 0
+
+	// RangeIncludeFrom includes the start bound, i.e. [from, to).
+	RangeIncludeFrom RangeFlags = 1 << 0
+
+	// RangeIncludeTo includes the end bound, i.e. (from, to].
+	RangeIncludeTo RangeFlags = 1 << 1
+
+	// RangeInclusive includes both bounds, i.e. [from, to].
+	RangeInclusive = RangeIncludeFrom | RangeIncludeTo
+)
+
+// PrefixRange returns an iterator over keys with a prefix, the iterator does not retain
+// the values.
+//
+// The map must compare keys lexicographically by bytes, i.e. as [bytes.Compare]
+// or [strings.Compare].
+func PrefixRange[K ~string | ~[]byte, V any](m Map[K, V], prefix K) Iterator[K, V] {
+	t := m.(*btree[K, V])
+	it := newIterator(t)
+	it.lower = rangeBound[K]{key: prefix, set: true, inclusive: true}
+
+	end, ok := prefixEnd(prefix)
+	if ok {
+		it.upper = rangeBound[K]{key: end, set: true}
+	}
+
+	it.SeekToStart()
+	return it
+}
+
+// Prefix returns a sequence of items with a key prefix in ascending order,
+// does not retain the values, see [PrefixRange].
+func Prefix[K ~string | ~[]byte, V any](m Map[K, V], prefix K) iter.Seq2[K, ref.R[V]] {
+	return func(yield func(K, ref.R[V]) bool) {
+		it := PrefixRange(m, prefix)
+		defer it.Free()
+
+		for it.Next() {
+			if !yield(it.Key(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// internal
+
+// rangeBound is an optional range bound.
+type rangeBound[K any] struct {
+	key       K
+	set       bool
+	inclusive bool
+}
+
+func newRangeIterator[K, V any](t *btree[K, V], from K, to K, flags RangeFlags) *iterator[K, V] {
+	it := newIterator(t)
+	it.lower = rangeBound[K]{
+		key:       from,
+		set:       true,
+		inclusive: flags&RangeIncludeFrom != 0,
+	}
+	it.upper = rangeBound[K]{
+		key:       to,
+		set:       true,
+		inclusive: flags&RangeIncludeTo != 0,
+	}
+
+	it.SeekToStart()
+	return it
+}
+
+// belowLower returns true if a key is before the range start.
+func (it *iterator[K, V]) belowLower(key K) bool {
+	if !it.lower.set {
+		return false
+	}
+
+	cmp := it.tree.compare(key, it.lower.key)
+	return cmp < 0 || (cmp == 0 && !it.lower.inclusive)
+}
+
+// aboveUpper returns true if a key is after the range end.
+func (it *iterator[K, V]) aboveUpper(key K) bool {
+	if !it.upper.set {
+		return false
+	}
+
+	cmp := it.tree.compare(key, it.upper.key)
+	return cmp > 0 || (cmp == 0 && !it.upper.inclusive)
+}
+
+// prefixEnd returns the first key after all keys with a prefix, or false if there is none,
+// i.e. when the prefix is empty or consists of 0xff bytes.
+func prefixEnd[K ~string | ~[]byte](prefix K) (K, bool) {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] == 0xff {
+			continue
+		}
+
+		end = append([]byte(nil), end[:i+1]...)
+		end[i]++
+		return K(end), true
+	}
+
+	var zero K
+	return zero, false
+}
+
+// iterateForward yields iterator items from start to end.
+func iterateForward[K, V any](it *iterator[K, V], yield func(K, ref.R[V]) bool) {
+	it.SeekToStart()
+
+	for it.Next() {
+		if !yield(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
+
+// iterateBackward yields iterator items from end to start.
+func iterateBackward[K, V any](it *iterator[K, V], yield func(K, ref.R[V]) bool) {
+	it.SeekToEnd()
+
+	for it.Previous() {
+		if !yield(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
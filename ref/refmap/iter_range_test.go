@@ -0,0 +1,342 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/ref"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModel is a sorted slice model of a map.
+type testModel struct {
+	keys []int
+}
+
+func (m *testModel) set(key int) {
+	i, ok := slices.BinarySearch(m.keys, key)
+	if !ok {
+		m.keys = slices.Insert(m.keys, i, key)
+	}
+}
+
+func (m *testModel) delete(key int) {
+	i, ok := slices.BinarySearch(m.keys, key)
+	if ok {
+		m.keys = slices.Delete(m.keys, i, i+1)
+	}
+}
+
+func (m *testModel) between(from, to int, flags RangeFlags) []int {
+	result := []int{}
+	for _, key := range m.keys {
+		switch {
+		case key < from, key == from && flags&RangeIncludeFrom == 0:
+			continue
+		case key > to, key == to && flags&RangeIncludeTo == 0:
+			continue
+		}
+		result = append(result, key)
+	}
+	return result
+}
+
+func testRangeKeys(it Iterator[int, *Value], backward bool) []int {
+	keys := []int{}
+	if backward {
+		it.SeekToEnd()
+		for it.Previous() {
+			keys = append(keys, it.Key())
+		}
+		return keys
+	}
+
+	it.SeekToStart()
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	return keys
+}
+
+// Range
+
+func TestRange__should_iterate_bounded_ranges(t *testing.T) {
+	items := testItemsN(100)
+	btree := testBtree(t, items...)
+
+	it := btree.Range(10, 20, RangeIncludeFrom)
+	defer it.Free()
+
+	keys := testRangeKeys(it, false)
+	assert.Equal(t, []int{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, keys)
+
+	keys = testRangeKeys(it, true)
+	assert.Equal(t, []int{19, 18, 17, 16, 15, 14, 13, 12, 11, 10}, keys)
+}
+
+func TestRange__should_respect_inclusive_flags(t *testing.T) {
+	items := testItemsN(100)
+	btree := testBtree(t, items...)
+
+	tests := []struct {
+		flags RangeFlags
+		keys  []int
+	}{
+		{RangeExclusive, []int{11, 12}},
+		{RangeIncludeFrom, []int{10, 11, 12}},
+		{RangeIncludeTo, []int{11, 12, 13}},
+		{RangeInclusive, []int{10, 11, 12, 13}},
+	}
+
+	for _, tt := range tests {
+		it := btree.Range(10, 13, tt.flags)
+		keys := testRangeKeys(it, false)
+		it.Free()
+
+		assert.Equal(t, tt.keys, keys, "flags=%d", tt.flags)
+	}
+}
+
+func TestRange__should_return_first_item_after_previous_stops_at_start(t *testing.T) {
+	items := testItemsN(100)
+	btree := testBtree(t, items...)
+
+	it := btree.Range(10, 20, RangeIncludeFrom)
+	defer it.Free()
+
+	require.True(t, it.Next())
+	require.True(t, it.Next())
+	assert.Equal(t, 11, it.Key())
+
+	require.True(t, it.Previous())
+	assert.Equal(t, 10, it.Key())
+	require.False(t, it.Previous())
+	require.False(t, it.Previous())
+
+	require.True(t, it.Next())
+	assert.Equal(t, 10, it.Key())
+}
+
+func TestRange__should_return_previous_item_after_next_stops_at_end(t *testing.T) {
+	items := testItemsN(100)
+	btree := testBtree(t, items...)
+
+	it := btree.Range(10, 12, RangeInclusive)
+	defer it.Free()
+
+	keys := testRangeKeys(it, false)
+	require.Equal(t, []int{10, 11, 12}, keys)
+	require.False(t, it.Next())
+
+	require.True(t, it.Previous())
+	assert.Equal(t, 12, it.Key())
+}
+
+func TestRange_SeekBefore__should_clamp_key_to_range(t *testing.T) {
+	items := testItemsN(100)
+	btree := testBtree(t, items...)
+
+	it := btree.Range(10, 20, RangeIncludeFrom)
+	defer it.Free()
+
+	it.SeekBefore(5)
+	require.True(t, it.Next())
+	assert.Equal(t, 10, it.Key())
+
+	it.SeekBefore(50)
+	require.False(t, it.Next())
+	require.True(t, it.Previous())
+	assert.Equal(t, 19, it.Key())
+}
+
+// SeekAfter/SeekTo
+
+func TestIterator_SeekAfter__should_position_after_floor_item(t *testing.T) {
+	btree := testBtree(t)
+	for i := 0; i < 1000; i += 2 {
+		btree.SetNoRetain(i, testValue(i))
+	}
+
+	it := btree.Iterator()
+	defer it.Free()
+
+	for key := 0; key < 1000; key++ {
+		it.SeekAfter(key)
+		require.True(t, it.Previous())
+		assert.Equal(t, key-key%2, it.Key())
+
+		it.SeekAfter(key)
+		ok := it.Next()
+		if key >= 998 {
+			require.False(t, ok)
+			continue
+		}
+		require.True(t, ok)
+		assert.Equal(t, key-key%2+2, it.Key())
+	}
+}
+
+func TestIterator_SeekTo__should_position_at_ceiling_item(t *testing.T) {
+	btree := testBtree(t)
+	for i := 0; i < 1000; i += 2 {
+		btree.SetNoRetain(i, testValue(i))
+	}
+
+	it := btree.Iterator()
+	defer it.Free()
+
+	for key := -1; key < 999; key++ {
+		require.True(t, it.SeekTo(key))
+		assert.Equal(t, key+(key&1), it.Key())
+	}
+
+	require.False(t, it.SeekTo(999))
+}
+
+// Model
+
+func TestRange__should_match_sorted_slice_model_under_random_operations(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	model := &testModel{}
+
+	btree := testBtree(t)
+	defer btree.Free()
+
+	flags := []RangeFlags{RangeExclusive, RangeIncludeFrom, RangeIncludeTo, RangeInclusive}
+
+	for i := 0; i < 2000; i++ {
+		key := random.Intn(1000)
+		if random.Intn(3) == 0 {
+			btree.Delete(key)
+			model.delete(key)
+		} else {
+			btree.SetNoRetain(key, testValue(key))
+			model.set(key)
+		}
+
+		if i%20 != 0 {
+			continue
+		}
+
+		for j := 0; j < 10; j++ {
+			from := random.Intn(1100) - 50
+			to := from + random.Intn(300) - 20
+			fl := flags[random.Intn(len(flags))]
+			expected := model.between(from, to, fl)
+
+			it := btree.Range(from, to, fl)
+			forward := testRangeKeys(it, false)
+			backward := testRangeKeys(it, true)
+			it.Free()
+
+			require.Equal(t, expected, forward, "from=%d to=%d flags=%d", from, to, fl)
+			slices.Reverse(backward)
+			require.Equal(t, expected, backward, "from=%d to=%d flags=%d", from, to, fl)
+		}
+	}
+}
+
+// Sequences
+
+func TestMap_All__should_iterate_items_in_both_directions(t *testing.T) {
+	items := testItems()
+	btree := testBtree(t, items...)
+
+	keys := []int{}
+	for key, value := range btree.All() {
+		require.Equal(t, key, value.Unwrap().val)
+		keys = append(keys, key)
+	}
+	assert.Equal(t, btree.Keys(), keys)
+
+	backward := []int{}
+	for key := range btree.Backward() {
+		backward = append(backward, key)
+	}
+	slices.Reverse(backward)
+	assert.Equal(t, keys, backward)
+}
+
+func TestMap_Between__should_iterate_range_and_stop_on_break(t *testing.T) {
+	items := testItemsN(100)
+	btree := testBtree(t, items...)
+
+	keys := []int{}
+	for key := range btree.Between(10, 20, RangeInclusive) {
+		keys = append(keys, key)
+		if key == 15 {
+			break
+		}
+	}
+	assert.Equal(t, []int{10, 11, 12, 13, 14, 15}, keys)
+
+	keys = keys[:0]
+	for key := range btree.BetweenBackward(10, 20, RangeExclusive) {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{19, 18, 17, 16, 15, 14, 13, 12, 11}, keys)
+}
+
+// Prefix
+
+func TestPrefix__should_iterate_keys_with_prefix(t *testing.T) {
+	m := New[string, int](true, strings.Compare)
+	defer m.Free()
+
+	for _, key := range []string{"a", "ab", "abc", "abd", "ac", "b", "ab\xff", "ab\xff\x00"} {
+		m.Set(key, 0)
+	}
+
+	keys := []string{}
+	for key := range Prefix(m, "ab") {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"ab", "abc", "abd", "ab\xff", "ab\xff\x00"}, keys)
+
+	keys = keys[:0]
+	for key := range Prefix(m, "ab\xff") {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"ab\xff", "ab\xff\x00"}, keys)
+}
+
+func TestPrefix__should_support_byte_keys(t *testing.T) {
+	type Key []byte
+	compare := func(a, b Key) int { return strings.Compare(string(a), string(b)) }
+
+	m := New[Key, ref.R[int]](true, compare)
+	defer m.Free()
+
+	for _, key := range []string{"x", "xy", "xz", "y"} {
+		m.Set(Key(key), nil)
+	}
+
+	keys := []string{}
+	for key := range Prefix(m, Key("x")) {
+		keys = append(keys, string(key))
+	}
+	assert.Equal(t, []string{"x", "xy", "xz"}, keys)
+}
+
+func TestPrefixEnd__should_return_next_prefix(t *testing.T) {
+	end, ok := prefixEnd("ab")
+	require.True(t, ok)
+	assert.Equal(t, "ac", end)
+
+	end, ok = prefixEnd("a\xff\xff")
+	require.True(t, ok)
+	assert.Equal(t, "b", end)
+
+	_, ok = prefixEnd("\xff")
+	assert.False(t, ok)
+
+	_, ok = prefixEnd("")
+	assert.False(t, ok)
+}
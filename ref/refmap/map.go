@@ -5,6 +5,8 @@
 package refmap
 
 import (
+	"iter"
+
 	"github.com/basecomplextech/baselibrary/pools"
 	"github.com/basecomplextech/baselibrary/ref"
 )
@@ -17,7 +19,7 @@ type Map[K, V any] interface {
 	// Empty returns true if the map is empty.
 	Empty() bool
 
-	// Length returns the number of items in this map, this can be an estimate.
+	// Length returns the number of items in this map.
 	Length() int64
 
 	// Mutable returns true if the map is mutable.
@@ -39,12 +41,52 @@ type Map[K, V any] interface {
 	// Contains returns true if the map contains a key.
 	Contains(key K) bool
 
+	// Rank returns the number of items with keys less than the key,
+	// i.e. the index of the key if present, or its insertion index.
+	Rank(key K) int64
+
+	// At returns an item by its index in key order, does not retain the value.
+	At(index int64) (K, ref.R[V], bool)
+
 	// Iterator returns an iterator, the iterator does not retain the values.
 	Iterator() Iterator[K, V]
 
+	// Range returns an iterator over a key range, the iterator does not retain the values.
+	//
+	// Seeking is bounded by the range, i.e. SeekToStart positions the iterator before
+	// the first item in the range, and SeekToEnd after the last one.
+	//
+	// Example:
+	//
+	//	it := m.Range(10, 20, refmap.RangeIncludeFrom)
+	//	defer it.Free()
+	//
+	//	for it.Next() {
+	//		key := it.Key() // 10 <= key < 20
+	//	}
+	Range(from K, to K, flags RangeFlags) Iterator[K, V]
+
 	// Keys returns all keys.
 	Keys() []K
 
+	// Sequences
+
+	// All returns a sequence of all items in ascending order, does not retain the values.
+	// The map must not be modified during iteration.
+	All() iter.Seq2[K, ref.R[V]]
+
+	// Backward returns a sequence of all items in descending order, does not retain the values.
+	// The map must not be modified during iteration.
+	Backward() iter.Seq2[K, ref.R[V]]
+
+	// Between returns a sequence of items in a key range in ascending order,
+	// does not retain the values. The map must not be modified during iteration.
+	Between(from K, to K, flags RangeFlags) iter.Seq2[K, ref.R[V]]
+
+	// BetweenBackward returns a sequence of items in a key range in descending order,
+	// does not retain the values. The map must not be modified during iteration.
+	BetweenBackward(from K, to K, flags RangeFlags) iter.Seq2[K, ref.R[V]]
+
 	// Write
 
 	// Set adds an item to the map, wraps into into a reference.
@@ -128,7 +170,7 @@ func (t *btree[K, V]) Empty() bool {
 	return t.length == 0
 }
 
-// Length returns the number of items in this map, this can be an estimate.
+// Length returns the number of items in this map.
 func (t *btree[K, V]) Length() int64 {
 	return t.length
 }
@@ -179,6 +221,65 @@ func (t *btree[K, V]) Contains(key K) bool {
 	return t.root.contains(key, t.compare)
 }
 
+// Rank returns the number of items with keys less than the key,
+// i.e. the index of the key if present, or its insertion index.
+func (t *btree[K, V]) Rank(key K) int64 {
+	if t.length == 0 {
+		return 0
+	}
+
+	rank := int64(0)
+
+	node := t.root
+	for {
+		branch, ok := node.(*branchNode[K, V])
+		if !ok {
+			break
+		}
+
+		// Add counts of preceding children
+		index := branch.indexOf(key, t.compare)
+		for _, item := range branch.items[:index] {
+			rank += item.node.count()
+		}
+		node = branch.child(index)
+	}
+
+	leaf := node.(*leafNode[K, V])
+	index := leaf.indexOf(key, t.compare)
+	return rank + int64(index)
+}
+
+// At returns an item by its index in key order, does not retain the value.
+func (t *btree[K, V]) At(index int64) (key K, value ref.R[V], ok bool) {
+	if index < 0 || index >= t.root.count() {
+		return
+	}
+
+	node := t.root
+	for {
+		branch, ok := node.(*branchNode[K, V])
+		if !ok {
+			break
+		}
+
+		// Find child with index
+		i := 0
+		for ; i < len(branch.items)-1; i++ {
+			count := branch.items[i].node.count()
+			if index < count {
+				break
+			}
+			index -= count
+		}
+		node = branch.child(i)
+	}
+
+	leaf := node.(*leafNode[K, V])
+	item := leaf.items[index]
+	return item.key, item.value, true
+}
+
 // Iterator returns an iterator, the iterator does not retain the values.
 func (t *btree[K, V]) Iterator() Iterator[K, V] {
 	it := newIterator(t)
@@ -186,6 +287,11 @@ func (t *btree[K, V]) Iterator() Iterator[K, V] {
 	return it
 }
 
+// Range returns an iterator over a key range, the iterator does not retain the values.
+func (t *btree[K, V]) Range(from K, to K, flags RangeFlags) Iterator[K, V] {
+	return newRangeIterator(t, from, to, flags)
+}
+
 // Keys returns all keys.
 func (t *btree[K, V]) Keys() []K {
 	n := t.length
@@ -204,6 +310,50 @@ func (t *btree[K, V]) Keys() []K {
 	return keys
 }
 
+// Sequences
+
+// All returns a sequence of all items in ascending order, does not retain the values.
+func (t *btree[K, V]) All() iter.Seq2[K, ref.R[V]] {
+	return func(yield func(K, ref.R[V]) bool) {
+		it := newIterator(t)
+		defer it.Free()
+
+		iterateForward(it, yield)
+	}
+}
+
+// Backward returns a sequence of all items in descending order, does not retain the values.
+func (t *btree[K, V]) Backward() iter.Seq2[K, ref.R[V]] {
+	return func(yield func(K, ref.R[V]) bool) {
+		it := newIterator(t)
+		defer it.Free()
+
+		iterateBackward(it, yield)
+	}
+}
+
+// Between returns a sequence of items in a key range in ascending order,
+// does not retain the values.
+func (t *btree[K, V]) Between(from K, to K, flags RangeFlags) iter.Seq2[K, ref.R[V]] {
+	return func(yield func(K, ref.R[V]) bool) {
+		it := newRangeIterator(t, from, to, flags)
+		defer it.Free()
+
+		iterateForward(it, yield)
+	}
+}
+
+// BetweenBackward returns a sequence of items in a key range in descending order,
+// does not retain the values.
+func (t *btree[K, V]) BetweenBackward(from K, to K, flags RangeFlags) iter.Seq2[K, ref.R[V]] {
+	return func(yield func(K, ref.R[V]) bool) {
+		it := newRangeIterator(t, from, to, flags)
+		defer it.Free()
+
+		iterateBackward(it, yield)
+	}
+}
+
 // Write
 
 // Set adds an item to the map, wraps into into a reference.
@@ -0,0 +1,66 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// ConflictFunc resolves a key changed differently in two maps, absent values are nil.
+//
+// The function returns a resolved value and true, or false to delete the key.
+// The resolved value is retained by the merged map.
+type ConflictFunc[K, V any] func(key K, base, ours, theirs ref.R[V]) (ref.R[V], bool)
+
+// Merge performs a three-way merge, and returns a new mutable map with our changes
+// and their changes applied to the base map.
+//
+// The merged map is a clone of our map with their changes applied, so the cost scales
+// with the number of their changes, see [Diff]. Keys changed in both maps to the same
+// value are not conflicts, other keys changed in both maps are resolved by the conflict
+// function. Values are compared by reference identity.
+//
+// Our map must be immutable, all maps must use the same compare function.
+func Merge[K, V any](base, ours, theirs Map[K, V], conflict ConflictFunc[K, V]) Map[K, V] {
+	result := ours.Clone()
+
+	it := Diff(base, theirs)
+	defer it.Free()
+
+	for it.Next() {
+		change := it.Change()
+		key := change.Key
+
+		// Apply their change if we have not changed the key,
+		// or skip it if we have made the same change.
+		value, _ := ours.Get(key)
+		switch {
+		case value == change.Old:
+			mergeApply(result, key, change.New)
+			continue
+		case value == change.New:
+			continue
+		}
+
+		// Resolve conflict
+		resolved, ok := conflict(key, change.Old, value, change.New)
+		if !ok {
+			resolved = nil
+		}
+		mergeApply(result, key, resolved)
+	}
+	return result
+}
+
+// private
+
+// mergeApply sets and retains a value, or deletes the key if the value is nil.
+func mergeApply[K, V any](m Map[K, V], key K, value ref.R[V]) {
+	if value == nil {
+		m.Delete(key)
+		return
+	}
+	m.SetRetain(key, value)
+}
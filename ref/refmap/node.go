@@ -18,6 +18,7 @@ type node[K, V any] interface {
 	mutable() bool
 
 	length() int
+	count() int64 // number of items in the subtree
 	minKey() K
 	maxKey() K
 
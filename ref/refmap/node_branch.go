@@ -19,8 +19,9 @@ type branchNode[K, V any] struct {
 	items  []branchItem[K, V]
 	_items [maxItems]branchItem[K, V]
 
-	mut  bool
-	refs int64
+	mut   bool
+	refs  int64
+	total int64 // number of items in the subtree
 }
 
 type branchItem[K, V any] struct {
@@ -43,6 +44,7 @@ func newBranchNode[K, V any](children ...node[K, V]) *branchNode[K, V] {
 			node:   child,
 		}
 		n.items = append(n.items, item)
+		n.total += child.count()
 	}
 	return n
 }
@@ -56,6 +58,7 @@ func cloneBranchNode[K, V any](n *branchNode[K, V]) *branchNode[K, V] {
 	n1.refs = 1
 
 	n1.items = n1.items[:len(n.items)]
+	n1.total = n.total
 	copy(n1.items, n.items)
 
 	// Retain children
@@ -76,6 +79,10 @@ func nextBranchNode[K, V any](items []branchItem[K, V]) *branchNode[K, V] {
 	n.items = n.items[:len(items)]
 	copy(n.items, items)
 
+	for _, item := range n.items {
+		n.total += item.node.count()
+	}
+
 	// No need to retain children
 	// We have moved them to the new node
 	return n
@@ -126,6 +133,10 @@ func (n *branchNode[K, V]) length() int {
 	return len(n.items)
 }
 
+func (n *branchNode[K, V]) count() int64 {
+	return n.total
+}
+
 func (n *branchNode[K, V]) minKey() K {
 	return n.items[0].minKey
 }
@@ -198,6 +209,9 @@ func (n *branchNode[K, V]) put(key K, value ref.R[V], compare CompareFunc[K]) bo
 
 	// Insert item
 	mod := node.put(key, value, compare)
+	if mod {
+		n.total++
+	}
 
 	// Update min key
 	n.items[index].minKey = node.minKey()
@@ -218,6 +232,7 @@ func (n *branchNode[K, V]) delete(key K, compare CompareFunc[K]) bool {
 	if !mod {
 		return false
 	}
+	n.total--
 
 	// Delete child if empty
 	if node.length() == 0 {
@@ -254,6 +269,7 @@ func (n *branchNode[K, V]) split() (node[K, V], bool) {
 
 	// Move items to next node
 	next := nextBranchNode(n.items[middle:])
+	n.total -= next.total
 
 	// Clear and truncate moved items,
 	// Do not release them, we have moved them to the new node
@@ -282,6 +298,7 @@ func (n *branchNode[K, V]) deleteChild(index int) {
 
 	// Release node
 	node := n.items[index].node
+	n.total -= node.count()
 	node.release()
 
 	// Shift items left
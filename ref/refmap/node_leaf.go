@@ -127,6 +127,10 @@ func (n *leafNode[K, V]) length() int {
 	return len(n.items)
 }
 
+func (n *leafNode[K, V]) count() int64 {
+	return int64(len(n.items))
+}
+
 func (n *leafNode[K, V]) minKey() K {
 	return n.items[0].key
 }
@@ -160,6 +164,19 @@ func (n *leafNode[K, V]) indexOf(key K, compare CompareFunc[K]) int {
 	})
 }
 
+// search returns an index of an item with key >= key, or key > key when after is true.
+func (n *leafNode[K, V]) search(key K, after bool, compare CompareFunc[K]) int {
+	if !after {
+		return n.indexOf(key, compare)
+	}
+
+	return sort.Search(len(n.items), func(i int) bool {
+		key0 := n.items[i].key
+		cmp := compare(key0, key)
+		return cmp > 0
+	})
+}
+
 func (n *leafNode[K, V]) get(key K, compare CompareFunc[K]) (v ref.R[V], ok bool) {
 	index := n.indexOf(key, compare)
 
@@ -0,0 +1,128 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"errors"
+
+	"github.com/basecomplextech/baselibrary/filesys"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// Snapshot is a read-only sorted map backed by a memory-mapped snapshot file.
+//
+// Items are decoded on access, the snapshot is never loaded fully into memory.
+// Blocks are verified on first access, reading a corrupted block panics with
+// ErrSnapshotCorrupted. Open the snapshot with the Verify option to verify all blocks
+// upfront and to return an error instead.
+//
+// The snapshot is safe for concurrent reads, iterators must not be used concurrently.
+//
+// Format:
+//
+//	snapshot  = block* index footer
+//	block     = entry* offset* checksum
+//	entry     = compactint(len(key)) key compactint(len(value)) value
+//	offset    = uint32 entry offset in block
+//	index     = compactint(blocks) indexItem* checksum
+//	indexItem = compactint(len(firstKey)) firstKey compactint(offset) compactint(size) compactint(count)
+//	footer    = uint64(indexOffset) uint64(indexSize) uint64(length) checksum magic
+//	checksum  = uint64 xxh3 of the preceding data
+//
+// All fixed size integers are big-endian.
+type Snapshot[K, V any] interface {
+	// Empty returns true if the snapshot is empty.
+	Empty() bool
+
+	// Length returns the number of items in the snapshot.
+	Length() int64
+
+	// Read
+
+	// Get decodes and returns an item value by a key, the caller must release the value.
+	//
+	// Unlike [Map.Get], the value is owned, not borrowed, because values are decoded
+	// on access. Code shared with maps must release the values only for snapshots.
+	Get(key K) (ref.R[V], bool)
+
+	// Contains returns true if the snapshot contains a key.
+	Contains(key K) bool
+
+	// Rank returns the number of items with keys less than the key,
+	// i.e. the index of the key if present, or its insertion index.
+	Rank(key K) int64
+
+	// Iterator returns an iterator, the iterator decodes and releases the values.
+	Iterator() Iterator[K, V]
+
+	// Verify verifies all blocks, decodes all keys and values, and returns ErrSnapshotCorrupted
+	// on a checksum, encoding or key order mismatch. Verified blocks never panic on reads.
+	Verify() error
+
+	// Internal
+
+	// Free frees the snapshot, does not close the file.
+	Free()
+}
+
+// Codec encodes and decodes snapshot keys or values.
+type Codec[T any] interface {
+	// Append appends an encoded value to a buffer.
+	Append(b []byte, v T) ([]byte, error)
+
+	// Decode decodes a value from bytes.
+	//
+	// The bytes point to the mapped snapshot file, and are valid until the file is closed.
+	Decode(b []byte) (T, error)
+}
+
+// SnapshotOptions specifies the snapshot writer and reader options.
+type SnapshotOptions struct {
+	// BlockSize is the target block size in bytes, the default is 4KB.
+	BlockSize int
+
+	// Verify verifies all blocks when opening a snapshot, see [Snapshot.Verify].
+	// Reading a verified snapshot never panics on corruption.
+	Verify bool
+}
+
+// ErrSnapshotCorrupted is returned or panicked with when a snapshot is corrupted.
+var ErrSnapshotCorrupted = errors.New("refmap snapshot corrupted")
+
+// OpenSnapshot maps a snapshot file into memory, verifies its index and returns a read-only map.
+// All blocks are verified only with the Verify option.
+//
+// The file must not be closed or modified until the snapshot is freed.
+func OpenSnapshot[K, V any](file filesys.File, compare CompareFunc[K], keys Codec[K],
+	values Codec[V], opts SnapshotOptions) (Snapshot[K, V], error) {
+
+	data, err := file.Map()
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := openSnapshot(data, compare, keys, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Verify {
+		if err := s.Verify(); err != nil {
+			s.Free()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// internal
+
+const (
+	snapshotBlockSize  = 4 << 10
+	snapshotMagic      = "RFMSNAP1"
+	snapshotFooterSize = 8*4 + len(snapshotMagic)
+	snapshotOffsetSize = 4
+	snapshotSumSize    = 8
+)
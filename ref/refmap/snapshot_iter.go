@@ -0,0 +1,215 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+var _ Iterator[any, any] = (*snapshotIterator[any, any])(nil)
+
+type snapshotIterator[K, V any] struct {
+	*snapshotIterState[K, V]
+}
+
+type snapshotIterState[K, V any] struct {
+	s *snapshot[K, V]
+
+	index int64 // current item, or the next item when not ok
+	ok    bool  // index points to an item
+
+	block int               // cached block, -1 when not loaded
+	data  snapshotBlockData // cached block data
+
+	key    K
+	hasKey bool
+	value  ref.R[V] // decoded value, released on move
+}
+
+func newSnapshotIterator[K, V any](s *snapshot[K, V]) *snapshotIterator[K, V] {
+	it := &snapshotIterator[K, V]{acquireSnapshotIterState[K, V]()}
+	it.s = s
+	it.block = -1
+	return it
+}
+
+func (s *snapshotIterState[K, V]) reset() {
+	if s.value != nil {
+		s.value.Release()
+	}
+	*s = snapshotIterState[K, V]{}
+}
+
+// OK returns true when the iterator points to a valid item, or false on end.
+func (it *snapshotIterator[K, V]) OK() bool {
+	return it.ok
+}
+
+// Key returns the current key or zero, the key is valid until the next iteration.
+func (it *snapshotIterator[K, V]) Key() K {
+	if !it.ok {
+		var zero K
+		return zero
+	}
+
+	if !it.hasKey {
+		b, j := it.load()
+		it.key = it.s.key(b, it.data, j)
+		it.hasKey = true
+	}
+	return it.key
+}
+
+// Value returns the current value or zero, the value is valid until the next iteration.
+func (it *snapshotIterator[K, V]) Value() ref.R[V] {
+	if !it.ok {
+		return nil
+	}
+
+	if it.value == nil {
+		b, j := it.load()
+		_, value := it.s.entry(b, it.data, j)
+		it.value = it.s.decodeValue(b, value)
+	}
+	return it.value
+}
+
+// Iterating
+
+// Next moves to the next item.
+func (it *snapshotIterator[K, V]) Next() bool {
+	next := it.index
+	if it.ok {
+		next++
+	}
+
+	n := it.s.length
+	if next >= n {
+		it.move(n, false)
+		return false
+	}
+
+	it.move(next, true)
+	return true
+}
+
+// Previous moves to the previous item.
+func (it *snapshotIterator[K, V]) Previous() bool {
+	prev := it.index - 1
+	if prev < 0 {
+		it.move(0, false)
+		return false
+	}
+
+	it.move(prev, true)
+	return true
+}
+
+// Seeking
+
+// SeekToStart positions the iterator at the start.
+func (it *snapshotIterator[K, V]) SeekToStart() bool {
+	it.move(0, false)
+	return true
+}
+
+// SeekToEnd positions the iterator at the end.
+func (it *snapshotIterator[K, V]) SeekToEnd() bool {
+	it.move(it.s.length, false)
+	return true
+}
+
+// SeekBefore positions the iterator before an item with key >= key.
+func (it *snapshotIterator[K, V]) SeekBefore(key K) bool {
+	b, j := it.s.search(key, false)
+	index := it.s.index(b, j)
+	it.move(index, false)
+	return index < it.s.length
+}
+
+// SeekAfter positions the iterator after an item with key <= key,
+// i.e. before an item with key > key.
+func (it *snapshotIterator[K, V]) SeekAfter(key K) bool {
+	b, j := it.s.search(key, true)
+	index := it.s.index(b, j)
+	it.move(index, false)
+	return index < it.s.length
+}
+
+// SeekTo positions the iterator at an item with key >= key, and returns true if found.
+func (it *snapshotIterator[K, V]) SeekTo(key K) bool {
+	if !it.SeekBefore(key) {
+		return false
+	}
+	return it.Next()
+}
+
+// SeekIndex positions the iterator before an item with an index in key order,
+// and returns true if found.
+func (it *snapshotIterator[K, V]) SeekIndex(index int64) bool {
+	n := it.s.length
+	it.move(max(0, min(index, n)), false)
+	return index >= 0 && index < n
+}
+
+// Internal
+
+// Free frees the iterator, implements the ref.Free interface.
+func (it *snapshotIterator[K, V]) Free() {
+	state := it.snapshotIterState
+	it.snapshotIterState = nil
+	releaseSnapshotIterState(state)
+}
+
+// private
+
+// move positions the iterator at an index, and releases the current item.
+func (it *snapshotIterator[K, V]) move(index int64, ok bool) {
+	if it.value != nil {
+		it.value.Release()
+		it.value = nil
+	}
+
+	var zero K
+	it.key = zero
+	it.hasKey = false
+
+	it.index = index
+	it.ok = ok
+}
+
+// load returns the current item position, and loads its block when not cached.
+func (it *snapshotIterator[K, V]) load() (int, int) {
+	if it.block >= 0 {
+		block := it.s.blocks[it.block]
+		j := it.index - block.start
+		if j >= 0 && j < int64(block.count) {
+			return it.block, int(j)
+		}
+	}
+
+	b := it.s.locate(it.index)
+	it.block = b
+	it.data = it.s.block(b)
+	return b, int(it.index - it.s.blocks[b].start)
+}
+
+// state pool
+
+var snapshotIterStatePools = pools.NewPools()
+
+func acquireSnapshotIterState[K, V any]() *snapshotIterState[K, V] {
+	v, ok := pools.Acquire[*snapshotIterState[K, V]](snapshotIterStatePools)
+	if ok {
+		return v
+	}
+	return &snapshotIterState[K, V]{}
+}
+
+func releaseSnapshotIterState[K, V any](s *snapshotIterState[K, V]) {
+	s.reset()
+	pools.Release(snapshotIterStatePools, s)
+}
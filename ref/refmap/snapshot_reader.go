@@ -0,0 +1,463 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/basecomplextech/baselibrary/crypto/xxh3"
+	"github.com/basecomplextech/baselibrary/encoding/compactint"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+var _ Snapshot[any, any] = (*snapshot[any, any])(nil)
+
+type snapshot[K, V any] struct {
+	data    []byte
+	compare CompareFunc[K]
+	keys    Codec[K]
+	values  Codec[V]
+
+	length   int64
+	blocks   []snapshotBlock[K]
+	verified []atomic.Bool
+}
+
+// snapshotBlock is a block index item.
+type snapshotBlock[K any] struct {
+	first  K     // first key
+	offset int   // block offset in data
+	size   int   // block size including offsets and checksum
+	start  int64 // index of the first item
+	count  int   // number of items
+}
+
+// snapshotBlockData holds verified block entries and offsets.
+type snapshotBlockData struct {
+	entries []byte
+	offsets []byte
+}
+
+func openSnapshot[K, V any](data []byte, compare CompareFunc[K], keys Codec[K],
+	values Codec[V]) (*snapshot[K, V], error) {
+
+	s := &snapshot[K, V]{
+		data:    data,
+		compare: compare,
+		keys:    keys,
+		values:  values,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Empty returns true if the snapshot is empty.
+func (s *snapshot[K, V]) Empty() bool {
+	return s.length == 0
+}
+
+// Length returns the number of items in the snapshot.
+func (s *snapshot[K, V]) Length() int64 {
+	return s.length
+}
+
+// Read
+
+// Get decodes and returns an item value by a key, the caller must release the value.
+func (s *snapshot[K, V]) Get(key K) (ref.R[V], bool) {
+	b, j, ok := s.find(key)
+	if !ok {
+		return nil, false
+	}
+
+	block := s.block(b)
+	_, value := s.entry(b, block, j)
+	return s.decodeValue(b, value), true
+}
+
+// Contains returns true if the snapshot contains a key.
+func (s *snapshot[K, V]) Contains(key K) bool {
+	_, _, ok := s.find(key)
+	return ok
+}
+
+// Rank returns the number of items with keys less than the key,
+// i.e. the index of the key if present, or its insertion index.
+func (s *snapshot[K, V]) Rank(key K) int64 {
+	b, j := s.search(key, false)
+	return s.index(b, j)
+}
+
+// Iterator returns an iterator, the iterator decodes and releases the values.
+func (s *snapshot[K, V]) Iterator() Iterator[K, V] {
+	return newSnapshotIterator(s)
+}
+
+// Verify verifies all blocks, decodes all keys and values, and returns ErrSnapshotCorrupted
+// on a checksum, encoding or key order mismatch. Verified blocks never panic on reads.
+func (s *snapshot[K, V]) Verify() error {
+	var prev K
+	for b := range s.blocks {
+		block, err := s.verifyBlock(b)
+		if err != nil {
+			return err
+		}
+
+		for j := 0; j < s.blocks[b].count; j++ {
+			key, value, err := parseSnapshotEntry(block, j)
+			if err != nil {
+				return fmt.Errorf("%w: block %d: %v", ErrSnapshotCorrupted, b, err)
+			}
+
+			k, err := s.keys.Decode(key)
+			if err != nil {
+				return fmt.Errorf("%w: block %d: %v", ErrSnapshotCorrupted, b, err)
+			}
+			if err := s.verifyValue(value); err != nil {
+				return fmt.Errorf("%w: block %d: %v", ErrSnapshotCorrupted, b, err)
+			}
+
+			switch {
+			case j == 0 && s.compare(k, s.blocks[b].first) != 0:
+				return fmt.Errorf("%w: block %d: first key mismatch", ErrSnapshotCorrupted, b)
+			case (b > 0 || j > 0) && s.compare(prev, k) >= 0:
+				return fmt.Errorf("%w: block %d: keys out of order", ErrSnapshotCorrupted, b)
+			}
+			prev = k
+		}
+
+		s.verified[b].Store(true)
+	}
+	return nil
+}
+
+// Internal
+
+// Free frees the snapshot, does not close the file.
+func (s *snapshot[K, V]) Free() {
+	s.data = nil
+	s.blocks = nil
+	s.verified = nil
+}
+
+// private
+
+// open parses the footer and the index.
+func (s *snapshot[K, V]) open() error {
+	if len(s.data) < snapshotFooterSize {
+		return fmt.Errorf("%w: file too small", ErrSnapshotCorrupted)
+	}
+
+	// Parse footer
+	end := len(s.data) - snapshotFooterSize
+	footer := s.data[end:]
+	if string(footer[32:]) != snapshotMagic {
+		return fmt.Errorf("%w: invalid magic", ErrSnapshotCorrupted)
+	}
+	if binary.BigEndian.Uint64(footer[24:]) != xxh3.Sum64(footer[:24]) {
+		return fmt.Errorf("%w: footer checksum mismatch", ErrSnapshotCorrupted)
+	}
+
+	indexOffset := binary.BigEndian.Uint64(footer[0:])
+	indexSize := binary.BigEndian.Uint64(footer[8:])
+	length := binary.BigEndian.Uint64(footer[16:])
+	if indexOffset > uint64(end) ||
+		indexSize > uint64(end)-indexOffset ||
+		indexSize < snapshotSumSize {
+		return fmt.Errorf("%w: index out of range", ErrSnapshotCorrupted)
+	}
+
+	// Verify index
+	index := s.data[indexOffset : indexOffset+indexSize]
+	sum := index[len(index)-snapshotSumSize:]
+	index = index[:len(index)-snapshotSumSize]
+	if binary.BigEndian.Uint64(sum) != xxh3.Sum64(index) {
+		return fmt.Errorf("%w: index checksum mismatch", ErrSnapshotCorrupted)
+	}
+
+	// Parse index
+	n, index, err := readSnapshotUint(index)
+	if err != nil {
+		return fmt.Errorf("%w: index: %v", ErrSnapshotCorrupted, err)
+	}
+	if n > uint64(len(index)) {
+		return fmt.Errorf("%w: invalid block count", ErrSnapshotCorrupted)
+	}
+
+	blocks := make([]snapshotBlock[K], 0, n)
+	start := int64(0)
+	offset := uint64(0)
+
+	for i := uint64(0); i < n; i++ {
+		var item snapshotIndexItem
+		item, index, err = readSnapshotIndexItem(index)
+		if err != nil {
+			return fmt.Errorf("%w: index: %v", ErrSnapshotCorrupted, err)
+		}
+		off, size, count := item.offset, item.size, item.count
+
+		// Blocks are contiguous and non-empty
+		switch {
+		case off != offset,
+			size < snapshotSumSize,
+			size > indexOffset-off,
+			count == 0,
+			count > (size-snapshotSumSize)/snapshotOffsetSize:
+			return fmt.Errorf("%w: invalid block %d", ErrSnapshotCorrupted, i)
+		}
+
+		first, err := s.keys.Decode(item.key)
+		if err != nil {
+			return fmt.Errorf("%w: block %d: %v", ErrSnapshotCorrupted, i, err)
+		}
+
+		block := snapshotBlock[K]{
+			first:  first,
+			offset: int(off),
+			size:   int(size),
+			start:  start,
+			count:  int(count),
+		}
+		blocks = append(blocks, block)
+
+		start += int64(count)
+		offset += size
+	}
+
+	switch {
+	case len(index) != 0:
+		return fmt.Errorf("%w: trailing index data", ErrSnapshotCorrupted)
+	case offset != indexOffset:
+		return fmt.Errorf("%w: index does not cover blocks", ErrSnapshotCorrupted)
+	case uint64(start) != length:
+		return fmt.Errorf("%w: length mismatch", ErrSnapshotCorrupted)
+	}
+
+	s.length = start
+	s.blocks = blocks
+	s.verified = make([]atomic.Bool, len(blocks))
+	return nil
+}
+
+// block returns block data, verifies it on first access, panics when the block is corrupted.
+func (s *snapshot[K, V]) block(b int) snapshotBlockData {
+	if s.verified[b].Load() {
+		return s.blockData(b)
+	}
+
+	block, err := s.verifyBlock(b)
+	if err != nil {
+		panic(err)
+	}
+	s.verified[b].Store(true)
+	return block
+}
+
+// blockData returns block data without verification.
+func (s *snapshot[K, V]) blockData(b int) snapshotBlockData {
+	block := s.blocks[b]
+	data := s.data[block.offset : block.offset+block.size-snapshotSumSize]
+	n := len(data) - block.count*snapshotOffsetSize
+
+	return snapshotBlockData{
+		entries: data[:n],
+		offsets: data[n:],
+	}
+}
+
+// verifyBlock verifies a block checksum and returns its data.
+func (s *snapshot[K, V]) verifyBlock(b int) (snapshotBlockData, error) {
+	block := s.blocks[b]
+	data := s.data[block.offset : block.offset+block.size]
+
+	n := len(data) - snapshotSumSize
+	if binary.BigEndian.Uint64(data[n:]) != xxh3.Sum64(data[:n]) {
+		return snapshotBlockData{}, fmt.Errorf("%w: block %d checksum mismatch",
+			ErrSnapshotCorrupted, b)
+	}
+	return s.blockData(b), nil
+}
+
+// entry returns an entry key and value, panics when the entry is corrupted.
+func (s *snapshot[K, V]) entry(b int, block snapshotBlockData, j int) ([]byte, []byte) {
+	key, value, err := parseSnapshotEntry(block, j)
+	if err != nil {
+		panic(fmt.Errorf("%w: block %d: %v", ErrSnapshotCorrupted, b, err))
+	}
+	return key, value
+}
+
+// search
+
+// find returns the position of an item with the key, or false.
+func (s *snapshot[K, V]) find(key K) (int, int, bool) {
+	b, j := s.search(key, false)
+	if b == len(s.blocks) {
+		return 0, 0, false
+	}
+
+	block := s.block(b)
+	if s.compare(s.key(b, block, j), key) != 0 {
+		return 0, 0, false
+	}
+	return b, j, true
+}
+
+// search returns the position of the first item with key >= key, or key > key when after.
+// The position is (len(blocks), 0) when there is no such item.
+func (s *snapshot[K, V]) search(key K, after bool) (int, int) {
+	match := func(k K) bool {
+		c := s.compare(k, key)
+		if after {
+			return c > 0
+		}
+		return c >= 0
+	}
+
+	// Find the first block which starts with a matching key,
+	// the previous block may also contain matching keys
+	b := sort.Search(len(s.blocks), func(i int) bool {
+		return match(s.blocks[i].first)
+	})
+	if b == 0 {
+		return 0, 0
+	}
+
+	prev := b - 1
+	block := s.block(prev)
+	j := sort.Search(s.blocks[prev].count, func(j int) bool {
+		return match(s.key(prev, block, j))
+	})
+	if j < s.blocks[prev].count {
+		return prev, j
+	}
+	return b, 0
+}
+
+// index returns the index of an item at a position.
+func (s *snapshot[K, V]) index(b int, j int) int64 {
+	if b == len(s.blocks) {
+		return s.length
+	}
+	return s.blocks[b].start + int64(j)
+}
+
+// locate returns the block which contains an item index.
+func (s *snapshot[K, V]) locate(index int64) int {
+	return sort.Search(len(s.blocks), func(i int) bool {
+		block := s.blocks[i]
+		return block.start+int64(block.count) > index
+	})
+}
+
+// decode
+
+// key decodes an item key, panics when the key is corrupted.
+func (s *snapshot[K, V]) key(b int, block snapshotBlockData, j int) K {
+	key, _ := s.entry(b, block, j)
+
+	k, err := s.keys.Decode(key)
+	if err != nil {
+		panic(fmt.Errorf("%w: block %d: %v", ErrSnapshotCorrupted, b, err))
+	}
+	return k
+}
+
+// decodeValue decodes a value and wraps it into a reference, panics when the value is corrupted.
+func (s *snapshot[K, V]) decodeValue(b int, value []byte) ref.R[V] {
+	v, err := s.values.Decode(value)
+	if err != nil {
+		panic(fmt.Errorf("%w: block %d: %v", ErrSnapshotCorrupted, b, err))
+	}
+
+	f, ok := (any)(v).(ref.Freer)
+	if ok {
+		return ref.NewFreer(v, f)
+	}
+	return ref.NewNoop(v)
+}
+
+// verifyValue decodes a value and frees it.
+func (s *snapshot[K, V]) verifyValue(value []byte) error {
+	v, err := s.values.Decode(value)
+	if err != nil {
+		return err
+	}
+
+	if f, ok := (any)(v).(ref.Freer); ok {
+		f.Free()
+	}
+	return nil
+}
+
+// util
+
+// parseSnapshotEntry returns an entry key and value in a block.
+func parseSnapshotEntry(block snapshotBlockData, j int) ([]byte, []byte, error) {
+	off := binary.BigEndian.Uint32(block.offsets[j*snapshotOffsetSize:])
+	if int(off) >= len(block.entries) {
+		return nil, nil, fmt.Errorf("entry %d offset out of range", j)
+	}
+
+	b := block.entries[off:]
+	key, b, err := readSnapshotBytes(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("entry %d: %w", j, err)
+	}
+	value, _, err := readSnapshotBytes(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("entry %d: %w", j, err)
+	}
+	return key, value, nil
+}
+
+// snapshotIndexItem is an encoded block index item.
+type snapshotIndexItem struct {
+	key    []byte
+	offset uint64
+	size   uint64
+	count  uint64
+}
+
+func readSnapshotIndexItem(b []byte) (item snapshotIndexItem, _ []byte, err error) {
+	if item.key, b, err = readSnapshotBytes(b); err != nil {
+		return item, nil, err
+	}
+	if item.offset, b, err = readSnapshotUint(b); err != nil {
+		return item, nil, err
+	}
+	if item.size, b, err = readSnapshotUint(b); err != nil {
+		return item, nil, err
+	}
+	if item.count, b, err = readSnapshotUint(b); err != nil {
+		return item, nil, err
+	}
+	return item, b, nil
+}
+
+func readSnapshotUint(b []byte) (uint64, []byte, error) {
+	v, n := compactint.Uint64(b)
+	if n <= 0 {
+		return 0, nil, errors.New("invalid compactint")
+	}
+	return v, b[n:], nil
+}
+
+func readSnapshotBytes(b []byte) ([]byte, []byte, error) {
+	n, b, err := readSnapshotUint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n > uint64(len(b)) {
+		return nil, nil, errors.New("bytes out of range")
+	}
+	return b[:n], b[n:], nil
+}
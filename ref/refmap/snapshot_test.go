@@ -0,0 +1,477 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/filesys"
+	"github.com/basecomplextech/baselibrary/filesys/memfs"
+	"github.com/basecomplextech/baselibrary/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSnapshotFile(t tests.T, opts SnapshotOptions, items ...Item[int, *Value]) filesys.File {
+	btree := testBtree(t, items...)
+	defer btree.Free()
+	btree.Freeze()
+
+	file := memfs.NewFile()
+	w := NewSnapshotWriter[int, *Value](testIntCodec{}, testValueCodec{}, opts)
+	if _, err := w.Write(file, btree); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func testSnapshot(t tests.T, opts SnapshotOptions, items ...Item[int, *Value]) Snapshot[int, *Value] {
+	file := testSnapshotFile(t, opts, items...)
+	return testOpenSnapshot(t, file, opts)
+}
+
+func testOpenSnapshot(t tests.T, file filesys.File, opts SnapshotOptions) Snapshot[int, *Value] {
+	compare := func(a, b int) int { return a - b }
+
+	s, err := OpenSnapshot[int, *Value](file, compare, testIntCodec{}, testValueCodec{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// testEvenItems returns items with even keys, so that odd keys are absent.
+func testEvenItems(n int) []Item[int, *Value] {
+	items := make([]Item[int, *Value], 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, testItem(i*2))
+	}
+	return items
+}
+
+func testSnapshotData(t *testing.T, file filesys.File) []byte {
+	data, err := file.Map()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// Write
+
+func TestSnapshotWriter_Write__should_panic_when_map_mutable(t *testing.T) {
+	btree := testBtree(t, testItemsN(10)...)
+	defer btree.Free()
+
+	file := memfs.NewFile()
+	w := NewSnapshotWriter[int, *Value](testIntCodec{}, testValueCodec{}, SnapshotOptions{})
+
+	assert.Panics(t, func() {
+		w.Write(file, btree)
+	})
+}
+
+func TestSnapshotWriter_Write__should_split_items_into_blocks(t *testing.T) {
+	opts := SnapshotOptions{BlockSize: 128}
+	s := testSnapshot(t, opts, testItemsN(1000)...)
+	defer s.Free()
+
+	s1 := s.(*snapshot[int, *Value])
+	assert.Greater(t, len(s1.blocks), 10)
+
+	for _, block := range s1.blocks {
+		assert.LessOrEqual(t, block.size, 128+32)
+	}
+}
+
+func TestSnapshotWriter_Write__should_return_codec_error(t *testing.T) {
+	btree := testBtree(t, testItemsN(10)...)
+	defer btree.Free()
+	btree.Freeze()
+
+	file := memfs.NewFile()
+	w := NewSnapshotWriter[int, *Value](testIntCodec{}, testErrorCodec{}, SnapshotOptions{})
+
+	_, err := w.Write(file, btree)
+	assert.Equal(t, errTestCodec, err)
+}
+
+// Open
+
+func TestOpenSnapshot__should_open_empty_snapshot(t *testing.T) {
+	s := testSnapshot(t, SnapshotOptions{})
+	defer s.Free()
+
+	assert.True(t, s.Empty())
+	assert.Equal(t, int64(0), s.Length())
+
+	_, ok := s.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), s.Rank(1))
+
+	it := s.Iterator()
+	defer it.Free()
+	assert.False(t, it.Next())
+	assert.NoError(t, s.Verify())
+}
+
+func TestOpenSnapshot__should_return_error_when_footer_corrupted(t *testing.T) {
+	file := testSnapshotFile(t, SnapshotOptions{}, testItemsN(10)...)
+	data := testSnapshotData(t, file)
+	data[len(data)-snapshotFooterSize] ^= 0xff
+
+	compare := func(a, b int) int { return a - b }
+	_, err := OpenSnapshot[int, *Value](file, compare, testIntCodec{}, testValueCodec{},
+		SnapshotOptions{})
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestOpenSnapshot__should_return_error_when_index_corrupted(t *testing.T) {
+	file := testSnapshotFile(t, SnapshotOptions{}, testItemsN(10)...)
+	data := testSnapshotData(t, file)
+	data[len(data)-snapshotFooterSize-snapshotSumSize-1] ^= 0xff
+
+	compare := func(a, b int) int { return a - b }
+	_, err := OpenSnapshot[int, *Value](file, compare, testIntCodec{}, testValueCodec{},
+		SnapshotOptions{})
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestOpenSnapshot__should_return_error_when_magic_invalid(t *testing.T) {
+	file := testSnapshotFile(t, SnapshotOptions{}, testItemsN(10)...)
+	data := testSnapshotData(t, file)
+	data[len(data)-1] = 0
+
+	compare := func(a, b int) int { return a - b }
+	_, err := OpenSnapshot[int, *Value](file, compare, testIntCodec{}, testValueCodec{},
+		SnapshotOptions{})
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestOpenSnapshot__should_return_error_when_verify_and_block_corrupted(t *testing.T) {
+	file := testSnapshotFile(t, SnapshotOptions{BlockSize: 128}, testItemsN(100)...)
+	data := testSnapshotData(t, file)
+	data[0] ^= 0xff
+
+	// Open without verify
+	s := testOpenSnapshot(t, file, SnapshotOptions{})
+	s.Free()
+
+	// Open with verify
+	compare := func(a, b int) int { return a - b }
+	opts := SnapshotOptions{Verify: true}
+
+	_, err := OpenSnapshot[int, *Value](file, compare, testIntCodec{}, testValueCodec{}, opts)
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestOpenSnapshot__should_verify_all_blocks(t *testing.T) {
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 128, Verify: true}, testItemsN(100)...)
+	defer s.Free()
+
+	s1 := s.(*snapshot[int, *Value])
+	for b := range s1.verified {
+		assert.True(t, s1.verified[b].Load(), "block %d", b)
+	}
+}
+
+// Get
+
+func TestSnapshot_Get__should_return_value(t *testing.T) {
+	items := testEvenItems(1000)
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 256}, items...)
+	defer s.Free()
+
+	assert.Equal(t, int64(1000), s.Length())
+
+	for _, item := range items {
+		value, ok := s.Get(item.Key)
+		require.True(t, ok, item.Key)
+		assert.Equal(t, item.Key, value.Unwrap().val)
+		value.Release()
+	}
+}
+
+func TestSnapshot_Get__should_return_false_when_not_found(t *testing.T) {
+	items := testEvenItems(1000)
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 256}, items...)
+	defer s.Free()
+
+	for _, key := range []int{-1, 1, 3, 501, 1999, 2000, 10000} {
+		_, ok := s.Get(key)
+		assert.False(t, ok, key)
+		assert.False(t, s.Contains(key), key)
+	}
+}
+
+func TestSnapshot_Get__should_decode_long_keys_and_values(t *testing.T) {
+	m := New[string, string](true, strings.Compare)
+	defer m.Free()
+
+	for i := 0; i < 100; i++ {
+		key := strings.Repeat(string(rune('a'+i%26)), 100+i*10)
+		m.Set(key, strings.Repeat("v", 300+i*1000))
+	}
+	m.Freeze()
+
+	file := memfs.NewFile()
+	w := NewSnapshotWriter[string, string](testStringCodec{}, testStringCodec{}, SnapshotOptions{})
+	_, err := w.Write(file, m)
+	require.NoError(t, err)
+
+	s, err := OpenSnapshot[string, string](file, strings.Compare, testStringCodec{}, testStringCodec{},
+		SnapshotOptions{})
+	require.NoError(t, err)
+	defer s.Free()
+	require.NoError(t, s.Verify())
+
+	for key, value := range m.All() {
+		value1, ok := s.Get(key)
+		require.True(t, ok)
+		assert.Equal(t, value.Unwrap(), value1.Unwrap())
+	}
+}
+
+func TestSnapshot_Get__should_panic_when_block_corrupted(t *testing.T) {
+	file := testSnapshotFile(t, SnapshotOptions{}, testItemsN(10)...)
+	data := testSnapshotData(t, file)
+	data[0] ^= 0xff
+
+	s := testOpenSnapshot(t, file, SnapshotOptions{})
+	defer s.Free()
+
+	assert.Panics(t, func() {
+		s.Get(5)
+	})
+}
+
+// Rank
+
+func TestSnapshot_Rank__should_return_key_index(t *testing.T) {
+	items := testEvenItems(1000)
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 256}, items...)
+	defer s.Free()
+
+	assert.Equal(t, int64(0), s.Rank(-1))
+	assert.Equal(t, int64(0), s.Rank(0))
+	assert.Equal(t, int64(1), s.Rank(1))
+	assert.Equal(t, int64(500), s.Rank(1000))
+	assert.Equal(t, int64(501), s.Rank(1001))
+	assert.Equal(t, int64(1000), s.Rank(1999))
+	assert.Equal(t, int64(1000), s.Rank(10000))
+}
+
+// Verify
+
+func TestSnapshot_Verify__should_mark_blocks_verified(t *testing.T) {
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 128}, testItemsN(100)...)
+	defer s.Free()
+	require.NoError(t, s.Verify())
+
+	s1 := s.(*snapshot[int, *Value])
+	require.Greater(t, len(s1.verified), 1)
+	for b := range s1.verified {
+		assert.True(t, s1.verified[b].Load(), "block %d", b)
+	}
+}
+
+func TestSnapshot_Verify__should_return_error_when_block_corrupted(t *testing.T) {
+	file := testSnapshotFile(t, SnapshotOptions{BlockSize: 128}, testItemsN(100)...)
+	data := testSnapshotData(t, file)
+
+	s := testOpenSnapshot(t, file, SnapshotOptions{})
+	defer s.Free()
+	require.NoError(t, s.Verify())
+
+	block := s.(*snapshot[int, *Value]).blocks[3]
+	data[block.offset+1] ^= 0xff
+
+	err := s.Verify()
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+// Iterator
+
+func TestSnapshot_Iterator__should_iterate_items(t *testing.T) {
+	items := testEvenItems(1000)
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 256}, items...)
+	defer s.Free()
+
+	it := s.Iterator()
+	defer it.Free()
+
+	keys := testSnapshotKeys(it, false)
+	assert.Equal(t, testItemKeys(items), keys)
+}
+
+func TestSnapshot_Iterator__should_iterate_backward(t *testing.T) {
+	items := testEvenItems(1000)
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 256}, items...)
+	defer s.Free()
+
+	it := s.Iterator()
+	defer it.Free()
+
+	it.SeekToEnd()
+	keys := testSnapshotKeys(it, true)
+
+	expected := testItemKeys(items)
+	for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+		expected[i], expected[j] = expected[j], expected[i]
+	}
+	assert.Equal(t, expected, keys)
+}
+
+func TestSnapshot_Iterator__should_seek_as_map_iterator(t *testing.T) {
+	items := testEvenItems(1000)
+	btree := testBtree(t, items...)
+	defer btree.Free()
+
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 256}, items...)
+	defer s.Free()
+
+	it0 := btree.Iterator()
+	defer it0.Free()
+
+	it1 := s.Iterator()
+	defer it1.Free()
+
+	for _, key := range []int{-1, 0, 1, 2, 255, 256, 998, 999, 1000, 1997, 1998, 1999, 5000} {
+		assert.Equal(t, it0.SeekBefore(key), it1.SeekBefore(key), key)
+		assert.Equal(t, testSnapshotKeysN(it0, 3, false), testSnapshotKeysN(it1, 3, false), key)
+
+		assert.Equal(t, it0.SeekBefore(key), it1.SeekBefore(key), key)
+		assert.Equal(t, testSnapshotKeysN(it0, 3, true), testSnapshotKeysN(it1, 3, true), key)
+
+		assert.Equal(t, it0.SeekAfter(key), it1.SeekAfter(key), key)
+		assert.Equal(t, testSnapshotKeysN(it0, 3, false), testSnapshotKeysN(it1, 3, false), key)
+
+		assert.Equal(t, it0.SeekTo(key), it1.SeekTo(key), key)
+		assert.Equal(t, it0.Key(), it1.Key(), key)
+	}
+}
+
+func TestSnapshot_Iterator__should_seek_index(t *testing.T) {
+	items := testEvenItems(1000)
+	s := testSnapshot(t, SnapshotOptions{BlockSize: 256}, items...)
+	defer s.Free()
+
+	it := s.Iterator()
+	defer it.Free()
+
+	for _, index := range []int64{0, 1, 100, 500, 999} {
+		require.True(t, it.SeekIndex(index))
+		require.True(t, it.Next())
+		assert.Equal(t, int(index*2), it.Key())
+		assert.Equal(t, int(index*2), it.Value().Unwrap().val)
+	}
+
+	assert.False(t, it.SeekIndex(-1))
+	assert.False(t, it.SeekIndex(1000))
+	assert.False(t, it.Next())
+}
+
+func TestSnapshot_Iterator__should_release_values_on_move(t *testing.T) {
+	s := testSnapshot(t, SnapshotOptions{}, testItemsN(10)...)
+	defer s.Free()
+
+	it := s.Iterator()
+
+	require.True(t, it.Next())
+	value := it.Value().Unwrap()
+	assert.Same(t, value, it.Value().Unwrap())
+
+	require.True(t, it.Next())
+	assert.True(t, value.freed)
+
+	value = it.Value().Unwrap()
+	it.Free()
+	assert.True(t, value.freed)
+}
+
+// private
+
+func testItemKeys(items []Item[int, *Value]) []int {
+	keys := make([]int, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
+
+func testSnapshotKeys(it Iterator[int, *Value], backward bool) []int {
+	return testSnapshotKeysN(it, -1, backward)
+}
+
+func testSnapshotKeysN(it Iterator[int, *Value], n int, backward bool) []int {
+	keys := []int{}
+	for len(keys) != n {
+		ok := false
+		if backward {
+			ok = it.Previous()
+		} else {
+			ok = it.Next()
+		}
+		if !ok {
+			break
+		}
+		keys = append(keys, it.Key())
+	}
+	return keys
+}
+
+// test codecs
+
+var errTestCodec = errors.New("test codec error")
+
+type testIntCodec struct{}
+
+func (testIntCodec) Append(b []byte, v int) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(b, uint64(v)), nil
+}
+
+func (testIntCodec) Decode(b []byte) (int, error) {
+	if len(b) != 8 {
+		return 0, errors.New("invalid int")
+	}
+	return int(binary.BigEndian.Uint64(b)), nil
+}
+
+type testValueCodec struct{}
+
+func (testValueCodec) Append(b []byte, v *Value) ([]byte, error) {
+	return binary.AppendVarint(b, int64(v.val)), nil
+}
+
+func (testValueCodec) Decode(b []byte) (*Value, error) {
+	v, n := binary.Varint(b)
+	if n != len(b) {
+		return nil, errors.New("invalid value")
+	}
+	return &Value{val: int(v)}, nil
+}
+
+type testStringCodec struct{}
+
+func (testStringCodec) Append(b []byte, v string) ([]byte, error) {
+	return append(b, v...), nil
+}
+
+func (testStringCodec) Decode(b []byte) (string, error) {
+	return string(b), nil
+}
+
+type testErrorCodec struct{}
+
+func (testErrorCodec) Append(b []byte, v *Value) ([]byte, error) {
+	return nil, errTestCodec
+}
+
+func (testErrorCodec) Decode(b []byte) (*Value, error) {
+	return nil, errTestCodec
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refmap
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/basecomplextech/baselibrary/crypto/xxh3"
+	"github.com/basecomplextech/baselibrary/encoding/compactint"
+)
+
+// SnapshotWriter writes frozen maps as snapshots, see Snapshot for the format.
+//
+// The writer reuses internal buffers and must not be used concurrently.
+type SnapshotWriter[K, V any] interface {
+	// Write writes a frozen map snapshot, and returns the number of written bytes,
+	// panics when the map is mutable.
+	Write(w io.Writer, m Map[K, V]) (int64, error)
+}
+
+// NewSnapshotWriter returns a new snapshot writer.
+func NewSnapshotWriter[K, V any](keys Codec[K], values Codec[V], opts SnapshotOptions,
+) SnapshotWriter[K, V] {
+	return newSnapshotWriter(keys, values, opts)
+}
+
+// internal
+
+var _ SnapshotWriter[any, any] = (*snapshotWriter[any, any])(nil)
+
+type snapshotWriter[K, V any] struct {
+	keys   Codec[K]
+	values Codec[V]
+	opts   SnapshotOptions
+
+	w      io.Writer
+	offset int64
+
+	buf     []byte   // encoded key or value
+	block   []byte   // current block entries
+	offsets []uint32 // current block entry offsets
+	first   []byte   // current block first key
+	index   []byte   // index items
+	blocks  int      // number of written blocks
+}
+
+func newSnapshotWriter[K, V any](keys Codec[K], values Codec[V], opts SnapshotOptions,
+) *snapshotWriter[K, V] {
+	if opts.BlockSize <= 0 {
+		opts.BlockSize = snapshotBlockSize
+	}
+
+	return &snapshotWriter[K, V]{
+		keys:   keys,
+		values: values,
+		opts:   opts,
+	}
+}
+
+// Write writes a frozen map snapshot, and returns the number of written bytes,
+// panics when the map is mutable.
+func (w *snapshotWriter[K, V]) Write(dst io.Writer, m Map[K, V]) (int64, error) {
+	if m.Mutable() {
+		panic("cannot snapshot mutable refmap")
+	}
+
+	w.reset(dst)
+	defer w.reset(nil)
+
+	// Write blocks
+	for key, value := range m.All() {
+		if err := w.add(key, value.Unwrap()); err != nil {
+			return w.offset, err
+		}
+	}
+	if err := w.flushBlock(); err != nil {
+		return w.offset, err
+	}
+
+	// Write index and footer
+	indexOffset := w.offset
+	if err := w.writeIndex(); err != nil {
+		return w.offset, err
+	}
+	indexSize := w.offset - indexOffset
+
+	if err := w.writeFooter(indexOffset, indexSize, m.Length()); err != nil {
+		return w.offset, err
+	}
+	return w.offset, nil
+}
+
+// private
+
+func (w *snapshotWriter[K, V]) reset(dst io.Writer) {
+	w.w = dst
+	w.offset = 0
+
+	w.block = w.block[:0]
+	w.offsets = w.offsets[:0]
+	w.first = w.first[:0]
+	w.index = w.index[:0]
+	w.blocks = 0
+}
+
+// add appends an item to the current block, and flushes the block when full.
+func (w *snapshotWriter[K, V]) add(key K, value V) (err error) {
+	off := len(w.block)
+	w.offsets = append(w.offsets, uint32(off))
+
+	// Append key
+	w.buf, err = w.keys.Append(w.buf[:0], key)
+	if err != nil {
+		return err
+	}
+	w.block = appendSnapshotBytes(w.block, w.buf)
+
+	// Copy first key
+	if off == 0 {
+		w.first = append(w.first[:0], w.buf...)
+	}
+
+	// Append value
+	w.buf, err = w.values.Append(w.buf[:0], value)
+	if err != nil {
+		return err
+	}
+	w.block = appendSnapshotBytes(w.block, w.buf)
+
+	// Flush when full
+	if len(w.block)+len(w.offsets)*snapshotOffsetSize < w.opts.BlockSize {
+		return nil
+	}
+	return w.flushBlock()
+}
+
+// flushBlock writes the current block and appends its index item.
+func (w *snapshotWriter[K, V]) flushBlock() error {
+	count := len(w.offsets)
+	if count == 0 {
+		return nil
+	}
+
+	// Append offsets and checksum
+	b := w.block
+	for _, off := range w.offsets {
+		b = binary.BigEndian.AppendUint32(b, off)
+	}
+	b = binary.BigEndian.AppendUint64(b, xxh3.Sum64(b))
+	w.block = b
+
+	// Append index item
+	w.index = appendSnapshotUint(w.index, uint64(len(w.first)))
+	w.index = append(w.index, w.first...)
+	w.index = appendSnapshotUint(w.index, uint64(w.offset))
+	w.index = appendSnapshotUint(w.index, uint64(len(b)))
+	w.index = appendSnapshotUint(w.index, uint64(count))
+	w.blocks++
+
+	// Write block
+	if err := w.write(b); err != nil {
+		return err
+	}
+
+	w.block = w.block[:0]
+	w.offsets = w.offsets[:0]
+	return nil
+}
+
+// writeIndex writes the index, reuses the block buffer.
+func (w *snapshotWriter[K, V]) writeIndex() error {
+	b := appendSnapshotUint(w.block[:0], uint64(w.blocks))
+	b = append(b, w.index...)
+	b = binary.BigEndian.AppendUint64(b, xxh3.Sum64(b))
+	w.block = b
+
+	return w.write(b)
+}
+
+func (w *snapshotWriter[K, V]) writeFooter(indexOffset int64, indexSize int64, length int64) error {
+	b := w.block[:0]
+	b = binary.BigEndian.AppendUint64(b, uint64(indexOffset))
+	b = binary.BigEndian.AppendUint64(b, uint64(indexSize))
+	b = binary.BigEndian.AppendUint64(b, uint64(length))
+	b = binary.BigEndian.AppendUint64(b, xxh3.Sum64(b))
+	b = append(b, snapshotMagic...)
+	w.block = b
+
+	return w.write(b)
+}
+
+func (w *snapshotWriter[K, V]) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// util
+
+func appendSnapshotUint(b []byte, v uint64) []byte {
+	var buf [compactint.MaxLen64]byte
+	n := compactint.PutUint64(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendSnapshotBytes(b []byte, v []byte) []byte {
+	b = appendSnapshotUint(b, uint64(len(v)))
+	return append(b, v...)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+import (
+	"testing"
+	"time"
+)
+
+const benchVectorSize = 100_000
+
+func BenchmarkVector_AppendRetain(b *testing.B) {
+	v := testVector(b)
+	values := testValues(benchVectorSize)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	j := 0
+	for i := 0; i < b.N; i++ {
+		if j == len(values) {
+			v.Free()
+			v = testVector(b)
+			j = 0
+		}
+
+		v.AppendRetain(values[j])
+		j++
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkVector_SetRetain(b *testing.B) {
+	values := testValues(benchVectorSize)
+	v := testVector(b, values...)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		j := i % len(values)
+		v.SetRetain(j, values[j])
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkVector_Get(b *testing.B) {
+	values := testValues(benchVectorSize)
+	v := testVector(b, values...)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		j := i % len(values)
+		v.Get(j)
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkVector_Iterate(b *testing.B) {
+	values := testValues(benchVectorSize)
+	v := testVector(b, values...)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	it := v.Iterator()
+	for i := 0; i < b.N; i++ {
+		if !it.Next() {
+			it.SeekToStart()
+			it.Next()
+		}
+		it.Value()
+	}
+	it.Free()
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkVector_CloneSet(b *testing.B) {
+	values := testValues(benchVectorSize)
+	v := testVector(b, values...)
+	v.Freeze()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	t0 := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		j := i % len(values)
+
+		v1 := v.Clone()
+		v1.SetRetain(j, values[j])
+		v1.Free()
+	}
+
+	sec := time.Since(t0).Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
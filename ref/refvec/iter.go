@@ -0,0 +1,228 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+import (
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// Iterator sequentially iterates over vector items.
+//
+// Usage:
+//
+//	it := vector.Iterator()
+//	defer it.Free()
+//
+//	it.SeekToStart()
+//
+//	for it.Next() {
+//		index := it.Index()
+//		value := it.Value()
+//	}
+type Iterator[V any] interface {
+	// OK returns true when the iterator points to a valid item, or false on end.
+	OK() bool
+
+	// Index returns the current index, or -1 when the iterator does not point to an item.
+	Index() int
+
+	// Value returns the current value or nil, the value is valid until the next iteration.
+	Value() ref.R[V]
+
+	// Iterating
+
+	// Next moves to the next item.
+	Next() bool
+
+	// Previous moves to the previous item.
+	Previous() bool
+
+	// Seeking
+
+	// SeekToStart positions the iterator at the start.
+	SeekToStart() bool
+
+	// SeekToEnd positions the iterator at the end.
+	SeekToEnd() bool
+
+	// SeekIndex positions the iterator before an item with an index,
+	// and returns true if the index is in range.
+	SeekIndex(index int) bool
+
+	// Internal
+
+	// Free frees the iterator, implements the ref.Free interface.
+	Free()
+}
+
+// internal
+
+var _ Iterator[any] = (*iterator[any])(nil)
+
+type iterator[V any] struct {
+	*iterState[V]
+}
+
+type iterState[V any] struct {
+	vec *vector[V]
+	mod int // track concurrent modifications
+
+	index int          // current item, or the next item when not ok
+	ok    bool         // index points to an item
+	leaf  *leafNode[V] // cached leaf, nil when not loaded
+	base  int          // cached leaf absolute position
+}
+
+func newIterator[V any](vec *vector[V]) *iterator[V] {
+	it := &iterator[V]{acquireIterState[V]()}
+	it.vec = vec
+	it.mod = vec.mod
+	return it
+}
+
+func (s *iterState[V]) reset() {
+	*s = iterState[V]{}
+}
+
+// OK returns true when the iterator points to a valid item, or false on end.
+func (it *iterator[V]) OK() bool {
+	return it.ok
+}
+
+// Index returns the current index, or -1 when the iterator does not point to an item.
+func (it *iterator[V]) Index() int {
+	if !it.ok {
+		return -1
+	}
+	return it.index
+}
+
+// Value returns the current value or nil, the value is valid until the next iteration.
+func (it *iterator[V]) Value() ref.R[V] {
+	if !it.ok {
+		return nil
+	}
+
+	p := it.vec.start + it.index
+	leaf := it.loadLeaf(p)
+	return leaf.get(p & mask)
+}
+
+// Iterating
+
+// Next moves to the next item.
+func (it *iterator[V]) Next() bool {
+	it.checkMod()
+
+	next := it.index
+	if it.ok {
+		next++
+	}
+
+	n := it.vec.Length()
+	if next >= n {
+		it.index = n
+		it.ok = false
+		return false
+	}
+
+	it.index = next
+	it.ok = true
+	return true
+}
+
+// Previous moves to the previous item.
+func (it *iterator[V]) Previous() bool {
+	it.checkMod()
+
+	prev := it.index - 1
+	if prev < 0 {
+		it.index = 0
+		it.ok = false
+		return false
+	}
+
+	it.index = prev
+	it.ok = true
+	return true
+}
+
+// Seeking
+
+// SeekToStart positions the iterator at the start.
+func (it *iterator[V]) SeekToStart() bool {
+	it.seek(0)
+	return true
+}
+
+// SeekToEnd positions the iterator at the end.
+func (it *iterator[V]) SeekToEnd() bool {
+	it.seek(it.vec.Length())
+	return true
+}
+
+// SeekIndex positions the iterator before an item with an index,
+// and returns true if the index is in range.
+func (it *iterator[V]) SeekIndex(index int) bool {
+	n := it.vec.Length()
+	it.seek(max(0, min(index, n)))
+	return index >= 0 && index < n
+}
+
+// Internal
+
+// Free frees the iterator, implements the ref.Free interface.
+func (it *iterator[V]) Free() {
+	state := it.iterState
+	it.iterState = nil
+	releaseIterState(state)
+}
+
+// private
+
+// seek positions the iterator before an item with an index.
+func (it *iterator[V]) seek(index int) {
+	it.index = index
+	it.ok = false
+
+	it.mod = it.vec.mod
+	it.leaf = nil
+}
+
+func (it *iterator[V]) checkMod() {
+	if it.mod != it.vec.mod {
+		panic("refvec concurrent modification error")
+	}
+}
+
+// loadLeaf returns a cached leaf or loads a leaf which contains an absolute position.
+func (it *iterator[V]) loadLeaf(p int) *leafNode[V] {
+	base := p &^ mask
+	if it.leaf != nil && it.base == base {
+		return it.leaf
+	}
+
+	it.leaf = it.vec.leaf(p)
+	it.base = base
+	return it.leaf
+}
+
+// state pool
+
+var iterStatePools = pools.NewPools()
+
+func acquireIterState[V any]() *iterState[V] {
+	v, ok := pools.Acquire[*iterState[V]](iterStatePools)
+	if ok {
+		return v
+	}
+	return &iterState[V]{}
+}
+
+func releaseIterState[V any](s *iterState[V]) {
+	s.reset()
+	pools.Release(iterStatePools, s)
+}
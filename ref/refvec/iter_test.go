@@ -0,0 +1,159 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testIterate(it Iterator[*Value]) []int {
+	var result []int
+	for it.Next() {
+		result = append(result, it.Value().Unwrap().val)
+	}
+	return result
+}
+
+func testIterateBackward(it Iterator[*Value]) []int {
+	var result []int
+	for it.Previous() {
+		result = append(result, it.Value().Unwrap().val)
+	}
+	return result
+}
+
+func testReverse(s []int) []int {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+	return s
+}
+
+// Next
+
+func TestIterator_Next__should_iterate_items(t *testing.T) {
+	n := width*width + 10
+	v := testVector(t, testValues(n)...)
+	defer v.Free()
+
+	it := v.Iterator()
+	defer it.Free()
+
+	assert.Equal(t, testRange(0, n), testIterate(it))
+	assert.False(t, it.OK())
+	assert.Equal(t, -1, it.Index())
+	assert.Nil(t, it.Value())
+}
+
+func TestIterator_Next__should_return_false_when_empty(t *testing.T) {
+	v := testVector(t)
+	defer v.Free()
+
+	it := v.Iterator()
+	defer it.Free()
+
+	assert.False(t, it.Next())
+	assert.False(t, it.Previous())
+}
+
+func TestIterator_Next__should_panic_on_concurrent_modification(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+
+	it := v.Iterator()
+	defer it.Free()
+
+	it.Next()
+	v.AppendRetain(testValue(10))
+
+	assert.Panics(t, func() {
+		it.Next()
+	})
+}
+
+// Previous
+
+func TestIterator_Previous__should_iterate_items_in_reverse_order(t *testing.T) {
+	n := width*width + 10
+	v := testVector(t, testValues(n)...)
+	defer v.Free()
+
+	it := v.Iterator()
+	defer it.Free()
+
+	it.SeekToEnd()
+	assert.Equal(t, testReverse(testRange(0, n)), testIterateBackward(it))
+	assert.Equal(t, -1, it.Index())
+}
+
+func TestIterator_Previous__should_change_direction(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+
+	it := v.Iterator()
+	defer it.Free()
+
+	it.Next()
+	it.Next()
+	it.Next()
+	assert.Equal(t, 2, it.Index())
+
+	it.Previous()
+	assert.Equal(t, 1, it.Index())
+	assert.Equal(t, 1, it.Value().Unwrap().val)
+}
+
+// SeekIndex
+
+func TestIterator_SeekIndex__should_position_iterator_before_item(t *testing.T) {
+	n := width * 3
+	v := testVector(t, testValues(n)...)
+	defer v.Free()
+
+	it := v.Iterator()
+	defer it.Free()
+
+	ok := it.SeekIndex(40)
+	assert.True(t, ok)
+	assert.Equal(t, testRange(40, n), testIterate(it))
+
+	ok = it.SeekIndex(40)
+	assert.True(t, ok)
+	assert.Equal(t, testReverse(testRange(0, 40)), testIterateBackward(it))
+}
+
+func TestIterator_SeekIndex__should_return_false_when_out_of_range(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+
+	it := v.Iterator()
+	defer it.Free()
+
+	assert.False(t, it.SeekIndex(10))
+	assert.False(t, it.Next())
+	assert.True(t, it.Previous())
+	assert.Equal(t, 9, it.Index())
+
+	assert.False(t, it.SeekIndex(-1))
+	assert.True(t, it.Next())
+	assert.Equal(t, 0, it.Index())
+}
+
+func TestIterator_SeekIndex__should_iterate_slice(t *testing.T) {
+	v := testVector(t, testValues(width*4)...)
+	defer v.Free()
+	v.Freeze()
+
+	v1 := v.Slice(10, 100)
+	defer v1.Free()
+
+	it := v1.Iterator()
+	defer it.Free()
+
+	it.SeekIndex(50)
+	assert.Equal(t, testRange(60, 100), testIterate(it))
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+const (
+	bits  = 5
+	width = 1 << bits
+	mask  = width - 1
+)
+
+type node[V any] interface {
+	retain()
+	release()
+	refcount() int64
+
+	clone() node[V]
+	freeze()
+	mutable() bool
+}
+
+// newNode returns a new mutable node, a leaf when shift is zero, or a branch otherwise.
+func newNode[V any](shift uint) node[V] {
+	if shift == 0 {
+		return newLeafNode[V]()
+	}
+	return newBranchNode[V]()
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+import (
+	"sync/atomic"
+
+	"github.com/basecomplextech/baselibrary/pools"
+)
+
+var _ node[any] = (*branchNode[any])(nil)
+
+type branchNode[V any] struct {
+	children [width]node[V] // nil when empty
+
+	mut  bool
+	refs int64
+}
+
+// newBranchNode returns a new mutable node, does not retain the children.
+func newBranchNode[V any](children ...node[V]) *branchNode[V] {
+	n := acquireBranch[V]()
+	n.mut = true
+	n.refs = 1
+
+	copy(n.children[:], children)
+	return n
+}
+
+// cloneBranchNode returns a mutable node clone.
+func cloneBranchNode[V any](n *branchNode[V]) *branchNode[V] {
+	n1 := acquireBranch[V]()
+	n1.mut = true
+	n1.refs = 1
+	n1.children = n.children
+
+	// Retain children
+	for _, child := range n1.children {
+		if child != nil {
+			child.retain()
+		}
+	}
+	return n1
+}
+
+// state
+
+func (n *branchNode[V]) reset() {
+	*n = branchNode[V]{}
+}
+
+// retain/release
+
+func (n *branchNode[V]) retain() {
+	v := atomic.AddInt64(&n.refs, 1)
+	if v == 1 {
+		panic("retained already released node")
+	}
+}
+
+func (n *branchNode[V]) release() {
+	v := atomic.AddInt64(&n.refs, -1)
+	switch {
+	case v < 0:
+		panic("released already released node")
+	case v > 0:
+		return
+	}
+
+	// Release children
+	for _, child := range n.children {
+		if child != nil {
+			child.release()
+		}
+	}
+
+	// Release node
+	releaseBranch[V](n)
+}
+
+func (n *branchNode[V]) refcount() int64 {
+	return n.refs
+}
+
+// mutate
+
+func (n *branchNode[V]) clone() node[V] {
+	return cloneBranchNode(n)
+}
+
+func (n *branchNode[V]) freeze() {
+	if !n.mut {
+		return
+	}
+
+	for _, child := range n.children {
+		if child != nil {
+			child.freeze()
+		}
+	}
+
+	n.mut = false
+}
+
+func (n *branchNode[V]) mutable() bool {
+	return n.mut
+}
+
+// methods
+
+// child returns a child node at index or nil.
+func (n *branchNode[V]) child(index int) node[V] {
+	return n.children[index]
+}
+
+// mutateChild returns a mutable child at index, creates it when absent,
+// or clones and replaces it when immutable.
+func (n *branchNode[V]) mutateChild(index int, shift uint) node[V] {
+	if !n.mut {
+		panic("operation on immutable node")
+	}
+
+	child := n.children[index]
+	switch {
+	case child == nil:
+		child = newNode[V](shift)
+	case !child.mutable():
+		prev := child
+		child = prev.clone()
+		prev.release()
+	default:
+		return child
+	}
+
+	n.children[index] = child
+	return child
+}
+
+// branch pool
+
+var branchNodePools = pools.NewPools()
+
+func acquireBranch[V any]() *branchNode[V] {
+	v, ok := pools.Acquire[*branchNode[V]](branchNodePools)
+	if ok {
+		return v
+	}
+	return &branchNode[V]{}
+}
+
+func releaseBranch[V any](n *branchNode[V]) {
+	n.reset()
+	pools.Release(branchNodePools, n)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+import (
+	"sync/atomic"
+
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+var _ node[any] = (*leafNode[any])(nil)
+
+type leafNode[V any] struct {
+	values [width]ref.R[V] // nil when empty
+
+	mut  bool
+	refs int64
+}
+
+// newLeafNode returns a new mutable node.
+func newLeafNode[V any]() *leafNode[V] {
+	n := acquireLeaf[V]()
+	n.mut = true
+	n.refs = 1
+	return n
+}
+
+// cloneLeafNode returns a mutable node clone.
+func cloneLeafNode[V any](n *leafNode[V]) *leafNode[V] {
+	n1 := acquireLeaf[V]()
+	n1.mut = true
+	n1.refs = 1
+	n1.values = n.values
+
+	// Retain values
+	for _, value := range n1.values {
+		if value != nil {
+			value.Retain()
+		}
+	}
+	return n1
+}
+
+// state
+
+func (n *leafNode[V]) reset() {
+	*n = leafNode[V]{}
+}
+
+// retain/release
+
+func (n *leafNode[V]) retain() {
+	v := atomic.AddInt64(&n.refs, 1)
+	if v == 1 {
+		panic("retained already released node")
+	}
+}
+
+func (n *leafNode[V]) release() {
+	v := atomic.AddInt64(&n.refs, -1)
+	switch {
+	case v < 0:
+		panic("released already released node")
+	case v > 0:
+		return
+	}
+
+	// Release values
+	for _, value := range n.values {
+		if value != nil {
+			value.Release()
+		}
+	}
+
+	// Release node
+	releaseLeaf[V](n)
+}
+
+func (n *leafNode[V]) refcount() int64 {
+	return n.refs
+}
+
+// mutate
+
+func (n *leafNode[V]) clone() node[V] {
+	return cloneLeafNode(n)
+}
+
+func (n *leafNode[V]) freeze() {
+	n.mut = false
+}
+
+func (n *leafNode[V]) mutable() bool {
+	return n.mut
+}
+
+// methods
+
+func (n *leafNode[V]) get(index int) ref.R[V] {
+	return n.values[index]
+}
+
+// put replaces a value at index, retains the new value and releases the previous one.
+func (n *leafNode[V]) put(index int, value ref.R[V]) {
+	if !n.mut {
+		panic("operation on immutable node")
+	}
+
+	prev := n.values[index]
+	n.values[index] = value
+	value.Retain()
+
+	if prev != nil {
+		prev.Release()
+	}
+}
+
+// leaf pool
+
+var leafNodePools = pools.NewPools()
+
+func acquireLeaf[V any]() *leafNode[V] {
+	v, ok := pools.Acquire[*leafNode[V]](leafNodePools)
+	if ok {
+		return v
+	}
+	return &leafNode[V]{}
+}
+
+func releaseLeaf[V any](n *leafNode[V]) {
+	n.reset()
+	pools.Release(leafNodePools, n)
+}
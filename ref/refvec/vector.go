@@ -0,0 +1,429 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/basecomplextech/baselibrary/pools"
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// Vector is an immutable indexed sequence which stores countable references,
+// implemented as a 32-way bit-partitioned trie.
+//
+// The vector retains/releases references internally, but does not retain them when iterating
+// or returning. Clones and slices share unmodified nodes with the original vector.
+type Vector[V any] interface {
+	// Empty returns true if the vector is empty.
+	Empty() bool
+
+	// Length returns the number of items in this vector.
+	Length() int
+
+	// Mutable returns true if the vector is mutable.
+	Mutable() bool
+
+	// Clone
+
+	// Clone returns a mutable clone of the vector.
+	Clone() Vector[V]
+
+	// Freeze makes the vector immutable.
+	Freeze()
+
+	// Slice returns a mutable vector of items in [start, end), panics when the vector is mutable.
+	//
+	// The slice shares nodes with the vector, so the values outside of the slice
+	// are not released until the slice is freed.
+	Slice(start int, end int) Vector[V]
+
+	// Read
+
+	// Get returns an item by an index, does not retain the value, panics when out of range.
+	Get(index int) ref.R[V]
+
+	// Iterator returns an iterator, the iterator does not retain the values.
+	Iterator() Iterator[V]
+
+	// Values returns all values, does not retain them.
+	Values() []ref.R[V]
+
+	// Sequences
+
+	// All returns a sequence of all items in ascending order, does not retain the values.
+	// The vector must not be modified during iteration.
+	All() iter.Seq2[int, ref.R[V]]
+
+	// Backward returns a sequence of all items in descending order, does not retain the values.
+	// The vector must not be modified during iteration.
+	Backward() iter.Seq2[int, ref.R[V]]
+
+	// Write
+
+	// Append appends an item to the vector, wraps it into a reference.
+	Append(value V)
+
+	// AppendRetain appends an item reference to the vector, and retains it.
+	AppendRetain(value ref.R[V])
+
+	// AppendNoRetain appends an item reference to the vector, does not retain it.
+	AppendNoRetain(value ref.R[V])
+
+	// Set replaces an item by an index, wraps it into a reference, panics when out of range.
+	Set(index int, value V)
+
+	// SetRetain replaces an item reference by an index, retains the new value and releases
+	// the previous one, panics when out of range.
+	SetRetain(index int, value ref.R[V])
+
+	// SetNoRetain replaces an item reference by an index, does not retain the new value,
+	// releases the previous one, panics when out of range.
+	SetNoRetain(index int, value ref.R[V])
+
+	// Internal
+
+	// Free frees the vector, releases all values.
+	Free()
+}
+
+// New returns an empty vector.
+func New[V any](mutable bool) Vector[V] {
+	v := newVector[V]()
+	if !mutable {
+		v.Freeze()
+	}
+	return v
+}
+
+// NewRef returns an empty vector wrapped in a ref.
+func NewRef[V any](mutable bool) ref.R[Vector[V]] {
+	v := New[V](mutable)
+	return ref.New(v)
+}
+
+// internal
+
+var _ Vector[any] = (*vector[any])(nil)
+
+type vector[V any] struct {
+	*state[V]
+}
+
+// state stores items at absolute positions [start, end) in the trie.
+type state[V any] struct {
+	root  node[V]
+	shift uint // root level shift, zero when root is a leaf
+	start int
+	end   int
+
+	mod     int // track concurrent modifications
+	mutable bool
+}
+
+func newVector[V any]() *vector[V] {
+	v := &vector[V]{acquireState[V]()}
+	v.root = newLeafNode[V]()
+	v.mutable = true
+	return v
+}
+
+func (s *state[V]) reset() {
+	*s = state[V]{}
+}
+
+// Empty returns true if the vector is empty.
+func (v *vector[V]) Empty() bool {
+	return v.end == v.start
+}
+
+// Length returns the number of items in this vector.
+func (v *vector[V]) Length() int {
+	return v.end - v.start
+}
+
+// Mutable returns true if the vector is mutable.
+func (v *vector[V]) Mutable() bool {
+	return v.mutable
+}
+
+// Clone
+
+// Clone returns a mutable clone of the vector.
+func (v *vector[V]) Clone() Vector[V] {
+	if v.mutable {
+		panic("cannot clone mutable refvec")
+	}
+
+	v1 := &vector[V]{acquireState[V]()}
+	v1.root = v.root.clone()
+	v1.shift = v.shift
+	v1.start = v.start
+	v1.end = v.end
+	v1.mutable = true
+	return v1
+}
+
+// Freeze makes the vector immutable.
+func (v *vector[V]) Freeze() {
+	if !v.mutable {
+		return
+	}
+
+	v.mutable = false
+	v.root.freeze()
+}
+
+// Slice returns a mutable vector of items in [start, end), panics when the vector is mutable.
+func (v *vector[V]) Slice(start int, end int) Vector[V] {
+	if v.mutable {
+		panic("cannot slice mutable refvec")
+	}
+	if start < 0 || end < start || end > v.Length() {
+		panic(fmt.Sprintf("refvec slice bounds out of range [%d:%d] with length %d",
+			start, end, v.Length()))
+	}
+	if start == end {
+		return New[V](true)
+	}
+
+	start += v.start
+	end += v.start
+
+	// Descend while the slice is inside a single child
+	root := v.root
+	shift := v.shift
+	for shift > 0 {
+		i := start >> shift
+		if i != (end-1)>>shift {
+			break
+		}
+
+		root = root.(*branchNode[V]).child(i)
+		start -= i << shift
+		end -= i << shift
+		shift -= bits
+	}
+
+	root.retain()
+
+	v1 := &vector[V]{acquireState[V]()}
+	v1.root = root
+	v1.shift = shift
+	v1.start = start
+	v1.end = end
+	v1.mutable = true
+	return v1
+}
+
+// Read
+
+// Get returns an item by an index, does not retain the value, panics when out of range.
+func (v *vector[V]) Get(index int) ref.R[V] {
+	v.checkIndex(index)
+
+	leaf := v.leaf(v.start + index)
+	return leaf.get((v.start + index) & mask)
+}
+
+// Iterator returns an iterator, the iterator does not retain the values.
+func (v *vector[V]) Iterator() Iterator[V] {
+	it := newIterator(v)
+	it.SeekToStart()
+	return it
+}
+
+// Values returns all values, does not retain them.
+func (v *vector[V]) Values() []ref.R[V] {
+	n := v.Length()
+	if n == 0 {
+		return nil
+	}
+
+	result := make([]ref.R[V], 0, n)
+	for _, value := range v.All() {
+		result = append(result, value)
+	}
+	return result
+}
+
+// Sequences
+
+// All returns a sequence of all items in ascending order, does not retain the values.
+func (v *vector[V]) All() iter.Seq2[int, ref.R[V]] {
+	return func(yield func(int, ref.R[V]) bool) {
+		it := newIterator(v)
+		defer it.Free()
+
+		for it.Next() {
+			if !yield(it.Index(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns a sequence of all items in descending order, does not retain the values.
+func (v *vector[V]) Backward() iter.Seq2[int, ref.R[V]] {
+	return func(yield func(int, ref.R[V]) bool) {
+		it := newIterator(v)
+		defer it.Free()
+
+		it.SeekToEnd()
+		for it.Previous() {
+			if !yield(it.Index(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Write
+
+// Append appends an item to the vector, wraps it into a reference.
+func (v *vector[V]) Append(value V) {
+	r := newRef(value)
+	v.AppendRetain(r)
+	r.Release()
+}
+
+// AppendRetain appends an item reference to the vector, and retains it.
+func (v *vector[V]) AppendRetain(value ref.R[V]) {
+	if !v.mutable {
+		panic("operation on immutable refvec")
+	}
+
+	// Grow if full
+	if v.end == width<<v.shift {
+		v.root = newBranchNode(v.root)
+		v.shift += bits
+	}
+
+	// Put value
+	leaf := v.mutateLeaf(v.end)
+	leaf.put(v.end&mask, value)
+
+	// Increment length
+	v.mod++
+	v.end++
+}
+
+// AppendNoRetain appends an item reference to the vector, does not retain it.
+func (v *vector[V]) AppendNoRetain(value ref.R[V]) {
+	v.AppendRetain(value)
+	value.Release()
+}
+
+// Set replaces an item by an index, wraps it into a reference, panics when out of range.
+func (v *vector[V]) Set(index int, value V) {
+	r := newRef(value)
+	v.SetRetain(index, r)
+	r.Release()
+}
+
+// SetRetain replaces an item reference by an index, retains the new value and releases
+// the previous one, panics when out of range.
+func (v *vector[V]) SetRetain(index int, value ref.R[V]) {
+	if !v.mutable {
+		panic("operation on immutable refvec")
+	}
+	v.checkIndex(index)
+
+	p := v.start + index
+	leaf := v.mutateLeaf(p)
+	leaf.put(p&mask, value)
+	v.mod++
+}
+
+// SetNoRetain replaces an item reference by an index, does not retain the new value,
+// releases the previous one, panics when out of range.
+func (v *vector[V]) SetNoRetain(index int, value ref.R[V]) {
+	v.SetRetain(index, value)
+	value.Release()
+}
+
+// Internal
+
+// Free frees the vector, releases all values.
+func (v *vector[V]) Free() {
+	v.root.release()
+	v.root = nil
+
+	// Release state
+	s := v.state
+	v.state = nil
+	releaseState[V](s)
+}
+
+// private
+
+func (v *vector[V]) checkIndex(index int) {
+	if index < 0 || index >= v.Length() {
+		panic(fmt.Sprintf("refvec index out of range [%d] with length %d", index, v.Length()))
+	}
+}
+
+// leaf returns a leaf which contains an absolute position.
+func (v *vector[V]) leaf(p int) *leafNode[V] {
+	node := v.root
+	for shift := v.shift; shift > 0; shift -= bits {
+		branch := node.(*branchNode[V])
+		node = branch.child((p >> shift) & mask)
+	}
+	return node.(*leafNode[V])
+}
+
+// mutateLeaf returns a mutable leaf which contains an absolute position,
+// clones immutable nodes and creates absent ones on the path.
+func (v *vector[V]) mutateLeaf(p int) *leafNode[V] {
+	node := v.mutateRoot()
+	for shift := v.shift; shift > 0; shift -= bits {
+		branch := node.(*branchNode[V])
+		node = branch.mutateChild((p>>shift)&mask, shift-bits)
+	}
+	return node.(*leafNode[V])
+}
+
+func (v *vector[V]) mutateRoot() node[V] {
+	if v.root.mutable() {
+		return v.root
+	}
+
+	// Clone and replace root
+	prev := v.root
+	next := v.root.clone()
+	v.root = next
+	v.mod++
+
+	// Release previous
+	prev.release()
+	return next
+}
+
+func newRef[V any](value V) ref.R[V] {
+	f, ok := (any)(value).(ref.Freer)
+	if ok {
+		return ref.NewFreer(value, f)
+	}
+	return ref.NewNoop(value)
+}
+
+// state pool
+
+var statePools = pools.NewPools()
+
+func acquireState[V any]() *state[V] {
+	v, ok := pools.Acquire[*state[V]](statePools)
+	if ok {
+		return v
+	}
+	return &state[V]{}
+}
+
+func releaseState[V any](s *state[V]) {
+	s.reset()
+	pools.Release(statePools, s)
+}
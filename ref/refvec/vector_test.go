@@ -0,0 +1,409 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package refvec
+
+import (
+	"testing"
+
+	"github.com/basecomplextech/baselibrary/ref"
+	"github.com/basecomplextech/baselibrary/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testVector(t tests.T, values ...ref.R[*Value]) *vector[*Value] {
+	v := newVector[*Value]()
+	for _, value := range values {
+		v.AppendRetain(value)
+	}
+	return v
+}
+
+func testValues(n int) []ref.R[*Value] {
+	values := make([]ref.R[*Value], 0, n)
+	for i := 0; i < n; i++ {
+		values = append(values, testValue(i))
+	}
+	return values
+}
+
+func testInts(v Vector[*Value]) []int {
+	result := make([]int, 0, v.Length())
+	for _, value := range v.All() {
+		result = append(result, value.Unwrap().val)
+	}
+	return result
+}
+
+func testRange(start int, end int) []int {
+	result := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		result = append(result, i)
+	}
+	return result
+}
+
+// New
+
+func TestNew__should_return_empty_vector(t *testing.T) {
+	v := New[*Value](true)
+	defer v.Free()
+
+	assert.True(t, v.Empty())
+	assert.Equal(t, 0, v.Length())
+	assert.True(t, v.Mutable())
+	assert.Nil(t, v.Values())
+}
+
+// Free
+
+func TestVector_Free__should_release_values(t *testing.T) {
+	values := testValues(width*width + 1)
+	v := testVector(t, values...)
+
+	for _, value := range values {
+		assert.Equal(t, int64(2), value.Refcount())
+	}
+
+	v.Free()
+	for _, value := range values {
+		assert.Equal(t, int64(1), value.Refcount())
+	}
+}
+
+// Append
+
+func TestVector_Append__should_append_values_and_grow_trie(t *testing.T) {
+	n := width*width*width + 1
+	v := testVector(t, testValues(n)...)
+	defer v.Free()
+
+	assert.Equal(t, n, v.Length())
+	assert.Equal(t, uint(3*bits), v.shift)
+	assert.Equal(t, testRange(0, n), testInts(v))
+}
+
+func TestVector_Append__should_wrap_value_into_reference(t *testing.T) {
+	v := New[*Value](true)
+
+	value := &Value{val: 1}
+	v.Append(value)
+
+	r := v.Get(0)
+	assert.Same(t, value, r.Unwrap())
+	assert.Equal(t, int64(1), r.Refcount())
+
+	v.Free()
+	assert.True(t, value.freed)
+}
+
+func TestVector_AppendRetain__should_panic_when_immutable(t *testing.T) {
+	v := New[*Value](false)
+	defer v.Free()
+
+	value := testValue(1)
+	assert.Panics(t, func() {
+		v.AppendRetain(value)
+	})
+}
+
+func TestVector_AppendNoRetain__should_not_retain_value(t *testing.T) {
+	v := New[*Value](true)
+	defer v.Free()
+
+	value := testValue(1)
+	value.Retain()
+
+	v.AppendNoRetain(value)
+	assert.Equal(t, int64(2), value.Refcount())
+}
+
+// Get
+
+func TestVector_Get__should_return_value_by_index(t *testing.T) {
+	values := testValues(1000)
+	v := testVector(t, values...)
+	defer v.Free()
+
+	for i, value := range values {
+		assert.Same(t, value, v.Get(i))
+	}
+}
+
+func TestVector_Get__should_panic_when_out_of_range(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+
+	assert.Panics(t, func() { v.Get(-1) })
+	assert.Panics(t, func() { v.Get(10) })
+}
+
+// Set
+
+func TestVector_SetRetain__should_retain_new_and_release_previous_value(t *testing.T) {
+	values := testValues(100)
+	v := testVector(t, values...)
+	defer v.Free()
+
+	value := testValue(-1)
+	v.SetRetain(50, value)
+
+	assert.Same(t, value, v.Get(50))
+	assert.Equal(t, int64(2), value.Refcount())
+	assert.Equal(t, int64(1), values[50].Refcount())
+}
+
+func TestVector_SetRetain__should_panic_when_out_of_range(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+
+	value := testValue(1)
+	assert.Panics(t, func() { v.SetRetain(10, value) })
+}
+
+func TestVector_Set__should_wrap_value_into_reference(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+
+	value := &Value{val: -1}
+	v.Set(5, value)
+
+	assert.Same(t, value, v.Get(5).Unwrap())
+}
+
+// Clone
+
+func TestVector_Clone__should_panic_when_mutable(t *testing.T) {
+	v := testVector(t)
+	defer v.Free()
+
+	assert.Panics(t, func() {
+		v.Clone()
+	})
+}
+
+func TestVector_Clone__should_return_mutable_clone(t *testing.T) {
+	v := testVector(t, testValues(100)...)
+	defer v.Free()
+	v.Freeze()
+
+	v1 := v.Clone()
+	defer v1.Free()
+
+	assert.True(t, v1.Mutable())
+	assert.Equal(t, testRange(0, 100), testInts(v1))
+}
+
+func TestVector_Clone__should_not_modify_original_on_mutation(t *testing.T) {
+	n := width * width
+	values := testValues(n)
+	v := testVector(t, values...)
+	defer v.Free()
+	v.Freeze()
+
+	v1 := v.Clone()
+	defer v1.Free()
+
+	v1.SetRetain(10, testValue(-1))
+	v1.AppendRetain(testValue(n))
+
+	assert.Equal(t, testRange(0, n), testInts(v))
+	assert.Equal(t, -1, v1.Get(10).Unwrap().val)
+	assert.Equal(t, n+1, v1.Length())
+}
+
+func TestVector_Clone__should_share_unmodified_nodes(t *testing.T) {
+	n := width * width
+	v := testVector(t, testValues(n)...)
+	defer v.Free()
+	v.Freeze()
+
+	v1 := testUnwrap(v.Clone())
+	defer v1.Free()
+
+	v1.SetRetain(0, testValue(-1))
+
+	root := v.root.(*branchNode[*Value])
+	root1 := v1.root.(*branchNode[*Value])
+	assert.NotSame(t, root.child(0), root1.child(0))
+	for i := 1; i < width; i++ {
+		assert.Same(t, root.child(i), root1.child(i))
+		assert.Equal(t, int64(2), root.child(i).refcount())
+	}
+}
+
+// Freeze
+
+func TestVector_Freeze__should_recursively_freeze_trie(t *testing.T) {
+	v := testVector(t, testValues(width*width)...)
+	defer v.Free()
+
+	v.Freeze()
+	assert.False(t, v.Mutable())
+
+	walk(v.root, func(n node[*Value]) {
+		assert.False(t, n.mutable())
+	})
+}
+
+// Slice
+
+func TestVector_Slice__should_panic_when_mutable(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+
+	assert.Panics(t, func() {
+		v.Slice(0, 5)
+	})
+}
+
+func TestVector_Slice__should_panic_when_out_of_range(t *testing.T) {
+	v := testVector(t, testValues(10)...)
+	defer v.Free()
+	v.Freeze()
+
+	assert.Panics(t, func() { v.Slice(-1, 5) })
+	assert.Panics(t, func() { v.Slice(5, 4) })
+	assert.Panics(t, func() { v.Slice(0, 11) })
+}
+
+func TestVector_Slice__should_return_items_in_range(t *testing.T) {
+	n := width*width + 100
+	v := testVector(t, testValues(n)...)
+	defer v.Free()
+	v.Freeze()
+
+	cases := [][2]int{
+		{0, 0},
+		{0, n},
+		{1, 2},
+		{10, 20},
+		{30, 40},
+		{100, 900},
+		{width * width, n},
+		{n - 1, n},
+	}
+
+	for _, c := range cases {
+		v1 := v.Slice(c[0], c[1])
+		assert.Equal(t, testRange(c[0], c[1]), testInts(v1), c)
+		v1.Free()
+	}
+}
+
+func TestVector_Slice__should_descend_to_single_child(t *testing.T) {
+	v := testVector(t, testValues(width*width*2)...)
+	defer v.Free()
+	v.Freeze()
+
+	v1 := testUnwrap(v.Slice(width+1, width+10))
+	defer v1.Free()
+
+	assert.Equal(t, uint(0), v1.shift)
+	assert.Equal(t, testRange(width+1, width+10), testInts(v1))
+}
+
+func TestVector_Slice__should_not_modify_original_on_append(t *testing.T) {
+	n := width * 4
+	v := testVector(t, testValues(n)...)
+	defer v.Free()
+	v.Freeze()
+
+	v1 := v.Slice(10, 20)
+	defer v1.Free()
+
+	for i := 0; i < width*width; i++ {
+		v1.AppendRetain(testValue(-i))
+	}
+	v1.SetRetain(0, testValue(-1))
+
+	assert.Equal(t, testRange(0, n), testInts(v))
+	assert.Equal(t, 10+width*width, v1.Length())
+	assert.Equal(t, -1, v1.Get(0).Unwrap().val)
+	assert.Equal(t, 11, v1.Get(1).Unwrap().val)
+	assert.Equal(t, 0, v1.Get(10).Unwrap().val)
+}
+
+func TestVector_Slice__should_release_values_on_free(t *testing.T) {
+	values := testValues(width * 4)
+	v := testVector(t, values...)
+	v.Freeze()
+
+	v1 := v.Slice(10, 100)
+	v1.AppendRetain(testValue(-1))
+	v.Free()
+	v1.Free()
+
+	for _, value := range values {
+		assert.Equal(t, int64(1), value.Refcount())
+	}
+}
+
+// Values
+
+func TestVector_Values__should_return_values(t *testing.T) {
+	values := testValues(100)
+	v := testVector(t, values...)
+	defer v.Free()
+
+	values1 := v.Values()
+	require.Len(t, values1, len(values))
+	for i, value := range values {
+		assert.Same(t, value, values1[i])
+	}
+}
+
+// Backward
+
+func TestVector_Backward__should_iterate_in_reverse_order(t *testing.T) {
+	v := testVector(t, testValues(100)...)
+	defer v.Free()
+
+	var indexes []int
+	for i, value := range v.Backward() {
+		require.Equal(t, i, value.Unwrap().val)
+		indexes = append(indexes, i)
+	}
+
+	require.Len(t, indexes, 100)
+	assert.Equal(t, 99, indexes[0])
+	assert.Equal(t, 0, indexes[99])
+}
+
+// private
+
+func testUnwrap[V any](v Vector[V]) *vector[V] {
+	return v.(*vector[V])
+}
+
+func walk[V any](n node[V], fn func(node[V])) {
+	fn(n)
+
+	branch, ok := n.(*branchNode[V])
+	if !ok {
+		return
+	}
+
+	for _, child := range branch.children {
+		if child != nil {
+			walk(child, fn)
+		}
+	}
+}
+
+// test value
+
+type Value struct {
+	val   int
+	freed bool
+}
+
+func testValue(v int) ref.R[*Value] {
+	return ref.New(&Value{val: v})
+}
+
+func (v *Value) Free() {
+	v.freed = true
+}
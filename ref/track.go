@@ -0,0 +1,278 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// EnableTracking enables reference tracking.
+//
+// Tracked references record the stacks of their creation, retains and releases.
+// An extra release of a tracked reference panics with its history.
+//
+// Tracking is slow and must be used only in tests and debug builds, see [tests.TestRefs]
+// and the refdebug build tag. Only references created after enabling are tracked.
+func EnableTracking() {
+	trackEnabled.Store(true)
+}
+
+// DisableTracking disables reference tracking and clears all tracked references.
+func DisableTracking() {
+	trackEnabled.Store(false)
+	globalTracker.clear()
+}
+
+// TrackingEnabled returns true if reference tracking is enabled.
+func TrackingEnabled() bool {
+	return trackEnabled.Load()
+}
+
+// TrackingMark returns the current tracking mark, which can be used to return references
+// created after it.
+func TrackingMark() uint64 {
+	return globalTracker.seq.Load()
+}
+
+// TrackedRefs returns alive tracked references created after the mark.
+func TrackedRefs(mark uint64) []TrackedRef {
+	return globalTracker.alive(mark)
+}
+
+// TrackedRef is an alive tracked reference with its history.
+type TrackedRef struct {
+	Type     string
+	Refcount int64
+	Events   []TrackEvent
+}
+
+// TrackEvent is a tracked reference event with a stack.
+type TrackEvent struct {
+	Kind  TrackEventKind
+	Stack []uintptr
+}
+
+// TrackEventKind is a tracked reference event kind.
+type TrackEventKind int
+
+const (
+	TrackNew TrackEventKind = iota
+	TrackRetain
+	TrackRelease
+)
+
+// String returns the reference type, refcount and the stacks of its creation, retains
+// and releases, i.e. the sites of unbalanced retains.
+func (r TrackedRef) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%v refcount=%d\n", r.Type, r.Refcount)
+
+	for _, e := range r.Events {
+		fmt.Fprintf(&b, "%v at\n", e.Kind)
+		writeTrackStack(&b, e.Stack)
+	}
+	return b.String()
+}
+
+// String returns the event kind name.
+func (k TrackEventKind) String() string {
+	switch k {
+	case TrackNew:
+		return "new"
+	case TrackRetain:
+		return "retain"
+	case TrackRelease:
+		return "release"
+	}
+	return fmt.Sprintf("unknown(%d)", int(k))
+}
+
+// internal
+
+const (
+	trackStackDepth = 32
+	trackReleased   = 1024 // max number of recently released references to keep
+)
+
+var (
+	trackEnabled  atomic.Bool
+	globalTracker = newTracker()
+)
+
+type tracker struct {
+	seq atomic.Uint64
+
+	mu       sync.Mutex
+	refs     map[any]*trackEntry
+	released map[any]*trackEntry // recently released references
+	order    []any               // released references in order of release
+}
+
+type trackEntry struct {
+	seq    uint64
+	typ    string
+	refs   int64
+	events []TrackEvent
+}
+
+func newTracker() *tracker {
+	return &tracker{
+		refs:     make(map[any]*trackEntry),
+		released: make(map[any]*trackEntry),
+	}
+}
+
+// trackNew tracks a new reference, must be called only when tracking is enabled.
+func trackNew(r any) {
+	globalTracker.add(r)
+}
+
+// trackRetain records a retain of a reference.
+func trackRetain(r any) {
+	globalTracker.retain(r)
+}
+
+// trackRelease records a release of a reference, panics with the reference history
+// if the reference has been released already.
+func trackRelease(r any) {
+	globalTracker.release(r)
+}
+
+func (t *tracker) add(r any) {
+	event := newTrackEvent(TrackNew)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.refs[r] = &trackEntry{
+		seq:    t.seq.Add(1),
+		typ:    fmt.Sprintf("%T", r),
+		refs:   1,
+		events: []TrackEvent{event},
+	}
+}
+
+func (t *tracker) retain(r any) {
+	event := newTrackEvent(TrackRetain)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.refs[r]
+	if !ok {
+		// Reference can be resurrected by a concurrent acquire,
+		// which succeeded before the actual release.
+		e, ok = t.released[r]
+		if !ok {
+			return
+		}
+		delete(t.released, r)
+		t.refs[r] = e
+	}
+
+	e.refs++
+	e.events = append(e.events, event)
+}
+
+func (t *tracker) release(r any) {
+	event := newTrackEvent(TrackRelease)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.refs[r]
+	if !ok {
+		e, ok = t.released[r]
+		if !ok {
+			return
+		}
+
+		e.events = append(e.events, event)
+		ref := e.tracked()
+		panic(fmt.Sprintf("release of already released reference\n%v", ref))
+	}
+
+	e.refs--
+	e.events = append(e.events, event)
+	if e.refs > 0 {
+		return
+	}
+
+	// Move to released
+	delete(t.refs, r)
+	t.released[r] = e
+	t.order = append(t.order, r)
+
+	// Forget oldest released
+	if len(t.order) > trackReleased {
+		delete(t.released, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+func (t *tracker) alive(mark uint64) []TrackedRef {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var entries []*trackEntry
+	for _, e := range t.refs {
+		if e.seq > mark {
+			entries = append(entries, e)
+		}
+	}
+
+	// Sort by creation
+	slices.SortFunc(entries, func(a, b *trackEntry) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	result := make([]TrackedRef, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.tracked())
+	}
+	return result
+}
+
+func (t *tracker) clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clear(t.refs)
+	clear(t.released)
+	t.order = nil
+}
+
+// private
+
+func (e *trackEntry) tracked() TrackedRef {
+	return TrackedRef{
+		Type:     e.typ,
+		Refcount: e.refs,
+		Events:   append([]TrackEvent(nil), e.events...),
+	}
+}
+
+func newTrackEvent(kind TrackEventKind) TrackEvent {
+	pcs := make([]uintptr, trackStackDepth)
+	n := runtime.Callers(4, pcs) // skip runtime.Callers, newTrackEvent, tracker method, track func
+	return TrackEvent{Kind: kind, Stack: pcs[:n]}
+}
+
+func writeTrackStack(b *strings.Builder, stack []uintptr) {
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(b, "\t%v\n\t\t%v:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			return
+		}
+	}
+}
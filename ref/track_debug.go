@@ -0,0 +1,12 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+//go:build refdebug
+
+package ref
+
+// Tracking is enabled by default in debug builds.
+func init() {
+	EnableTracking()
+}
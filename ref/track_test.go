@@ -0,0 +1,124 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTracking(t *testing.T, enable bool) uint64 {
+	enabled := TrackingEnabled()
+	t.Cleanup(func() {
+		DisableTracking()
+		if enabled {
+			EnableTracking()
+		}
+	})
+
+	if enable {
+		EnableTracking()
+	} else {
+		DisableTracking()
+	}
+	return TrackingMark()
+}
+
+func TestTracking__should_track_alive_references(t *testing.T) {
+	mark := testTracking(t, true)
+
+	r0 := NewNoop(1)
+	r1 := NewFree(2, func() {})
+	r2 := NewFreer([4]int64{}, FreeFunc(func() {}))
+	r3 := Next(3, NewNoop(4))
+	r1.Retain()
+
+	refs := TrackedRefs(mark)
+	require.Len(t, refs, 5)
+	assert.Equal(t, int64(2), refs[1].Refcount)
+	assert.Len(t, refs[1].Events, 2)
+	assert.Equal(t, TrackRetain, refs[1].Events[1].Kind)
+
+	r0.Release()
+	r1.Release()
+	r1.Release()
+	r2.Release()
+	r3.Release()
+
+	refs = TrackedRefs(mark)
+	assert.Len(t, refs, 0)
+}
+
+func TestTracking__should_print_retain_sites(t *testing.T) {
+	mark := testTracking(t, true)
+
+	r := NewNoop(1)
+	r.Retain()
+	r.Release()
+
+	refs := TrackedRefs(mark)
+	require.Len(t, refs, 1)
+
+	s := refs[0].String()
+	assert.Contains(t, s, "refcount=1")
+	assert.Contains(t, s, "retain at")
+	assert.Contains(t, s, "TestTracking__should_print_retain_sites")
+}
+
+func TestTracking__should_panic_with_history_on_extra_release(t *testing.T) {
+	testTracking(t, true)
+
+	r := New(FreeFunc(func() {}))
+	r.Release()
+
+	defer func() {
+		e := recover()
+		require.NotNil(t, e)
+		assert.Contains(t, e, "release of already released reference")
+		assert.Contains(t, e, "new at")
+	}()
+	r.Release()
+}
+
+func TestTracking__should_track_var_references(t *testing.T) {
+	mark := testTracking(t, true)
+
+	v := NewVar[int]()
+	v.Set(1)
+
+	r, ok := v.Acquire()
+	require.True(t, ok)
+	assert.Len(t, TrackedRefs(mark), 2) // var ref, noop retained by var
+
+	r.Release()
+	v.Unset()
+	assert.Len(t, TrackedRefs(mark), 0)
+}
+
+func TestTracking__should_track_sharded_var_references(t *testing.T) {
+	mark := testTracking(t, true)
+
+	v := NewShardedVar[int]()
+	v.Set(1)
+
+	r, ok := v.Acquire()
+	require.True(t, ok)
+	assert.NotEmpty(t, TrackedRefs(mark))
+
+	r.Release()
+	v.Unset()
+	assert.Len(t, TrackedRefs(mark), 0)
+}
+
+func TestTracking__should_not_track_when_disabled(t *testing.T) {
+	mark := testTracking(t, false)
+
+	r := NewNoop(1)
+	r.Retain()
+
+	assert.Len(t, TrackedRefs(mark), 0)
+}
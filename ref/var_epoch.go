@@ -0,0 +1,358 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/basecomplextech/baselibrary/opt"
+)
+
+// EpochVar is a variable which holds a value reference, and uses epoch-based reclamation
+// for read-mostly data.
+//
+// Readers enter and exit epochs without writes to shared memory, each reader writes only
+// its own padded slot. Writers replace the current reference and retire the previous one,
+// a retired reference is released when all readers which could have seen it exit their epochs.
+// The writer releases it immediately when there are no such readers, otherwise the last one
+// of them releases it on exit.
+//
+// Acquire uses internal sharded slots and retains the reference, use readers to avoid
+// refcount increments completely.
+//
+// Usage:
+//
+//	r := v.Reader()
+//	defer r.Free()
+//
+//	for {
+//		table, ok := r.Enter()
+//		if ok {
+//			route(table.Unwrap())
+//		}
+//		r.Exit()
+//	}
+type EpochVar[T any] interface {
+	Var[T]
+
+	// Reader returns a new reader, the reader must not be used concurrently and must be freed.
+	Reader() EpochReader[T]
+}
+
+// EpochReader reads an epoch variable, the reader must not be used concurrently.
+type EpochReader[T any] interface {
+	// Enter enters an epoch, and returns the current reference or false.
+	// The reference is not retained and is valid until Exit.
+	Enter() (R[T], bool)
+
+	// Exit exits the epoch.
+	Exit()
+
+	// Internal
+
+	// Free frees the reader.
+	Free()
+}
+
+// NewEpochVar returns a new empty epoch variable.
+func NewEpochVar[T any]() EpochVar[T] {
+	return newEpochVar[T]()
+}
+
+// internal
+
+var _ EpochVar[any] = (*epochVar[any])(nil)
+
+type epochVar[T any] struct {
+	cur     atomic.Pointer[epochRef[T]]
+	epoch   atomic.Uint64
+	pending atomic.Bool // true when there are retired references
+
+	mu      sync.Mutex
+	shards  []epochSlot       // slots for acquire
+	slots   []*epochSlot      // all slots, including shards
+	free    []*epochSlot      // free reader slots
+	retired []epochRetired[T] // retired references in epoch order
+}
+
+// epochRef is an immutable box for a reference, so that it can be stored atomically.
+type epochRef[T any] struct {
+	ref R[T]
+}
+
+// epochRetired is a reference replaced in an epoch.
+type epochRetired[T any] struct {
+	ref   R[T]
+	epoch uint64
+}
+
+// epochSlot is a reader slot which stores an active epoch.
+type epochSlot struct {
+	state atomic.Uint64 // epoch<<1 | 1 when active, or 0
+	_     [120]byte
+}
+
+func newEpochVar[T any]() *epochVar[T] {
+	cpus := runtime.NumCPU()
+
+	v := &epochVar[T]{
+		shards: make([]epochSlot, cpus),
+	}
+	for i := range v.shards {
+		v.slots = append(v.slots, &v.shards[i])
+	}
+	return v
+}
+
+// Acquire acquires, retains and returns a value reference, or false.
+func (v *epochVar[T]) Acquire() (R[T], bool) {
+	slot := v.enterShard()
+	defer v.exit(slot)
+
+	box := v.cur.Load()
+	if box == nil {
+		return nil, false
+	}
+
+	// Retired references are not released until exit
+	ref := box.ref
+	ref.Retain()
+	return ref, true
+}
+
+// Reader returns a new reader, the reader must not be used concurrently and must be freed.
+func (v *epochVar[T]) Reader() EpochReader[T] {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var slot *epochSlot
+	if n := len(v.free); n > 0 {
+		slot = v.free[n-1]
+		v.free = v.free[:n-1]
+	} else {
+		slot = &epochSlot{}
+		v.slots = append(v.slots, slot)
+	}
+
+	return &epochReader[T]{v: v, slot: slot}
+}
+
+// Set sets a value, releases the previous reference.
+func (v *epochVar[T]) Set(value T) {
+	ref := NewNoop(value)
+	defer ref.Release()
+
+	v.SetRetain(ref)
+}
+
+// SetRetain sets a value reference, retains the new one and releases the old one.
+func (v *epochVar[T]) SetRetain(ref R[T]) {
+	ref.Retain()
+	next := &epochRef[T]{ref: ref}
+
+	v.swap(next)
+}
+
+// Unset clears the value.
+func (v *epochVar[T]) Unset() {
+	v.swap(nil)
+}
+
+// Internal
+
+// Unwrap returns the current value.
+// The method must be externally synchronized.
+func (v *epochVar[T]) Unwrap() opt.Opt[T] {
+	box := v.cur.Load()
+	if box == nil {
+		return opt.Opt[T]{}
+	}
+
+	val := box.ref.Unwrap()
+	return opt.New(val)
+}
+
+// UnwrapRef returns the current reference.
+// The method must be externally synchronized.
+func (v *epochVar[T]) UnwrapRef() opt.Opt[R[T]] {
+	box := v.cur.Load()
+	if box == nil {
+		return opt.Opt[R[T]]{}
+	}
+
+	return opt.New(box.ref)
+}
+
+// private
+
+// enter marks a slot as active in the current epoch.
+//
+// The epoch is loaded before the reference, so a writer which has not seen the slot
+// as active has already replaced the reference, and the reader sees the new one.
+func (v *epochVar[T]) enter(slot *epochSlot) {
+	epoch := v.epoch.Load()
+	slot.state.Store(epoch<<1 | 1)
+}
+
+// enterShard marks a random free shard as active in the current epoch.
+func (v *epochVar[T]) enterShard() *epochSlot {
+	n := len(v.shards)
+	i := int(fastrand()) % n
+
+	for {
+		slot := &v.shards[i]
+		epoch := v.epoch.Load()
+
+		if slot.state.Load() == 0 && slot.state.CompareAndSwap(0, epoch<<1|1) {
+			return slot
+		}
+
+		i = (i + 1) % n
+		if i == 0 {
+			runtime.Gosched()
+		}
+	}
+}
+
+// exit marks a slot as inactive, and releases retired references when pending.
+func (v *epochVar[T]) exit(slot *epochSlot) {
+	slot.state.Store(0)
+
+	if !v.pending.Load() {
+		return
+	}
+
+	// Pending is rare, and stored before the writer scans the slots,
+	// so either the writer or this reader sees the slot as inactive
+	v.mu.Lock()
+	refs := v.reclaim()
+	v.mu.Unlock()
+
+	releaseEpochRefs(refs)
+}
+
+// swap replaces the current reference, retires the previous one and releases
+// retired references which are not used by readers anymore.
+func (v *epochVar[T]) swap(next *epochRef[T]) {
+	v.mu.Lock()
+
+	prev := v.cur.Swap(next)
+	if prev != nil {
+		// Readers in newer epochs see the next reference
+		epoch := v.epoch.Add(1)
+		v.retired = append(v.retired, epochRetired[T]{ref: prev.ref, epoch: epoch})
+		v.pending.Store(true)
+	}
+
+	refs := v.reclaim()
+	v.mu.Unlock()
+
+	releaseEpochRefs(refs)
+}
+
+// reclaim removes and returns retired references which are not used by readers,
+// must be called under the mutex.
+func (v *epochVar[T]) reclaim() []R[T] {
+	if len(v.retired) == 0 {
+		return nil
+	}
+
+	// Find the oldest active epoch
+	oldest := uint64(0)
+	active := false
+	for _, slot := range v.slots {
+		state := slot.state.Load()
+		if state&1 == 0 {
+			continue
+		}
+
+		epoch := state >> 1
+		if !active || epoch < oldest {
+			oldest = epoch
+			active = true
+		}
+	}
+
+	// Collect references retired before or in the oldest epoch,
+	// readers in older epochs could have seen them
+	n := len(v.retired)
+	if active {
+		n = 0
+		for _, r := range v.retired {
+			if r.epoch > oldest {
+				break
+			}
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	refs := make([]R[T], 0, n)
+	for _, r := range v.retired[:n] {
+		refs = append(refs, r.ref)
+	}
+
+	v.retired = slices.Delete(v.retired, 0, n)
+	v.pending.Store(len(v.retired) > 0)
+	return refs
+}
+
+func releaseEpochRefs[T any](refs []R[T]) {
+	for _, ref := range refs {
+		ref.Release()
+	}
+}
+
+// reader
+
+var _ EpochReader[any] = (*epochReader[any])(nil)
+
+type epochReader[T any] struct {
+	v    *epochVar[T]
+	slot *epochSlot
+}
+
+// Enter enters an epoch, and returns the current reference or false.
+// The reference is not retained and is valid until Exit.
+func (r *epochReader[T]) Enter() (R[T], bool) {
+	r.v.enter(r.slot)
+
+	box := r.v.cur.Load()
+	if box == nil {
+		return nil, false
+	}
+	return box.ref, true
+}
+
+// Exit exits the epoch.
+func (r *epochReader[T]) Exit() {
+	r.v.exit(r.slot)
+}
+
+// Internal
+
+// Free frees the reader.
+func (r *epochReader[T]) Free() {
+	v := r.v
+	slot := r.slot
+
+	r.v = nil
+	r.slot = nil
+
+	// Exit if entered
+	if slot.state.Load() != 0 {
+		v.exit(slot)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.free = append(v.free, slot)
+}
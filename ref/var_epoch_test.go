@@ -0,0 +1,249 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Acquire
+
+func TestEpochVar_Acquire__should_acquire_current_reference(t *testing.T) {
+	r := NewNoop(1)
+
+	v := newEpochVar[int]()
+	v.SetRetain(r)
+	r.Release()
+
+	r1, ok := v.Acquire()
+	require.True(t, ok)
+	assert.Same(t, r, r1)
+	assert.Equal(t, int64(2), r.Refcount())
+
+	v.Unset()
+	assert.Equal(t, int64(1), r.Refcount())
+
+	r1.Release()
+	assert.Equal(t, int64(0), r.Refcount())
+}
+
+func TestEpochVar_Acquire__should_return_false_when_unset(t *testing.T) {
+	v := newEpochVar[int]()
+
+	r, ok := v.Acquire()
+	assert.False(t, ok)
+	assert.Nil(t, r)
+}
+
+// SetRetain
+
+func TestEpochVar_SetRetain__should_retain_new_reference(t *testing.T) {
+	r := NewNoop(1)
+
+	v := newEpochVar[int]()
+	v.SetRetain(r)
+	assert.Equal(t, int64(2), r.Refcount())
+
+	r.Release()
+	assert.Equal(t, int64(1), r.Refcount())
+}
+
+func TestEpochVar_SetRetain__should_release_previous_reference_without_readers(t *testing.T) {
+	r0 := NewNoop(1)
+	r1 := NewNoop(2)
+
+	v := newEpochVar[int]()
+	v.SetRetain(r0)
+	v.SetRetain(r1)
+
+	assert.Equal(t, int64(1), r0.Refcount())
+	assert.Equal(t, int64(2), r1.Refcount())
+	assert.Equal(t, 2, v.Unwrap().Value)
+}
+
+func TestEpochVar_SetRetain__should_release_previous_reference_when_readers_exit(t *testing.T) {
+	r0 := NewNoop(1)
+	r1 := NewNoop(2)
+
+	v := newEpochVar[int]()
+	v.SetRetain(r0)
+	r0.Release()
+
+	reader := v.Reader()
+	defer reader.Free()
+
+	ref, ok := reader.Enter()
+	require.True(t, ok)
+	assert.Same(t, r0, ref)
+
+	v.SetRetain(r1)
+	r1.Release()
+	assert.Equal(t, int64(1), r0.Refcount())
+
+	reader.Exit()
+	assert.Equal(t, int64(0), r0.Refcount())
+	assert.Equal(t, int64(1), r1.Refcount())
+}
+
+func TestEpochVar_SetRetain__should_not_wait_for_readers_in_newer_epochs(t *testing.T) {
+	r0 := NewNoop(1)
+	r1 := NewNoop(2)
+	r2 := NewNoop(3)
+
+	v := newEpochVar[int]()
+	v.SetRetain(r0)
+
+	reader0 := v.Reader()
+	defer reader0.Free()
+	reader1 := v.Reader()
+	defer reader1.Free()
+
+	reader0.Enter()
+	v.SetRetain(r1)
+
+	ref, _ := reader1.Enter()
+	assert.Same(t, r1, ref)
+	v.SetRetain(r2)
+
+	// Reader0 can see r0 and r1, reader1 can see r1
+	reader0.Exit()
+	assert.Equal(t, int64(1), r0.Refcount())
+	assert.Equal(t, int64(2), r1.Refcount())
+
+	reader1.Exit()
+	assert.Equal(t, int64(1), r1.Refcount())
+	assert.Equal(t, int64(2), r2.Refcount())
+}
+
+// Unset
+
+func TestEpochVar_Unset__should_clear_value(t *testing.T) {
+	r := NewNoop(1)
+
+	v := newEpochVar[int]()
+	v.SetRetain(r)
+	v.Unset()
+
+	assert.Equal(t, int64(1), r.Refcount())
+	assert.False(t, v.Unwrap().Valid)
+	assert.False(t, v.UnwrapRef().Valid)
+}
+
+// Reader
+
+func TestEpochReader_Enter__should_return_current_reference(t *testing.T) {
+	r := NewNoop(1)
+
+	v := newEpochVar[int]()
+	reader := v.Reader()
+	defer reader.Free()
+
+	_, ok := reader.Enter()
+	assert.False(t, ok)
+	reader.Exit()
+
+	v.SetRetain(r)
+
+	ref, ok := reader.Enter()
+	require.True(t, ok)
+	assert.Same(t, r, ref)
+	assert.Equal(t, int64(2), r.Refcount())
+	reader.Exit()
+}
+
+func TestEpochReader_Free__should_exit_epoch_and_reuse_slot(t *testing.T) {
+	r := NewNoop(1)
+
+	v := newEpochVar[int]()
+	v.SetRetain(r)
+	r.Release()
+
+	reader := v.Reader()
+	reader.Enter()
+	v.Unset()
+	assert.Equal(t, int64(1), r.Refcount())
+
+	reader.Free()
+	assert.Equal(t, int64(0), r.Refcount())
+
+	n := len(v.slots)
+	reader = v.Reader()
+	defer reader.Free()
+	assert.Equal(t, n, len(v.slots))
+}
+
+// Concurrency
+
+func TestEpochVar__should_not_release_references_used_by_readers(t *testing.T) {
+	ref0 := newEpochTestRef()
+
+	v := newEpochVar[*epochTestValue]()
+	v.SetRetain(ref0)
+	ref0.Release()
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+
+	// Readers
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			reader := v.Reader()
+			defer reader.Free()
+
+			for !stop.Load() {
+				ref, ok := reader.Enter()
+				if ok && ref.Unwrap().freed.Load() {
+					panic("read freed value")
+				}
+				reader.Exit()
+
+				ref, ok = v.Acquire()
+				if ok {
+					if ref.Unwrap().freed.Load() {
+						panic("acquired freed value")
+					}
+					ref.Release()
+				}
+			}
+		}()
+	}
+
+	// Writer
+	refs := make([]R[*epochTestValue], 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		ref := newEpochTestRef()
+		refs = append(refs, ref)
+		v.SetRetain(ref)
+	}
+
+	stop.Store(true)
+	wg.Wait()
+	v.Unset()
+
+	for _, ref := range refs {
+		ref.Release()
+		assert.Equal(t, int64(0), ref.Refcount())
+	}
+	assert.Empty(t, v.retired)
+}
+
+type epochTestValue struct {
+	freed atomic.Bool
+}
+
+func newEpochTestRef() R[*epochTestValue] {
+	value := &epochTestValue{}
+	return NewFree(value, func() {
+		value.freed.Store(true)
+	})
+}
@@ -19,6 +19,10 @@ func newVarRef[T any](ref R[T]) *varRef[T] {
 	r := &varRef[T]{}
 	r.refs.Init(1)
 	r.ref = ref
+
+	if trackEnabled.Load() {
+		trackNew(r)
+	}
 	return r
 }
 
@@ -30,10 +34,13 @@ func (r *varRef[T]) Refcount() int64 {
 // Acquire tries to increment refcount and returns true, or false if already released.
 func (r *varRef[T]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.release()
+	r.refs.Release()
 	return false
 }
 
@@ -42,11 +49,14 @@ func (r *varRef[T]) Retain() {
 	// Increment refs, return if alive
 	acquired := r.refs.Acquire()
 	if acquired {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 
-	// Otherwise, release immediately
-	r.release()
+	// Otherwise, undo increment
+	r.refs.Release()
 
 	// Panic on retain of released object
 	var zero T
@@ -76,16 +86,23 @@ func (r *varRef[T]) acquire() bool {
 	// Increment refs, return if alive
 	acquired := r.refs.Acquire()
 	if acquired {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	// Otherwise, release immediately
-	r.release()
+	// Otherwise, undo increment
+	r.refs.Release()
 	return false
 }
 
 // release decrements refcount, and frees the object if refcount is 0.
 func (r *varRef[T]) release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	// Decrement refs, return if alive
 	released := r.refs.Release()
 	if !released {
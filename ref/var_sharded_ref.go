@@ -19,15 +19,22 @@ func (r *shardedVarRef[T]) init(refs *Atomic64, set *shardedVarRefset[T]) {
 	r.set = set
 	r.refs = refs
 	r.refs.Init(1)
+
+	if trackEnabled.Load() {
+		trackNew(r)
+	}
 }
 
 // Acquire increments refcount and returns the reference.
 func (r *shardedVarRef[T]) Acquire() bool {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return true
 	}
 
-	r.Release()
+	r.refs.Release()
 	return false
 }
 
@@ -39,6 +46,9 @@ func (r *shardedVarRef[T]) Refcount() int64 {
 // Retain increments refcount, panics when count is <= 0.
 func (r *shardedVarRef[T]) Retain() {
 	if ok := r.refs.Acquire(); ok {
+		if trackEnabled.Load() {
+			trackRetain(r)
+		}
 		return
 	}
 	r.refs.Release()
@@ -49,6 +59,10 @@ func (r *shardedVarRef[T]) Retain() {
 
 // Release decrements refcount and releases the object if the count is 0.
 func (r *shardedVarRef[T]) Release() {
+	if trackEnabled.Load() {
+		trackRelease(r)
+	}
+
 	if ok := r.refs.Release(); !ok {
 		return
 	}
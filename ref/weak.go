@@ -0,0 +1,84 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import "fmt"
+
+// Weak is a weak reference which does not keep an object alive.
+//
+// Upgrade retains and returns the reference only if its refcount has not dropped to zero.
+// The released bit guarantees that a reference cannot be resurrected after the final release,
+// so an upgrade is race-safe against a concurrent release.
+//
+// The weak reference keeps the reference itself in memory, but not the freed object,
+// references clear their objects or return their states to pools on release.
+//
+// Example:
+//
+//	weak := ref.NewWeak(buf)
+//	buf.Release()
+//
+//	buf, ok := weak.Upgrade()
+//	if !ok {
+//		return
+//	}
+//	defer buf.Release()
+type Weak[T any] struct {
+	ref acquirer[T]
+}
+
+// NewWeak returns a weak reference, panics if the reference does not support acquiring.
+//
+// All references in this package support weak references.
+func NewWeak[T any](r R[T]) Weak[T] {
+	a, ok := r.(acquirer[T])
+	if !ok {
+		panic(fmt.Sprintf("weak: %T does not support weak references", r))
+	}
+	return Weak[T]{ref: a}
+}
+
+// Upgrade retains and returns the reference, or false if it has been released
+// or the weak reference is empty.
+func (w Weak[T]) Upgrade() (R[T], bool) {
+	if w.ref == nil {
+		return nil, false
+	}
+
+	if ok := w.ref.Acquire(); !ok {
+		return nil, false
+	}
+	return w.ref, true
+}
+
+// Alive returns true if the reference has not been released yet.
+// The result is immediately stale, use Upgrade to access the object.
+func (w Weak[T]) Alive() bool {
+	if w.ref == nil {
+		return false
+	}
+	return w.ref.Refcount() > 0
+}
+
+// internal
+
+// acquirer is a reference which can be acquired only when not released.
+type acquirer[T any] interface {
+	R[T]
+
+	// Acquire tries to increment refcount and returns true, or false if already released.
+	Acquire() bool
+}
+
+var (
+	_ acquirer[Freer] = (*ref[Freer])(nil)
+	_ acquirer[any]   = (*refFreer[any])(nil)
+	_ acquirer[any]   = (*refFreerPooled[any])(nil)
+	_ acquirer[any]   = (*refNext[any, any])(nil)
+	_ acquirer[any]   = (*refNextPooled[any, any])(nil)
+	_ acquirer[any]   = (*refNoop[any])(nil)
+	_ acquirer[any]   = (*varRef[any])(nil)
+	_ acquirer[any]   = (*shardedVarRef[any])(nil)
+)
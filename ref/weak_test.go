@@ -0,0 +1,98 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package ref
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeak_Upgrade__should_retain_alive_reference(t *testing.T) {
+	r := NewFree(10, func() {})
+	w := NewWeak(r)
+
+	r1, ok := w.Upgrade()
+	require.True(t, ok)
+	assert.Equal(t, int64(2), r.Refcount())
+	assert.Equal(t, 10, r1.Unwrap())
+
+	r1.Release()
+	r.Release()
+}
+
+func TestWeak_Upgrade__should_return_false_when_released(t *testing.T) {
+	freed := 0
+	r := NewFree(10, func() { freed++ })
+	w := NewWeak(r)
+
+	r.Release()
+	require.Equal(t, 1, freed)
+
+	_, ok := w.Upgrade()
+	assert.False(t, ok)
+	assert.False(t, w.Alive())
+	assert.Equal(t, 1, freed)
+}
+
+func TestWeak_Upgrade__should_support_pooled_references(t *testing.T) {
+	type big [8]int64
+
+	r := NewFree(big{1}, func() {})
+	_, pooled := r.(*refFreerPooled[big])
+	require.True(t, pooled)
+
+	w := NewWeak(r)
+	r1, ok := w.Upgrade()
+	require.True(t, ok)
+	assert.Equal(t, big{1}, r1.Unwrap())
+
+	r1.Release()
+	r.Release()
+
+	_, ok = w.Upgrade()
+	assert.False(t, ok)
+}
+
+func TestWeak_Upgrade__should_return_false_when_empty(t *testing.T) {
+	var w Weak[int]
+
+	_, ok := w.Upgrade()
+	assert.False(t, ok)
+}
+
+func TestWeak_Upgrade__should_be_race_safe_against_final_release(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		var freed atomic.Int32
+		r := NewFree(i, func() { freed.Add(1) })
+		w := NewWeak(r)
+
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			r.Release()
+		}()
+		go func() {
+			defer wg.Done()
+
+			r1, ok := w.Upgrade()
+			if !ok {
+				return
+			}
+
+			assert.Equal(t, int32(0), freed.Load())
+			assert.Equal(t, i, r1.Unwrap())
+			r1.Release()
+		}()
+
+		wg.Wait()
+		assert.Equal(t, int32(1), freed.Load())
+	}
+}
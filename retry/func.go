@@ -10,6 +10,7 @@ import (
 	"github.com/basecomplextech/baselibrary/async"
 	"github.com/basecomplextech/baselibrary/logging"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type (
@@ -75,6 +76,12 @@ func (c FuncCall[T]) MaxRetries(maxRetries int) FuncCall[T] {
 	return c
 }
 
+// Clock sets the clock.
+func (c FuncCall[T]) Clock(clock clockwork.Clock) FuncCall[T] {
+	c.opts.Clock = clock
+	return c
+}
+
 // Options overrides all options.
 func (c FuncCall[T]) Options(opts Options) FuncCall[T] {
 	c.opts = opts
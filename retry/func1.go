@@ -10,6 +10,7 @@ import (
 	"github.com/basecomplextech/baselibrary/async"
 	"github.com/basecomplextech/baselibrary/logging"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type (
@@ -75,6 +76,12 @@ func (c Func1Call[T, A]) MaxRetries(maxRetries int) Func1Call[T, A] {
 	return c
 }
 
+// Clock sets the clock.
+func (c Func1Call[T, A]) Clock(clock clockwork.Clock) Func1Call[T, A] {
+	c.opts.Clock = clock
+	return c
+}
+
 // Options overrides all options.
 func (c Func1Call[T, A]) Options(opts Options) Func1Call[T, A] {
 	c.opts = opts
@@ -10,6 +10,7 @@ import (
 	"github.com/basecomplextech/baselibrary/async"
 	"github.com/basecomplextech/baselibrary/logging"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type (
@@ -106,6 +107,12 @@ func (c LoopCall) MaxRetries(maxRetries int) LoopCall {
 	return c
 }
 
+// Clock sets the clock.
+func (c LoopCall) Clock(clock clockwork.Clock) LoopCall {
+	c.opts.Clock = clock
+	return c
+}
+
 // Options overrides all options.
 func (c LoopCall) Options(opts Options) LoopCall {
 	c.opts = opts
@@ -10,6 +10,7 @@ import (
 	"github.com/basecomplextech/baselibrary/async"
 	"github.com/basecomplextech/baselibrary/logging"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type (
@@ -75,6 +76,12 @@ func (c Loop1Call[A]) MaxRetries(maxRetries int) Loop1Call[A] {
 	return c
 }
 
+// Clock sets the clock.
+func (c Loop1Call[A]) Clock(clock clockwork.Clock) Loop1Call[A] {
+	c.opts.Clock = clock
+	return c
+}
+
 // Options overrides all options.
 func (c Loop1Call[A]) Options(opts Options) Loop1Call[A] {
 	c.opts = opts
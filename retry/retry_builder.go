@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/basecomplextech/baselibrary/logging"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 // builder provides chained methods for building a call.
@@ -33,6 +34,9 @@ type builder[C any] interface {
 	// MaxRetries sets the max retries.
 	MaxRetries(maxRetries int) C
 
+	// Clock sets the clock.
+	Clock(clock clockwork.Clock) C
+
 	// Options overrides all options.
 	Options(opts Options) C
 }
@@ -5,10 +5,9 @@
 package retry
 
 import (
-	"time"
-
 	"github.com/basecomplextech/baselibrary/async"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type call struct {
@@ -45,12 +44,14 @@ func (c call) handleError(err status.Status, attempt int) status.Status {
 func (c call) sleep(ctx async.Context, attempt int) status.Status {
 	// Sleep before retry
 	delay := delay(attempt, c.opts.MinDelay, c.opts.MaxDelay)
-	timer := time.NewTimer(delay)
+	clock := clockwork.Or(c.opts.Clock)
+
+	timer := clock.NewTimer(delay)
 	select {
 	case <-ctx.Wait():
 		timer.Stop()
 		return ctx.Status()
-	case <-timer.C:
+	case <-timer.C():
 		return status.OK
 	}
 }
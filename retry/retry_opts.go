@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/basecomplextech/baselibrary/logging"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 // Options specifies the options for a retrier.
@@ -29,6 +30,9 @@ type Options struct {
 
 	// MaxRetries is the max retries, zero means unlimited.
 	MaxRetries int
+
+	// Clock is the clock used to sleep between retries, nil means the real clock.
+	Clock clockwork.Clock
 }
 
 // Default returns the default options.
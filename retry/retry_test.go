@@ -0,0 +1,89 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package retry
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/basecomplextech/baselibrary/async"
+	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry__should_sleep_with_exponential_backoff(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+
+	var calls atomic.Int32
+	fn := func(ctx async.Context) (int, status.Status) {
+		calls.Add(1)
+		return 0, status.Test("test")
+	}
+
+	done := make(chan status.Status, 1)
+	go func() {
+		_, st := Retry(fn).
+			Logger(nil).
+			MaxRetries(4).
+			Clock(clock).
+			Run(async.NoContext())
+		done <- st
+	}()
+
+	delays := []time.Duration{
+		25 * time.Millisecond,
+		75 * time.Millisecond,
+		175 * time.Millisecond,
+	}
+	for _, d := range delays {
+		clock.BlockUntil(1)
+
+		clock.Advance(d - 1)
+		assert.Equal(t, 1, clock.Waiters())
+
+		clock.Advance(1)
+		assert.Equal(t, 0, clock.Waiters())
+	}
+
+	select {
+	case st := <-done:
+		assert.Equal(t, status.CodeTest, st.Code)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	assert.Equal(t, int32(5), calls.Load())
+}
+
+func TestRetry__should_return_context_status_when_cancelled_while_sleeping(t *testing.T) {
+	clock := clockwork.NewFake(time.Time{})
+	ctx := async.NewContext()
+	defer ctx.Free()
+
+	fn := func(ctx async.Context) (int, status.Status) {
+		return 0, status.Test("test")
+	}
+
+	done := make(chan status.Status, 1)
+	go func() {
+		_, st := Retry(fn).
+			Logger(nil).
+			Clock(clock).
+			Run(ctx)
+		done <- st
+	}()
+
+	clock.BlockUntil(1)
+	ctx.Cancel()
+
+	select {
+	case st := <-done:
+		assert.Equal(t, status.Cancelled, st)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	assert.Equal(t, 0, clock.Waiters())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/basecomplextech/baselibrary/async"
 	"github.com/basecomplextech/baselibrary/logging"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type (
@@ -72,6 +73,12 @@ func (c VoidCall) MaxRetries(maxRetries int) VoidCall {
 	return c
 }
 
+// Clock sets the clock.
+func (c VoidCall) Clock(clock clockwork.Clock) VoidCall {
+	c.opts.Clock = clock
+	return c
+}
+
 // Options overrides all options.
 func (c VoidCall) Options(opts Options) VoidCall {
 	c.opts = opts
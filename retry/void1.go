@@ -10,6 +10,7 @@ import (
 	"github.com/basecomplextech/baselibrary/async"
 	"github.com/basecomplextech/baselibrary/logging"
 	"github.com/basecomplextech/baselibrary/status"
+	"github.com/basecomplextech/baselibrary/timeutil/clockwork"
 )
 
 type (
@@ -72,6 +73,12 @@ func (c VoidCall1[A]) MaxRetries(maxRetries int) VoidCall1[A] {
 	return c
 }
 
+// Clock sets the clock.
+func (c VoidCall1[A]) Clock(clock clockwork.Clock) VoidCall1[A] {
+	c.opts.Clock = clock
+	return c
+}
+
 // Options overrides all options.
 func (c VoidCall1[A]) Options(opts Options) VoidCall1[A] {
 	c.opts = opts
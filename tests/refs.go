@@ -0,0 +1,52 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package tests
+
+import (
+	"strings"
+
+	"github.com/basecomplextech/baselibrary/ref"
+)
+
+// TestRefs enables reference tracking, and fails the test at cleanup if any references
+// created during the test remain alive, printing their unbalanced retain sites.
+//
+// Tracking is global, so the helper must not be used in parallel tests.
+//
+// Example:
+//
+//	func TestParse(t *testing.T) {
+//		tests.TestRefs(t)
+//
+//		buf := ref.New(buffer.New())
+//		defer buf.Release()
+//		...
+//	}
+func TestRefs(t T) {
+	t.Helper()
+
+	enabled := ref.TrackingEnabled()
+	if !enabled {
+		ref.EnableTracking()
+	}
+	mark := ref.TrackingMark()
+
+	t.Cleanup(func() {
+		refs := ref.TrackedRefs(mark)
+		if !enabled {
+			ref.DisableTracking()
+		}
+		if len(refs) == 0 {
+			return
+		}
+
+		b := strings.Builder{}
+		b.WriteString("leaked references:\n")
+		for _, r := range refs {
+			b.WriteString(r.String())
+		}
+		t.Error(b.String())
+	})
+}
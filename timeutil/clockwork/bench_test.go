@@ -0,0 +1,117 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package clockwork
+
+import (
+	"testing"
+	"time"
+)
+
+func benchFunc() {}
+
+// Create and cancel
+
+func BenchmarkTime_AfterFunc_Stop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		t := time.AfterFunc(time.Second, benchFunc)
+		t.Stop()
+	}
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkTime_AfterFunc_Stop_Parallel(b *testing.B) {
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			t := time.AfterFunc(time.Second, benchFunc)
+			t.Stop()
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkWheel_AfterFunc_Stop(b *testing.B) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t := w.AfterFunc(time.Second, benchFunc)
+		t.Stop()
+	}
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkWheel_AfterFunc_Stop_Parallel(b *testing.B) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			t := w.AfterFunc(time.Second, benchFunc)
+			t.Stop()
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+// Many pending
+
+func BenchmarkTime_AfterFunc_Stop_Pending(b *testing.B) {
+	pending := make([]*time.Timer, 100_000)
+	for i := range pending {
+		pending[i] = time.AfterFunc(time.Hour, benchFunc)
+	}
+	defer func() {
+		for _, t := range pending {
+			t.Stop()
+		}
+	}()
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			t := time.AfterFunc(time.Second, benchFunc)
+			t.Stop()
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
+
+func BenchmarkWheel_AfterFunc_Stop_Pending(b *testing.B) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+
+	for i := 0; i < 100_000; i++ {
+		w.AfterFunc(time.Hour, benchFunc)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			t := w.AfterFunc(time.Second, benchFunc)
+			t.Stop()
+		}
+	})
+
+	sec := b.Elapsed().Seconds()
+	ops := float64(b.N) / sec
+	b.ReportMetric(ops/1000_000, "mops")
+}
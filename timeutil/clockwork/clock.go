@@ -0,0 +1,74 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package clockwork
+
+import "time"
+
+// Clock is an injectable time source, which allows to replace the real time in tests.
+//
+// Use [Real] in production code, and [NewFake] in tests to advance time manually.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// Until returns the duration until t.
+	Until(t time.Time) time.Duration
+
+	// Sleep pauses the current goroutine for at least the duration.
+	Sleep(d time.Duration)
+
+	// After waits for the duration to elapse and then sends the current time on the channel.
+	After(d time.Duration) <-chan time.Time
+
+	// AfterFunc waits for the duration to elapse and then calls fn.
+	AfterFunc(d time.Duration, fn func()) Timer
+
+	// NewTimer returns a new timer which sends the current time on its channel after the duration.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker returns a new ticker which sends the current time on its channel every period.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event timer, see [time.Timer].
+type Timer interface {
+	// C returns the timer channel, nil for AfterFunc timers.
+	C() <-chan time.Time
+
+	// Reset changes the timer to expire after the duration,
+	// returns true if the timer had been active.
+	Reset(d time.Duration) bool
+
+	// Stop prevents the timer from firing, returns true if the timer had been active.
+	Stop() bool
+}
+
+// Ticker delivers ticks at intervals, see [time.Ticker].
+type Ticker interface {
+	// C returns the ticker channel.
+	C() <-chan time.Time
+
+	// Reset stops the ticker and resets its period to the duration.
+	Reset(d time.Duration)
+
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// Real returns the real clock which uses the time package.
+func Real() Clock {
+	return defaultClock
+}
+
+// Or returns the clock if not nil, or the real clock.
+func Or(c Clock) Clock {
+	if c == nil {
+		return defaultClock
+	}
+	return c
+}
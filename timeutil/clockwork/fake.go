@@ -0,0 +1,364 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package clockwork
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a fake clock which is advanced manually, it fires timers and tickers deterministically.
+//
+// Time does not pass unless Advance or Set are called. Due timers fire in order of their
+// deadlines during Advance, AfterFunc functions are called synchronously by Advance.
+// Timers with non-positive durations send on their channels immediately, but AfterFunc
+// functions with non-positive durations are called on the next Advance.
+//
+// Example:
+//
+//	clock := clockwork.NewFake(time.Time{})
+//
+//	go func() {
+//		clock.Sleep(time.Second)
+//		// ...
+//	}()
+//
+//	clock.BlockUntil(1)
+//	clock.Advance(time.Second)
+type Fake interface {
+	Clock
+
+	// Advance advances the time by the duration, and fires all due timers and tickers.
+	Advance(d time.Duration)
+
+	// Set sets the time, and fires all due timers and tickers.
+	// The time cannot go backwards, earlier times are ignored.
+	Set(t time.Time)
+
+	// Waiters returns the number of active timers, tickers and sleepers.
+	Waiters() int
+
+	// BlockUntil blocks until there are at least n active timers, tickers and sleepers.
+	BlockUntil(n int)
+}
+
+// NewFake returns a new fake clock, zero start time means a fixed default time.
+func NewFake(start time.Time) Fake {
+	return newFake(start)
+}
+
+// internal
+
+// fakeStart is the default fake clock start time.
+var fakeStart = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var _ Fake = (*fake)(nil)
+
+type fake struct {
+	mu      sync.Mutex
+	cond    sync.Cond // signalled when waiters added
+	now     time.Time
+	seq     uint64
+	waiters []*fakeTimer
+}
+
+func newFake(start time.Time) *fake {
+	if start.IsZero() {
+		start = fakeStart
+	}
+
+	c := &fake{now: start}
+	c.cond.L = &c.mu
+	return c
+}
+
+// Now returns the current time.
+func (c *fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Since returns the time elapsed since t.
+func (c *fake) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Until returns the duration until t.
+func (c *fake) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+// Sleep pauses the current goroutine until the time is advanced by the duration.
+func (c *fake) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-c.After(d)
+}
+
+// After waits for the duration to elapse and then sends the current time on the channel.
+func (c *fake) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// AfterFunc waits for the duration to elapse and then calls fn.
+func (c *fake) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &fakeTimer{c: c, fn: fn}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.schedule(t, d)
+	return t
+}
+
+// NewTimer returns a new timer which sends the current time on its channel after the duration.
+func (c *fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{c: c, ch: make(chan time.Time, 1)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+
+	c.schedule(t, d)
+	return t
+}
+
+// NewTicker returns a new ticker which sends the current time on its channel every period.
+func (c *fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	t := &fakeTimer{c: c, ch: make(chan time.Time, 1), period: d}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.schedule(t, d)
+	return fakeTicker{t}
+}
+
+// Advance advances the time by the duration, and fires all due timers and tickers.
+func (c *fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.now.Add(d)
+	for {
+		t := c.next(target)
+		if t == nil {
+			break
+		}
+
+		// Move time to timer
+		if t.when.After(c.now) {
+			c.now = t.when
+		}
+
+		// Reschedule ticker, or remove timer
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			c.remove(t)
+		}
+
+		// Send time, drop when full
+		if t.ch != nil {
+			select {
+			case t.ch <- c.now:
+			default:
+			}
+			continue
+		}
+
+		// Call function outside of lock
+		c.mu.Unlock()
+		t.fn()
+		c.mu.Lock()
+	}
+
+	if target.After(c.now) {
+		c.now = target
+	}
+}
+
+// Set sets the time, and fires all due timers and tickers.
+// The time cannot go backwards, earlier times are ignored.
+func (c *fake) Set(t time.Time) {
+	d := t.Sub(c.Now())
+	if d < 0 {
+		return
+	}
+	c.Advance(d)
+}
+
+// Waiters returns the number of active timers, tickers and sleepers.
+func (c *fake) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until there are at least n active timers, tickers and sleepers.
+func (c *fake) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// private
+
+// next returns the earliest timer due at the target time, or nil.
+func (c *fake) next(target time.Time) *fakeTimer {
+	var next *fakeTimer
+	for _, t := range c.waiters {
+		if t.when.After(target) {
+			continue
+		}
+
+		switch {
+		case next == nil:
+			next = t
+		case t.when.Before(next.when):
+			next = t
+		case t.when.Equal(next.when) && t.seq < next.seq:
+			next = t
+		}
+	}
+	return next
+}
+
+func (c *fake) schedule(t *fakeTimer, d time.Duration) {
+	c.seq++
+
+	t.when = c.now.Add(d)
+	t.seq = c.seq
+	t.active = true
+
+	c.waiters = append(c.waiters, t)
+	c.cond.Broadcast()
+}
+
+// remove removes a timer, returns true if the timer had been active.
+func (c *fake) remove(t *fakeTimer) bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+
+	for i, t1 := range c.waiters {
+		if t1 != t {
+			continue
+		}
+
+		last := len(c.waiters) - 1
+		c.waiters[i] = c.waiters[last]
+		c.waiters[last] = nil
+		c.waiters = c.waiters[:last]
+		break
+	}
+	return true
+}
+
+// timer
+
+var _ Timer = (*fakeTimer)(nil)
+
+type fakeTimer struct {
+	c      *fake
+	ch     chan time.Time // nil for AfterFunc
+	fn     func()         // nil for timers and tickers
+	period time.Duration  // positive for tickers
+
+	// guarded by clock mutex
+	when   time.Time
+	seq    uint64
+	active bool
+}
+
+// C returns the timer channel, nil for AfterFunc timers.
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+// Reset changes the timer to expire after the duration,
+// returns true if the timer had been active.
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	active := t.c.remove(t)
+	t.drain()
+
+	if d <= 0 && t.ch != nil && t.period == 0 {
+		t.ch <- t.c.now
+		return active
+	}
+
+	if t.period > 0 {
+		t.period = d
+	}
+	t.c.schedule(t, d)
+	return active
+}
+
+// Stop prevents the timer from firing, returns true if the timer had been active.
+func (t *fakeTimer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	active := t.c.remove(t)
+	t.drain()
+	return active
+}
+
+// drain removes a stale value from the channel, like timers since Go 1.23.
+func (t *fakeTimer) drain() {
+	if t.ch == nil {
+		return
+	}
+
+	select {
+	case <-t.ch:
+	default:
+	}
+}
+
+// ticker
+
+var _ Ticker = fakeTicker{}
+
+type fakeTicker struct {
+	t *fakeTimer
+}
+
+// C returns the ticker channel.
+func (t fakeTicker) C() <-chan time.Time {
+	return t.t.C()
+}
+
+// Reset stops the ticker and resets its period to the duration.
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.t.Reset(d)
+}
+
+// Stop turns off the ticker.
+func (t fakeTicker) Stop() {
+	t.t.Stop()
+}
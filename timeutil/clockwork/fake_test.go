@@ -0,0 +1,176 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package clockwork
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Now
+
+func TestFake_Now__should_return_start_time(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	assert.Equal(t, start, c.Now())
+}
+
+func TestFake_Advance__should_advance_time(t *testing.T) {
+	c := NewFake(time.Time{})
+	start := c.Now()
+
+	c.Advance(time.Second)
+	assert.Equal(t, time.Second, c.Since(start))
+}
+
+// Timer
+
+func TestFake_NewTimer__should_fire_timer_when_advanced(t *testing.T) {
+	c := NewFake(time.Time{})
+	timer := c.NewTimer(time.Second)
+
+	c.Advance(time.Second - 1)
+	select {
+	case <-timer.C():
+		t.Fatal("timer should not fire")
+	default:
+	}
+
+	c.Advance(1)
+	select {
+	case now := <-timer.C():
+		assert.Equal(t, c.Now(), now)
+	default:
+		t.Fatal("timer should fire")
+	}
+	assert.Equal(t, 0, c.Waiters())
+}
+
+func TestFake_NewTimer__should_not_fire_stopped_timer(t *testing.T) {
+	c := NewFake(time.Time{})
+	timer := c.NewTimer(time.Second)
+
+	ok := timer.Stop()
+	assert.True(t, ok)
+
+	c.Advance(time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer should not fire")
+	default:
+	}
+
+	ok = timer.Stop()
+	assert.False(t, ok)
+}
+
+func TestFake_NewTimer__should_reset_timer(t *testing.T) {
+	c := NewFake(time.Time{})
+	timer := c.NewTimer(time.Second)
+
+	timer.Reset(2 * time.Second)
+	c.Advance(time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer should not fire")
+	default:
+	}
+
+	c.Advance(time.Second)
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("timer should fire")
+	}
+}
+
+// AfterFunc
+
+func TestFake_AfterFunc__should_call_functions_in_order(t *testing.T) {
+	c := NewFake(time.Time{})
+	start := c.Now()
+
+	var calls []time.Duration
+	c.AfterFunc(3*time.Second, func() { calls = append(calls, c.Since(start)) })
+	c.AfterFunc(1*time.Second, func() { calls = append(calls, c.Since(start)) })
+	c.AfterFunc(2*time.Second, func() { calls = append(calls, c.Since(start)) })
+
+	c.Advance(5 * time.Second)
+
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, calls)
+	assert.Equal(t, 5*time.Second, c.Since(start))
+}
+
+func TestFake_AfterFunc__should_schedule_timers_from_function(t *testing.T) {
+	c := NewFake(time.Time{})
+
+	n := 0
+	var fn func()
+	fn = func() {
+		n++
+		c.AfterFunc(time.Second, fn)
+	}
+	c.AfterFunc(time.Second, fn)
+
+	c.Advance(3 * time.Second)
+	assert.Equal(t, 3, n)
+}
+
+// Ticker
+
+func TestFake_NewTicker__should_tick_every_period(t *testing.T) {
+	c := NewFake(time.Time{})
+	ticker := c.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		c.Advance(time.Second)
+
+		select {
+		case <-ticker.C():
+		default:
+			t.Fatal("ticker should tick")
+		}
+	}
+}
+
+func TestFake_NewTicker__should_drop_ticks_for_slow_receivers(t *testing.T) {
+	c := NewFake(time.Time{})
+	ticker := c.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	c.Advance(5 * time.Second)
+
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Fatal("ticks should be dropped")
+	default:
+	}
+}
+
+// Sleep
+
+func TestFake_Sleep__should_sleep_until_advanced(t *testing.T) {
+	c := NewFake(time.Time{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		c.Sleep(time.Second)
+	}()
+
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sleep should end")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package clockwork
+
+import "time"
+
+var defaultClock Clock = realClock{}
+
+type realClock struct{}
+
+// Now returns the current time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t.
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// Until returns the duration until t.
+func (realClock) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+// Sleep pauses the current goroutine for at least the duration.
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// After waits for the duration to elapse and then sends the current time on the channel.
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// AfterFunc waits for the duration to elapse and then calls fn.
+func (realClock) AfterFunc(d time.Duration, fn func()) Timer {
+	t := time.AfterFunc(d, fn)
+	return realTimer{t}
+}
+
+// NewTimer returns a new timer which sends the current time on its channel after the duration.
+func (realClock) NewTimer(d time.Duration) Timer {
+	t := time.NewTimer(d)
+	return realTimer{t}
+}
+
+// NewTicker returns a new ticker which sends the current time on its channel every period.
+func (realClock) NewTicker(d time.Duration) Ticker {
+	t := time.NewTicker(d)
+	return realTicker{t}
+}
+
+// timer
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time        { return t.t.C }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+
+// ticker
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time   { return t.t.C }
+func (t realTicker) Reset(d time.Duration) { t.t.Reset(d) }
+func (t realTicker) Stop()                 { t.t.Stop() }
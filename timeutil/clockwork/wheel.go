@@ -0,0 +1,357 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package clockwork
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Wheel is a hashed timer wheel clock, which trades timer precision for cheap timers.
+//
+// The wheel is a ring of slots, each slot holds a list of timers, a background goroutine
+// advances the wheel every resolution tick and fires the due timers. Adding and stopping
+// a timer is O(1) and does not use the runtime timer heap, which makes the wheel suitable
+// for many short-lived timeouts which are usually cancelled, i.e. RPC deadlines.
+//
+// Timers fire at most one resolution late. AfterFunc functions are called in their own
+// goroutines as in [time.AfterFunc], so slow functions do not delay other timers.
+// Tickers use the runtime.
+//
+// Example:
+//
+//	wheel := clockwork.NewWheel(time.Millisecond, 0)
+//	defer wheel.Stop()
+//
+//	ctx := async.NextTimeoutContextWith(wheel, parent, time.Second)
+//	defer ctx.Free()
+type Wheel interface {
+	Clock
+
+	// Resolution returns the wheel tick duration.
+	Resolution() time.Duration
+
+	// Stop stops the wheel, pending timers never fire.
+	// Adding or resetting timers after Stop panics.
+	Stop()
+}
+
+// NewWheel returns a new started timer wheel with a resolution and a number of slots,
+// the number of slots is rounded up to a power of two, zero means the default.
+func NewWheel(resolution time.Duration, slots int) Wheel {
+	return newWheel(resolution, slots)
+}
+
+// internal
+
+const wheelDefaultSlots = 512
+
+var _ Wheel = (*wheel)(nil)
+
+type wheel struct {
+	res   time.Duration
+	start time.Time
+	slots []wheelSlot
+	mask  int64
+
+	tick  atomic.Int64  // last processed tick
+	fired []*wheelTimer // reused by wheel goroutine
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+type wheelSlot struct {
+	mu   sync.Mutex
+	head *wheelTimer
+}
+
+func newWheel(resolution time.Duration, slots int) *wheel {
+	if resolution <= 0 {
+		panic("non-positive wheel resolution")
+	}
+	if slots <= 0 {
+		slots = wheelDefaultSlots
+	}
+
+	// Round slots to power of two
+	n := 1
+	for n < slots {
+		n <<= 1
+	}
+
+	w := &wheel{
+		res:   resolution,
+		start: time.Now(),
+		slots: make([]wheelSlot, n),
+		mask:  int64(n - 1),
+
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go w.run()
+	return w
+}
+
+// Now returns the current time.
+func (w *wheel) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t.
+func (w *wheel) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// Until returns the duration until t.
+func (w *wheel) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+// Sleep pauses the current goroutine for at least the duration.
+func (w *wheel) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-w.After(d)
+}
+
+// After waits for the duration to elapse and then sends the current time on the channel.
+func (w *wheel) After(d time.Duration) <-chan time.Time {
+	return w.NewTimer(d).C()
+}
+
+// AfterFunc waits for the duration to elapse and then calls fn.
+func (w *wheel) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &wheelTimer{w: w, fn: fn}
+	w.add(t, d)
+	return t
+}
+
+// NewTimer returns a new timer which sends the current time on its channel after the duration.
+func (w *wheel) NewTimer(d time.Duration) Timer {
+	t := &wheelTimer{w: w, ch: make(chan time.Time, 1)}
+	w.add(t, d)
+	return t
+}
+
+// NewTicker returns a new ticker which sends the current time on its channel every period.
+// Tickers use the runtime.
+func (w *wheel) NewTicker(d time.Duration) Ticker {
+	return defaultClock.NewTicker(d)
+}
+
+// Resolution returns the wheel tick duration.
+func (w *wheel) Resolution() time.Duration {
+	return w.res
+}
+
+// Stop stops the wheel, pending timers never fire.
+// Adding or resetting timers after Stop panics.
+func (w *wheel) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// internal
+
+// add adds a timer to the slot of its deadline tick, panics if the wheel is stopped.
+func (w *wheel) add(t *wheelTimer, d time.Duration) {
+	// Timers added after stop would never fire
+	select {
+	case <-w.stop:
+		panic("timer wheel stopped")
+	default:
+	}
+
+	elapsed := time.Since(w.start) + d
+	deadline := int64((elapsed + w.res - 1) / w.res)
+
+	for {
+		// Schedule timer to the next tick when it has already passed
+		tick := w.tick.Load()
+		if deadline <= tick {
+			deadline = tick + 1
+		}
+
+		s := &w.slots[deadline&w.mask]
+		s.mu.Lock()
+
+		// Retry if the wheel has processed the tick concurrently
+		if w.tick.Load() >= deadline {
+			s.mu.Unlock()
+			continue
+		}
+
+		t.deadline = deadline
+		t.slot.Store(s)
+		s.push(t)
+		s.mu.Unlock()
+		return
+	}
+}
+
+// remove removes a timer, returns false if the timer is not active.
+func (w *wheel) remove(t *wheelTimer) bool {
+	for {
+		s := t.slot.Load()
+		if s == nil {
+			return false
+		}
+
+		s.mu.Lock()
+		if t.slot.Load() != s {
+			s.mu.Unlock()
+			continue
+		}
+
+		s.remove(t)
+		t.slot.Store(nil)
+		s.mu.Unlock()
+		return true
+	}
+}
+
+// run advances the wheel every tick.
+func (w *wheel) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.res)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			target := int64(now.Sub(w.start) / w.res)
+			for tick := w.tick.Load() + 1; tick <= target; tick++ {
+				w.process(tick)
+			}
+		}
+	}
+}
+
+// process fires due timers in the slot of a tick.
+func (w *wheel) process(tick int64) {
+	w.tick.Store(tick)
+	s := &w.slots[tick&w.mask]
+
+	// Collect due timers
+	s.mu.Lock()
+	for t := s.head; t != nil; {
+		next := t.next
+		if t.deadline <= tick {
+			s.remove(t)
+			t.slot.Store(nil)
+			w.fired = append(w.fired, t)
+		}
+		t = next
+	}
+	s.mu.Unlock()
+
+	// Fire outside of lock
+	for i, t := range w.fired {
+		t.fire()
+		w.fired[i] = nil
+	}
+	w.fired = w.fired[:0]
+}
+
+// slot
+
+func (s *wheelSlot) push(t *wheelTimer) {
+	t.prev = nil
+	t.next = s.head
+	if s.head != nil {
+		s.head.prev = t
+	}
+	s.head = t
+}
+
+func (s *wheelSlot) remove(t *wheelTimer) {
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		s.head = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+
+	t.prev = nil
+	t.next = nil
+}
+
+// timer
+
+var _ Timer = (*wheelTimer)(nil)
+
+type wheelTimer struct {
+	w  *wheel
+	ch chan time.Time // nil for AfterFunc
+	fn func()         // nil for timers
+
+	slot atomic.Pointer[wheelSlot] // nil when inactive
+
+	// guarded by slot mutex
+	deadline int64
+	prev     *wheelTimer
+	next     *wheelTimer
+}
+
+// C returns the timer channel, nil for AfterFunc timers.
+func (t *wheelTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+// Reset changes the timer to expire after the duration,
+// returns true if the timer had been active.
+func (t *wheelTimer) Reset(d time.Duration) bool {
+	active := t.w.remove(t)
+	t.drain()
+
+	t.w.add(t, d)
+	return active
+}
+
+// Stop prevents the timer from firing, returns true if the timer had been active.
+func (t *wheelTimer) Stop() bool {
+	active := t.w.remove(t)
+	t.drain()
+	return active
+}
+
+// private
+
+// fire sends the time or calls the function in a new goroutine,
+// so that it does not block the wheel goroutine.
+func (t *wheelTimer) fire() {
+	if t.fn != nil {
+		go t.fn()
+		return
+	}
+
+	select {
+	case t.ch <- time.Now():
+	default:
+	}
+}
+
+func (t *wheelTimer) drain() {
+	if t.ch == nil {
+		return
+	}
+
+	select {
+	case <-t.ch:
+	default:
+	}
+}
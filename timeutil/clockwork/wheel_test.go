@@ -0,0 +1,163 @@
+// Copyright 2025 Ivan Korobkov. All rights reserved.
+// Use of this software is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package clockwork
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheel_AfterFunc__should_call_function_after_duration(t *testing.T) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+
+	start := time.Now()
+	done := make(chan time.Duration, 1)
+	w.AfterFunc(10*time.Millisecond, func() {
+		done <- time.Since(start)
+	})
+
+	select {
+	case d := <-done:
+		assert.GreaterOrEqual(t, d, 10*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+}
+
+func TestWheel_AfterFunc__should_fire_timers_longer_than_wheel(t *testing.T) {
+	w := NewWheel(time.Millisecond, 4)
+	defer w.Stop()
+
+	start := time.Now()
+	done := make(chan time.Duration, 1)
+	w.AfterFunc(10*time.Millisecond, func() {
+		done <- time.Since(start)
+	})
+
+	select {
+	case d := <-done:
+		assert.GreaterOrEqual(t, d, 10*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+}
+
+func TestWheel_AfterFunc__should_not_block_other_timers(t *testing.T) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+
+	block := make(chan struct{})
+	defer close(block)
+	w.AfterFunc(time.Millisecond, func() {
+		<-block
+	})
+
+	done := make(chan struct{})
+	w.AfterFunc(5*time.Millisecond, func() {
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timer blocked by another timer function")
+	}
+}
+
+func TestWheel_NewTimer__should_send_time(t *testing.T) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+
+	timer := w.NewTimer(5 * time.Millisecond)
+
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+
+	ok := timer.Stop()
+	assert.False(t, ok)
+}
+
+func TestWheel_Stop__should_stop_timer(t *testing.T) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+
+	var fired atomic.Bool
+	timer := w.AfterFunc(5*time.Millisecond, func() {
+		fired.Store(true)
+	})
+
+	ok := timer.Stop()
+	assert.True(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, fired.Load())
+}
+
+func TestWheel_AfterFunc__should_panic_when_wheel_stopped(t *testing.T) {
+	w := NewWheel(time.Millisecond, 0)
+	timer := w.NewTimer(time.Hour)
+	w.Stop()
+
+	assert.Panics(t, func() {
+		w.AfterFunc(time.Millisecond, func() {})
+	})
+	assert.Panics(t, func() {
+		w.Sleep(time.Millisecond)
+	})
+	assert.Panics(t, func() {
+		timer.Reset(time.Millisecond)
+	})
+}
+
+func TestWheel_Reset__should_reschedule_timer(t *testing.T) {
+	w := NewWheel(time.Millisecond, 0)
+	defer w.Stop()
+
+	timer := w.NewTimer(time.Hour)
+	ok := timer.Reset(5 * time.Millisecond)
+	assert.True(t, ok)
+
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+}
+
+func TestWheel__should_fire_concurrent_timers(t *testing.T) {
+	w := NewWheel(time.Millisecond, 64)
+	defer w.Stop()
+
+	n := 1000
+	wg := sync.WaitGroup{}
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			d := time.Duration(i%20) * time.Millisecond
+			w.AfterFunc(d, wg.Done)
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timers did not fire")
+	}
+}